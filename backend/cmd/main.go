@@ -1,53 +1,363 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 
-	"github.com/gin-gonic/gin"
+	"github.com/guiver/config"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/handlers"
+	"github.com/guiver/internal/delivery/http/pagination"
+	"github.com/guiver/internal/delivery/http/router"
+	"github.com/guiver/internal/domain/models"
+	domain "github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/exchange"
+	"github.com/guiver/internal/exchange/static"
+	"github.com/guiver/internal/identity"
+	fakeidentity "github.com/guiver/internal/identity/fake"
+	"github.com/guiver/internal/identity/firebaseauth"
+	"github.com/guiver/internal/identity/jwtauth"
+	statictokens "github.com/guiver/internal/identity/static"
+	"github.com/guiver/internal/infrastructure/firestore"
+	"github.com/guiver/internal/infrastructure/memory"
+	"github.com/guiver/internal/infrastructure/repository"
+	"github.com/guiver/internal/infrastructure/sqlstore"
+	"github.com/guiver/internal/payments"
+	"github.com/guiver/internal/payments/fake"
+	"github.com/guiver/pkg/firebase"
 	"github.com/joho/godotenv"
 )
 
+// repositories agrupa las implementaciones de los repositorios del dominio
+type repositories struct {
+	guivers  domain.GuiverRepository
+	causes   domain.CauseRepository
+	products domain.ProductRepository
+	pledges  domain.PledgeRepository
+	orders   domain.OrderRepository
+	ledger   domain.LedgerRepository
+	roles    domain.RoleRepository
+	close    func() error
+}
+
+// searchReindexer lo implementan los repositorios cuyo índice de búsqueda se
+// puede reconstruir a partir de los datos guardados
+type searchReindexer interface {
+	ReindexSearch(ctx context.Context) error
+}
+
+// updateMigrator lo implementan los repositorios que guardaban las
+// actualizaciones dentro de cada Causa y pueden moverlas a su propio almacén
+type updateMigrator interface {
+	MigrateUpdates(ctx context.Context) error
+}
+
+// priceMigrator lo implementan los repositorios que pueden guardar precios de
+// Productos anteriores a Money, sin moneda
+type priceMigrator interface {
+	MigratePrices(ctx context.Context, currency string) error
+}
+
 func main() {
+	reindex := flag.Bool("reindex-search", false, "rebuild the search index of causes and products and exit")
+	migrateUpdates := flag.Bool("migrate-updates", false, "move cause updates stored inside each cause to their own collection and exit")
+	migratePrices := flag.Bool("migrate-prices", false, "assign PAYMENT_CURRENCY to product prices stored without currency and exit")
+	grantAdmin := flag.String("grant-admin", "", "assign the admin role to the guiver with this ID and exit")
+	flag.Parse()
+
 	// Cargar variables de entorno
 	if err := godotenv.Load(); err != nil {
 		log.Printf("Warning: .env file not found")
 	}
 
-	// Crear el router de Gin
-	r := gin.Default()
+	cfg := config.LoadConfig()
 
-	// Configurar CORS
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	// Inicializar Firebase, si algo lo usa
+	if usesFirebase(cfg) {
+		if err := firebase.InitFirebase(cfg.Firebase.CredentialsFile); err != nil {
+			log.Fatalf("Error initializing Firebase: %v", err)
 		}
+	}
 
-		c.Next()
-	})
+	// Repositorios
+	repos, err := newRepositories(cfg)
+	if err != nil {
+		log.Fatalf("Error creating repositories: %v", err)
+	}
+	defer repos.close()
 
-	// Ruta de prueba
-	r.GET("/api/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
+	if *reindex {
+		reindexSearch(repos)
+		return
+	}
+	if *migrateUpdates {
+		migrateCauseUpdates(repos)
+		return
+	}
+	if *migratePrices {
+		migrateProductPrices(repos, cfg.Payments.Currency)
+		return
+	}
+	if *grantAdmin != "" {
+		grantAdminRole(repos, *grantAdmin)
+		return
+	}
+
+	// Pasarela de pagos
+	provider, err := newPaymentProvider(cfg)
+	if err != nil {
+		log.Fatalf("Error creating payment provider: %v", err)
+	}
 
-	// Obtener el puerto del entorno o usar 8080 por defecto
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Cotizaciones
+	rates, err := newExchangeSource(cfg)
+	if err != nil {
+		log.Fatalf("Error creating exchange rate source: %v", err)
 	}
 
+	// Proveedor de cuentas
+	accounts, err := newIdentityProvider(cfg)
+	if err != nil {
+		log.Fatalf("Error creating identity provider: %v", err)
+	}
+	verifier, err := newTokenVerifier(cfg, accounts)
+	if err != nil {
+		log.Fatalf("Error creating token verifier: %v", err)
+	}
+
+	// Permisos
+	policy := authz.NewPolicy(repos.roles)
+
+	// Handlers
+	cursors := pagination.NewCursorCodec(cursorSecret(cfg))
+	guiverHandler := handlers.NewGuiverHandler(repos.guivers, policy)
+	causeHandler := handlers.NewCauseHandler(repos.causes, repos.guivers, policy, cursors)
+	productHandler := handlers.NewProductHandler(repos.products, repos.causes, rates, cfg.Payments.Currency, policy, cursors)
+	pledgeHandler := handlers.NewPledgeHandler(repos.pledges, repos.causes, repos.ledger, provider, policy, cursors)
+	orderHandler := handlers.NewOrderHandler(repos.orders, repos.products, repos.ledger, provider, cursors)
+	ledgerHandler := handlers.NewLedgerHandler(repos.ledger, repos.causes, policy, cursors)
+	paymentHandler := handlers.NewPaymentHandler(provider, repos.pledges, repos.orders, repos.products, repos.ledger)
+	authHandler := handlers.NewAuthHandler(accounts, repos.guivers)
+	adminHandler := handlers.NewAdminHandler(repos.roles, repos.guivers, policy)
+
+	// Router
+	r := router.NewRouter(cfg, guiverHandler, causeHandler, productHandler, pledgeHandler, orderHandler, ledgerHandler, paymentHandler, authHandler, adminHandler, verifier, policy)
+	r.Setup()
+
 	// Iniciar el servidor
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
+	log.Printf("Server starting on port %d (storage: %s)", cfg.Server.Port, cfg.Storage.Driver)
+	if err := r.Run(); err != nil {
 		log.Fatalf("Error starting server: %v", err)
 	}
 }
+
+// reindexSearch reconstruye el índice de búsqueda de los repositorios que lo
+// permiten. Los demás lo mantienen siempre al día y no necesitan hacerlo.
+func reindexSearch(repos *repositories) {
+	for name, repo := range map[string]interface{}{"causes": repos.causes, "products": repos.products} {
+		reindexer, ok := repo.(searchReindexer)
+		if !ok {
+			log.Printf("Search index of %s is maintained automatically, nothing to do", name)
+			continue
+		}
+		if err := reindexer.ReindexSearch(context.Background()); err != nil {
+			log.Fatalf("Error reindexing %s: %v", name, err)
+		}
+		log.Printf("Search index of %s rebuilt", name)
+	}
+}
+
+// migrateCauseUpdates mueve las actualizaciones de las Causas a su propia
+// colección, si el repositorio las guardaba dentro de cada Causa
+func migrateCauseUpdates(repos *repositories) {
+	migrator, ok := repos.causes.(updateMigrator)
+	if !ok {
+		log.Printf("Cause updates are already stored separately, nothing to do")
+		return
+	}
+	if err := migrator.MigrateUpdates(context.Background()); err != nil {
+		log.Fatalf("Error migrating cause updates: %v", err)
+	}
+	log.Printf("Cause updates migrated")
+}
+
+// migrateProductPrices asigna la moneda indicada a los precios de Productos
+// guardados sin moneda, si el repositorio puede tenerlos
+func migrateProductPrices(repos *repositories, currency string) {
+	migrator, ok := repos.products.(priceMigrator)
+	if !ok {
+		log.Printf("Product prices already have a currency, nothing to do")
+		return
+	}
+	if err := migrator.MigratePrices(context.Background(), currency); err != nil {
+		log.Fatalf("Error migrating product prices: %v", err)
+	}
+	log.Printf("Product prices migrated to %s", currency)
+}
+
+// grantAdminRole asigna el rol de administrador a un Guiver, conservando los
+// demás roles que tenga. Sirve para crear el primer administrador, que luego
+// puede asignar roles desde /admin.
+func grantAdminRole(repos *repositories, guiverID string) {
+	ctx := context.Background()
+	roles, err := repos.roles.GetRoles(ctx, guiverID)
+	if err != nil {
+		log.Fatalf("Error getting roles of %s: %v", guiverID, err)
+	}
+	if err := repos.roles.SetRoles(ctx, guiverID, append(roles, models.RoleAdmin)); err != nil {
+		log.Fatalf("Error granting admin role to %s: %v", guiverID, err)
+	}
+	log.Printf("Admin role granted to %s", guiverID)
+}
+
+// cursorSecret devuelve la clave para firmar cursores. Sin clave configurada se
+// genera una aleatoria, por lo que los cursores dejan de valer al reiniciar.
+func cursorSecret(cfg *config.Config) []byte {
+	if cfg.Pagination.CursorSecret != "" {
+		return []byte(cfg.Pagination.CursorSecret)
+	}
+
+	log.Printf("Warning: CURSOR_SECRET not set, page cursors will not survive restarts")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Error generating cursor secret: %v", err)
+	}
+	return secret
+}
+
+// newPaymentProvider crea la pasarela de pagos configurada
+func newPaymentProvider(cfg *config.Config) (payments.Provider, error) {
+	switch cfg.Payments.Provider {
+	case config.PaymentProviderFake:
+		secret := []byte(cfg.Payments.WebhookSecret)
+		if len(secret) == 0 {
+			log.Printf("Warning: PAYMENT_WEBHOOK_SECRET not set, using a random one")
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				return nil, err
+			}
+		}
+		return fake.New(secret, cfg.Payments.CheckoutURL), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Payments.Provider)
+	}
+}
+
+// newIdentityProvider crea el proveedor de cuentas configurado
+func newIdentityProvider(cfg *config.Config) (identity.Provider, error) {
+	switch cfg.Auth.Provider {
+	case config.AuthProviderFirebase:
+		return firebaseauth.New(firebase.GetAuthClient(), cfg.Auth.FirebaseAPIKey)
+	case config.AuthProviderFake:
+		return fakeidentity.New(cfg.Auth.FakeVerifyEmails), nil
+	default:
+		return nil, fmt.Errorf("unknown identity provider %q", cfg.Auth.Provider)
+	}
+}
+
+// newTokenVerifier crea el verificador de tokens configurado. El estático
+// también acepta los tokens que emite el proveedor de cuentas falso.
+func newTokenVerifier(cfg *config.Config, accounts identity.Provider) (identity.TokenVerifier, error) {
+	switch cfg.Auth.Verifier {
+	case config.TokenVerifierFirebase:
+		return firebaseauth.NewVerifier(firebase.GetAuthClient())
+	case config.TokenVerifierJWT:
+		options := jwtauth.Options{Issuer: cfg.Auth.JWT.Issuer, Audience: cfg.Auth.JWT.Audience}
+		switch cfg.Auth.JWT.Algorithm {
+		case jwtauth.HS256:
+			return jwtauth.NewHMAC([]byte(cfg.Auth.JWT.Secret), options)
+		case jwtauth.RS256:
+			key, err := os.ReadFile(cfg.Auth.JWT.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			return jwtauth.NewRSA(key, options)
+		default:
+			return nil, fmt.Errorf("unknown JWT algorithm %q", cfg.Auth.JWT.Algorithm)
+		}
+	case config.TokenVerifierStatic:
+		log.Printf("Warning: using static tokens, never use TOKEN_VERIFIER=static in production")
+		verifier := statictokens.New()
+		if fakeAccounts, ok := accounts.(*fakeidentity.Provider); ok {
+			verifier = fakeAccounts.Verifier()
+		}
+		if err := verifier.Load(cfg.Auth.StaticTokens); err != nil {
+			return nil, err
+		}
+		return verifier, nil
+	default:
+		return nil, fmt.Errorf("unknown token verifier %q", cfg.Auth.Verifier)
+	}
+}
+
+// usesFirebase indica si el almacenamiento, las cuentas o la verificación de
+// tokens configurados usan Firebase
+func usesFirebase(cfg *config.Config) bool {
+	return cfg.Storage.Driver == config.StorageFirestore ||
+		cfg.Auth.Provider == config.AuthProviderFirebase ||
+		cfg.Auth.Verifier == config.TokenVerifierFirebase
+}
+
+// newExchangeSource crea el origen de cotizaciones configurado
+func newExchangeSource(cfg *config.Config) (exchange.Source, error) {
+	switch cfg.Exchange.Source {
+	case config.ExchangeSourceStatic:
+		return static.New(cfg.Exchange.RatesFile)
+	default:
+		return nil, fmt.Errorf("unknown exchange rate source %q", cfg.Exchange.Source)
+	}
+}
+
+// newRepositories crea los repositorios según el driver de almacenamiento configurado
+func newRepositories(cfg *config.Config) (*repositories, error) {
+	switch cfg.Storage.Driver {
+	case config.StorageFirestore:
+		db, err := firestore.NewClient(firebase.GetApp())
+		if err != nil {
+			return nil, err
+		}
+		return &repositories{
+			guivers:  repository.NewGuiverRepository(db),
+			causes:   repository.NewCauseRepository(db),
+			products: repository.NewProductRepository(db),
+			pledges:  repository.NewPledgeRepository(db),
+			orders:   repository.NewOrderRepository(db),
+			ledger:   repository.NewLedgerRepository(db),
+			roles:    repository.NewRoleRepository(db),
+			close:    db.Close,
+		}, nil
+	case config.StorageMemory:
+		causes := memory.NewCauseRepository()
+		return &repositories{
+			guivers:  memory.NewGuiverRepository(),
+			causes:   causes,
+			products: memory.NewProductRepository(),
+			pledges:  memory.NewPledgeRepository(causes),
+			orders:   memory.NewOrderRepository(),
+			ledger:   memory.NewLedgerRepository(),
+			roles:    memory.NewRoleRepository(),
+			close:    func() error { return nil },
+		}, nil
+	case config.StorageSQLite, config.StoragePostgres:
+		db, err := sqlstore.Open(context.Background(), sqlstore.Dialect(cfg.Storage.Driver), cfg.Storage.DSN)
+		if err != nil {
+			return nil, err
+		}
+		return &repositories{
+			guivers:  sqlstore.NewGuiverRepository(db),
+			causes:   sqlstore.NewCauseRepository(db),
+			products: sqlstore.NewProductRepository(db),
+			pledges:  sqlstore.NewPledgeRepository(db),
+			orders:   sqlstore.NewOrderRepository(db),
+			ledger:   sqlstore.NewLedgerRepository(db),
+			roles:    sqlstore.NewRoleRepository(db),
+			close:    db.Close,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
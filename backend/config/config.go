@@ -7,9 +7,14 @@ import (
 
 // Config contiene la configuración de la aplicación
 type Config struct {
-	Server   ServerConfig
-	Firebase FirebaseConfig
-	Cors     CorsConfig
+	Server     ServerConfig
+	Firebase   FirebaseConfig
+	Auth       AuthConfig
+	Storage    StorageConfig
+	Pagination PaginationConfig
+	Payments   PaymentsConfig
+	Exchange   ExchangeConfig
+	Cors       CorsConfig
 }
 
 // ServerConfig contiene la configuración del servidor
@@ -23,6 +28,82 @@ type FirebaseConfig struct {
 	CredentialsFile string
 }
 
+// Proveedores de cuentas soportados
+const (
+	AuthProviderFirebase = "firebase"
+	AuthProviderFake     = "fake"
+)
+
+// Verificadores de tokens soportados
+const (
+	TokenVerifierFirebase = "firebase"
+	TokenVerifierJWT      = "jwt"
+	TokenVerifierStatic   = "static"
+)
+
+// AuthConfig contiene la configuración del registro, el inicio de sesión y la
+// verificación de los tokens de las rutas protegidas
+type AuthConfig struct {
+	Provider         string // firebase, fake
+	FirebaseAPIKey   string // clave web del proyecto, para iniciar sesión con la API REST de Firebase Auth
+	FakeVerifyEmails bool   // el proveedor falso crea las cuentas con el email ya verificado
+	Verifier         string // firebase, jwt, static
+	JWT              JWTConfig
+	StaticTokens     string // tokens del verificador estático, "token=uid,token=uid"
+}
+
+// JWTConfig contiene la configuración del verificador de tokens JWT propios
+type JWTConfig struct {
+	Algorithm     string // HS256, RS256
+	Secret        string // secreto de HS256
+	PublicKeyFile string // clave pública PEM del emisor para RS256
+	Issuer        string // si no está vacío, emisor que deben tener los tokens
+	Audience      string // si no está vacío, audiencia que deben incluir los tokens
+}
+
+// Drivers de almacenamiento soportados
+const (
+	StorageFirestore = "firestore"
+	StorageMemory    = "memory"
+	StorageSQLite    = "sqlite"
+	StoragePostgres  = "postgres"
+)
+
+// StorageConfig contiene la configuración del backend de almacenamiento
+type StorageConfig struct {
+	Driver string // firestore, memory, sqlite, postgres
+	DSN    string // cadena de conexión para sqlite y postgres
+}
+
+// PaginationConfig contiene la configuración de la paginación
+type PaginationConfig struct {
+	CursorSecret string // clave HMAC para firmar los cursores; si está vacía se genera una al iniciar
+}
+
+// Pasarelas de pago soportadas
+const (
+	PaymentProviderFake = "fake"
+)
+
+// PaymentsConfig contiene la configuración de la pasarela de pagos
+type PaymentsConfig struct {
+	Provider      string // fake
+	WebhookSecret string // clave para verificar las notificaciones; si está vacía la pasarela falsa genera una al iniciar
+	CheckoutURL   string // URL base de las páginas de pago de la pasarela falsa
+	Currency      string // moneda por defecto de los precios de los productos que no indican otra
+}
+
+// Orígenes de cotizaciones soportados
+const (
+	ExchangeSourceStatic = "static"
+)
+
+// ExchangeConfig contiene la configuración de las cotizaciones entre monedas
+type ExchangeConfig struct {
+	Source    string // static
+	RatesFile string // archivo JSON con las cotizaciones del origen estático
+}
+
 // CorsConfig contiene la configuración de CORS
 type CorsConfig struct {
 	AllowOrigins []string
@@ -39,6 +120,37 @@ func LoadConfig() *Config {
 		Firebase: FirebaseConfig{
 			CredentialsFile: getEnv("FIREBASE_CREDENTIALS", "config/serviceAccountKey.json"),
 		},
+		Auth: AuthConfig{
+			Provider:         getEnv("AUTH_PROVIDER", AuthProviderFirebase),
+			FirebaseAPIKey:   getEnv("FIREBASE_API_KEY", ""),
+			FakeVerifyEmails: getEnvAsBool("FAKE_AUTH_VERIFY_EMAILS", true),
+			Verifier:         getEnv("TOKEN_VERIFIER", TokenVerifierFirebase),
+			JWT: JWTConfig{
+				Algorithm:     getEnv("JWT_ALGORITHM", "HS256"),
+				Secret:        getEnv("JWT_SECRET", ""),
+				PublicKeyFile: getEnv("JWT_PUBLIC_KEY_FILE", ""),
+				Issuer:        getEnv("JWT_ISSUER", ""),
+				Audience:      getEnv("JWT_AUDIENCE", ""),
+			},
+			StaticTokens: getEnv("STATIC_TOKENS", ""),
+		},
+		Storage: StorageConfig{
+			Driver: getEnv("STORAGE_DRIVER", StorageFirestore),
+			DSN:    getEnv("STORAGE_DSN", "guiver.db"),
+		},
+		Pagination: PaginationConfig{
+			CursorSecret: getEnv("CURSOR_SECRET", ""),
+		},
+		Payments: PaymentsConfig{
+			Provider:      getEnv("PAYMENT_PROVIDER", PaymentProviderFake),
+			WebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+			CheckoutURL:   getEnv("FAKE_CHECKOUT_URL", "http://localhost:3000/checkout"),
+			Currency:      getEnv("PAYMENT_CURRENCY", "ARS"),
+		},
+		Exchange: ExchangeConfig{
+			Source:    getEnv("EXCHANGE_RATES_SOURCE", ExchangeSourceStatic),
+			RatesFile: getEnv("EXCHANGE_RATES_FILE", "config/exchange_rates.json"),
+		},
 		Cors: CorsConfig{
 			AllowOrigins: []string{"http://localhost:3000", "https://guiver-84885.web.app"},
 			AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
@@ -62,3 +174,12 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
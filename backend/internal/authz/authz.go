@@ -0,0 +1,204 @@
+// Package authz decide qué puede hacer cada Guiver. En lugar de comparar el
+// dueño de cada recurso en cada handler, cada acción tiene una regla: la puede
+// hacer el dueño del recurso, sus colaboradores o quien tenga alguno de los
+// roles indicados.
+//
+// Los roles de un Guiver son RoleGuiver, los del claim "roles" de su token
+// (custom claims de Firebase o del emisor de los JWT) y los asignados en el
+// repository.RoleRepository.
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// ErrDenied indica que el Guiver no puede hacer la acción
+var ErrDenied = errors.New("action not allowed")
+
+// RolesClaim es el claim del token con los roles de la cuenta
+const RolesClaim = "roles"
+
+// Action es una acción sujeta a permisos
+type Action string
+
+const (
+	ActionUpdateCause         Action = "cause:update"
+	ActionDeleteCause         Action = "cause:delete"
+	ActionManageCollaborators Action = "cause:collaborators"
+	ActionPostUpdate          Action = "cause:post-update"
+	ActionPinUpdate           Action = "cause:pin-update"
+	ActionManageFunds         Action = "cause:funds"    // rendir gastos y reembolsar aportes
+	ActionDisburseFunds       Action = "cause:disburse" // registrar entregas de fondos
+	ActionEditUpdate          Action = "update:edit"
+	ActionDeleteUpdate        Action = "update:delete"
+	ActionEditComment         Action = "comment:edit"
+	ActionDeleteComment       Action = "comment:delete"
+	ActionUpdateProduct       Action = "product:update"
+	ActionDeleteProduct       Action = "product:delete"
+	ActionUpdateGuiver        Action = "guiver:update"
+	ActionDeleteGuiver        Action = "guiver:delete"
+	ActionManageRoles         Action = "roles:manage"
+)
+
+// rule dice quiénes pueden hacer una acción
+type rule struct {
+	owner         bool // el dueño del recurso
+	collaborators bool // sus colaboradores
+	// el autor del recurso mientras siga siendo colaborador
+	collaboratingAuthor bool
+	// si no está vacío, el dueño además necesita alguno de estos roles
+	ownerRoles []models.Role
+	// quienes tengan alguno de estos roles, sin importar de quién sea el recurso
+	roles []models.Role
+}
+
+var rules = map[Action]rule{
+	ActionUpdateCause:         {owner: true, roles: []models.Role{models.RoleModerator, models.RoleAdmin}},
+	ActionDeleteCause:         {owner: true, roles: []models.Role{models.RoleModerator, models.RoleAdmin}},
+	ActionManageCollaborators: {owner: true, roles: []models.Role{models.RoleAdmin}},
+	ActionPostUpdate:          {owner: true, collaborators: true, roles: []models.Role{models.RoleAdmin}},
+	ActionPinUpdate:           {owner: true, collaborators: true, roles: []models.Role{models.RoleAdmin}},
+	ActionManageFunds:         {owner: true, roles: []models.Role{models.RoleAdmin}},
+	ActionDisburseFunds:       {owner: true, ownerRoles: []models.Role{models.RoleOrganizer}, roles: []models.Role{models.RoleAdmin}},
+	ActionEditUpdate:          {owner: true, collaboratingAuthor: true, roles: []models.Role{models.RoleAdmin}},
+	ActionDeleteUpdate:        {owner: true, collaboratingAuthor: true, roles: []models.Role{models.RoleModerator, models.RoleAdmin}},
+	ActionEditComment:         {owner: true},
+	ActionDeleteComment:       {owner: true, roles: []models.Role{models.RoleModerator, models.RoleAdmin}},
+	ActionUpdateProduct:       {owner: true, roles: []models.Role{models.RoleModerator, models.RoleAdmin}},
+	ActionDeleteProduct:       {owner: true, roles: []models.Role{models.RoleModerator, models.RoleAdmin}},
+	ActionUpdateGuiver:        {owner: true, roles: []models.Role{models.RoleAdmin}},
+	ActionDeleteGuiver:        {owner: true, roles: []models.Role{models.RoleAdmin}},
+	ActionManageRoles:         {roles: []models.Role{models.RoleAdmin}},
+}
+
+// Subject es quien intenta hacer una acción: el Guiver autenticado y los
+// claims de su token
+type Subject struct {
+	ID     string
+	Claims map[string]interface{}
+}
+
+// Resource describe el recurso sobre el que se hace una acción
+type Resource struct {
+	OwnerID       string
+	Collaborators []string
+	AuthorID      string
+}
+
+// Owned describe un recurso que solo tiene dueño, como un producto, un
+// comentario, cuyo dueño es su autor, o un Guiver, que es dueño de sí mismo
+func Owned(ownerID string) Resource {
+	return Resource{OwnerID: ownerID}
+}
+
+// CauseResource describe una causa
+func CauseResource(cause *models.Cause) Resource {
+	return Resource{OwnerID: cause.GuiverID, Collaborators: cause.Collaborators}
+}
+
+// UpdateResource describe una actualización de una causa: pertenece al dueño
+// de la causa y la escribió quien la publicó
+func UpdateResource(cause *models.Cause, update *models.Update) Resource {
+	resource := CauseResource(cause)
+	resource.AuthorID = update.GuiverID
+	return resource
+}
+
+func (r Resource) isCollaborator(guiverID string) bool {
+	for _, id := range r.Collaborators {
+		if id == guiverID {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy aplica las reglas de permisos
+type Policy struct {
+	roles repository.RoleRepository
+}
+
+// NewPolicy crea una política que lee los roles asignados de roles
+func NewPolicy(roles repository.RoleRepository) *Policy {
+	return &Policy{roles: roles}
+}
+
+// Roles devuelve RoleGuiver seguido de los demás roles del Guiver, ordenados:
+// los del token (los desconocidos se ignoran) y los asignados
+func (p *Policy) Roles(ctx context.Context, subject Subject) ([]models.Role, error) {
+	assigned, err := p.roles.GetRoles(ctx, subject.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := append([]models.Role{}, assigned...)
+	claimed, _ := subject.Claims[RolesClaim].([]interface{})
+	for _, claim := range claimed {
+		name, _ := claim.(string)
+		if role := models.Role(name); role.Valid() {
+			roles = append(roles, role)
+		}
+	}
+	normalized, err := models.NormalizeRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.Role{models.RoleGuiver}, normalized...), nil
+}
+
+// HasRole indica si el Guiver tiene alguno de los roles
+func (p *Policy) HasRole(ctx context.Context, subject Subject, roles ...models.Role) (bool, error) {
+	if subject.ID == "" {
+		return false, nil
+	}
+	have, err := p.Roles(ctx, subject)
+	if err != nil {
+		return false, err
+	}
+	return hasAny(have, roles), nil
+}
+
+// Authorize verifica que el Guiver pueda hacer la acción sobre el recurso.
+// Falla con ErrDenied si no puede, o con el error del repositorio si no se
+// pudieron leer sus roles. Los roles solo se leen si la regla los necesita.
+func (p *Policy) Authorize(ctx context.Context, subject Subject, action Action, resource Resource) error {
+	rule, ok := rules[action]
+	if !ok || subject.ID == "" {
+		return ErrDenied
+	}
+
+	owner := rule.owner && resource.OwnerID == subject.ID
+	related := owner ||
+		(rule.collaborators && resource.isCollaborator(subject.ID)) ||
+		(rule.collaboratingAuthor && resource.AuthorID == subject.ID && resource.isCollaborator(subject.ID))
+	if related && (!owner || len(rule.ownerRoles) == 0) {
+		return nil
+	}
+
+	if !owner && len(rule.roles) == 0 {
+		return ErrDenied
+	}
+	have, err := p.Roles(ctx, subject)
+	if err != nil {
+		return err
+	}
+	if hasAny(have, rule.roles) || (owner && hasAny(have, rule.ownerRoles)) {
+		return nil
+	}
+	return ErrDenied
+}
+
+func hasAny(have, want []models.Role) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
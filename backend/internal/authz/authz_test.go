@@ -0,0 +1,138 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guiver/internal/domain/models"
+)
+
+// roleRepository devuelve los roles asignados de un mapa y cuenta las lecturas
+type roleRepository struct {
+	roles map[string][]models.Role
+	err   error
+	reads int
+}
+
+func (r *roleRepository) GetRoles(ctx context.Context, guiverID string) ([]models.Role, error) {
+	r.reads++
+	return r.roles[guiverID], r.err
+}
+
+func (r *roleRepository) SetRoles(ctx context.Context, guiverID string, roles []models.Role) error {
+	r.roles[guiverID] = roles
+	return nil
+}
+
+func (r *roleRepository) ListByRole(ctx context.Context, role models.Role) ([]string, error) {
+	return nil, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	repo := &roleRepository{roles: map[string][]models.Role{
+		"admin":     {models.RoleAdmin},
+		"moderator": {models.RoleModerator},
+		"organizer": {models.RoleOrganizer},
+	}}
+	policy := NewPolicy(repo)
+	cause := Resource{OwnerID: "owner", Collaborators: []string{"collab", "author"}}
+	update := cause
+	update.AuthorID = "author"
+	formerAuthor := cause
+	formerAuthor.AuthorID = "former"
+	organizerCause := Resource{OwnerID: "organizer"}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		action   Action
+		resource Resource
+		allowed  bool
+	}{
+		{"Owner", Subject{ID: "owner"}, ActionUpdateCause, cause, true},
+		{"Stranger", Subject{ID: "stranger"}, ActionUpdateCause, cause, false},
+		{"Anonymous", Subject{}, ActionUpdateCause, Resource{}, false},
+		{"UnknownAction", Subject{ID: "admin"}, "cause:launch", cause, false},
+		{"ModeratorRole", Subject{ID: "moderator"}, ActionDeleteCause, cause, true},
+		{"ModeratorNotAllowed", Subject{ID: "moderator"}, ActionManageCollaborators, cause, false},
+		{"AdminRole", Subject{ID: "admin"}, ActionManageCollaborators, cause, true},
+		{"CollaboratorNotAllowed", Subject{ID: "collab"}, ActionUpdateCause, cause, false},
+		{"Collaborator", Subject{ID: "collab"}, ActionPostUpdate, cause, true},
+		{"CollaboratingAuthor", Subject{ID: "author"}, ActionEditUpdate, update, true},
+		{"CollaboratorNotAuthor", Subject{ID: "collab"}, ActionEditUpdate, update, false},
+		{"AuthorNoLongerCollaborator", Subject{ID: "former"}, ActionEditUpdate, formerAuthor, false},
+		{"OwnerEditsAnyUpdate", Subject{ID: "owner"}, ActionEditUpdate, update, true},
+		{"OwnerWithoutOwnerRole", Subject{ID: "owner"}, ActionDisburseFunds, cause, false},
+		{"OwnerWithOwnerRole", Subject{ID: "organizer"}, ActionDisburseFunds, organizerCause, true},
+		{"OwnerRoleOnOthers", Subject{ID: "organizer"}, ActionDisburseFunds, cause, false},
+		{"AdminDisburses", Subject{ID: "admin"}, ActionDisburseFunds, cause, true},
+		{"ClaimedRole", Subject{ID: "claimed", Claims: map[string]interface{}{
+			RolesClaim: []interface{}{"admin"}}}, ActionDeleteGuiver, Owned("someone"), true},
+		{"UnknownClaimIgnored", Subject{ID: "claimed", Claims: map[string]interface{}{
+			RolesClaim: []interface{}{"root", 1}}}, ActionDeleteGuiver, Owned("someone"), false},
+		{"OwnComment", Subject{ID: "owner"}, ActionEditComment, Owned("owner"), true},
+		{"AdminEditsComment", Subject{ID: "admin"}, ActionEditComment, Owned("owner"), false},
+		{"ManageRolesOnlyAdmin", Subject{ID: "owner"}, ActionManageRoles, Owned("owner"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(context.Background(), tt.subject, tt.action, tt.resource)
+			if tt.allowed && err != nil {
+				t.Errorf("Authorize = %v, want allowed", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrDenied) {
+				t.Errorf("Authorize = %v, want ErrDenied", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeReadsRolesOnlyWhenNeeded(t *testing.T) {
+	failure := errors.New("unavailable")
+	repo := &roleRepository{roles: map[string][]models.Role{}, err: failure}
+	policy := NewPolicy(repo)
+	ctx := context.Background()
+	cause := Resource{OwnerID: "owner", Collaborators: []string{"collab"}}
+
+	if err := policy.Authorize(ctx, Subject{ID: "owner"}, ActionUpdateCause, cause); err != nil {
+		t.Errorf("Authorize owner = %v", err)
+	}
+	if err := policy.Authorize(ctx, Subject{ID: "collab"}, ActionPostUpdate, cause); err != nil {
+		t.Errorf("Authorize collaborator = %v", err)
+	}
+	if err := policy.Authorize(ctx, Subject{ID: "owner"}, ActionEditComment, Owned("other")); !errors.Is(err, ErrDenied) {
+		t.Errorf("Authorize without roles = %v, want ErrDenied", err)
+	}
+	if repo.reads != 0 {
+		t.Errorf("GetRoles called %d times, want 0", repo.reads)
+	}
+
+	if err := policy.Authorize(ctx, Subject{ID: "stranger"}, ActionUpdateCause, cause); !errors.Is(err, failure) {
+		t.Errorf("Authorize = %v, want the repository error", err)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	repo := &roleRepository{roles: map[string][]models.Role{"g1": {models.RoleOrganizer}}}
+	policy := NewPolicy(repo)
+	ctx := context.Background()
+
+	subject := Subject{ID: "g1", Claims: map[string]interface{}{
+		RolesClaim: []interface{}{"moderator", "organizer", "unknown"}}}
+	roles, err := policy.Roles(ctx, subject)
+	if err != nil {
+		t.Fatalf("Roles: %v", err)
+	}
+	if want := []models.Role{models.RoleGuiver, models.RoleModerator, models.RoleOrganizer}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("Roles = %v, want %v", roles, want)
+	}
+
+	if ok, err := policy.HasRole(ctx, subject, models.RoleAdmin, models.RoleModerator); err != nil || !ok {
+		t.Errorf("HasRole = %v, %v, want true", ok, err)
+	}
+	if ok, err := policy.HasRole(ctx, Subject{}, models.RoleGuiver); err != nil || ok {
+		t.Errorf("HasRole anonymous = %v, %v, want false", ok, err)
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// AdminHandler maneja las rutas de administración de los roles de los Guivers
+type AdminHandler struct {
+	BaseHandler
+	roleRepo   repository.RoleRepository
+	guiverRepo repository.GuiverRepository
+}
+
+// NewAdminHandler crea una nueva instancia de AdminHandler
+func NewAdminHandler(roleRepo repository.RoleRepository, guiverRepo repository.GuiverRepository, policy *authz.Policy) *AdminHandler {
+	return &AdminHandler{
+		BaseHandler: BaseHandler{policy: policy},
+		roleRepo:    roleRepo,
+		guiverRepo:  guiverRepo,
+	}
+}
+
+// Register registra las rutas del handler en el grupo de administración, que
+// ya exige el rol de administrador
+func (h *AdminHandler) Register(r *gin.RouterGroup) {
+	r.GET("/roles/:role", h.listByRole)
+	r.GET("/guivers/:id/roles", h.getRoles)
+	r.PUT("/guivers/:id/roles", h.setRoles)
+}
+
+// SetRolesRequest es la estructura para reemplazar los roles asignados a un
+// Guiver. Una lista vacía se los quita todos.
+type SetRolesRequest struct {
+	Roles []models.Role `json:"roles" binding:"required"`
+}
+
+// GuiverRolesResponse son los roles asignados a un Guiver. No incluye los del
+// claim "roles" de su token ni RoleGuiver, que tienen todos.
+type GuiverRolesResponse struct {
+	GuiverID string        `json:"guiverId"`
+	Roles    []models.Role `json:"roles"`
+}
+
+// RoleMembersResponse son los Guivers que tienen asignado un rol
+type RoleMembersResponse struct {
+	Role      models.Role `json:"role"`
+	GuiverIDs []string    `json:"guiverIds"`
+}
+
+func (h *AdminHandler) listByRole(c *gin.Context) {
+	role := models.Role(c.Param("role"))
+	if !role.Valid() || role == models.RoleGuiver {
+		h.sendError(c, http.StatusBadRequest, "Invalid role")
+		return
+	}
+
+	ids, err := h.roleRepo.ListByRole(c.Request.Context(), role)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Role", "Error listing guivers by role")
+		return
+	}
+
+	h.sendSuccess(c, RoleMembersResponse{Role: role, GuiverIDs: ids})
+}
+
+func (h *AdminHandler) getRoles(c *gin.Context) {
+	id := c.Param("id")
+	roles, err := h.roleRepo.GetRoles(c.Request.Context(), id)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Role", "Error getting roles")
+		return
+	}
+
+	h.sendSuccess(c, GuiverRolesResponse{GuiverID: id, Roles: roles})
+}
+
+// setRoles reemplaza los roles asignados a un Guiver existente. Un
+// administrador no puede quitarse a sí mismo el rol, para que no quede la
+// instalación sin nadie que pueda devolvérselo.
+func (h *AdminHandler) setRoles(c *gin.Context) {
+	var req SetRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	roles, err := models.NormalizeRoles(req.Roles)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid role")
+		return
+	}
+
+	id := c.Param("id")
+	if !h.authorize(c, authz.ActionManageRoles, authz.Owned(id), "Not authorized to manage roles") {
+		return
+	}
+	if id == c.GetString("userId") && !hasRole(roles, models.RoleAdmin) {
+		h.sendErrorCode(c, http.StatusConflict, responses.CodeConflict, "Admins cannot remove their own admin role")
+		return
+	}
+
+	if _, err := h.guiverRepo.GetByID(c.Request.Context(), id); err != nil {
+		h.sendRepositoryError(c, err, "Guiver", "Error getting guiver")
+		return
+	}
+	if err := h.roleRepo.SetRoles(c.Request.Context(), id, roles); err != nil {
+		if errors.Is(err, models.ErrInvalidRole) {
+			h.sendError(c, http.StatusBadRequest, "Invalid role")
+			return
+		}
+		h.sendRepositoryError(c, err, "Role", "Error setting roles")
+		return
+	}
+
+	h.sendSuccess(c, GuiverRolesResponse{GuiverID: id, Roles: roles})
+}
+
+func hasRole(roles []models.Role, role models.Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/identity"
+)
+
+// AuthHandler maneja el registro y el inicio de sesión de los Guivers
+type AuthHandler struct {
+	BaseHandler
+	identity   identity.Provider
+	guiverRepo repository.GuiverRepository
+}
+
+// NewAuthHandler crea una nueva instancia de AuthHandler
+func NewAuthHandler(provider identity.Provider, guiverRepo repository.GuiverRepository) *AuthHandler {
+	return &AuthHandler{
+		identity:   provider,
+		guiverRepo: guiverRepo,
+	}
+}
+
+// Register registra las rutas del handler, que son públicas
+func (h *AuthHandler) Register(r *gin.RouterGroup) {
+	auth := r.Group("/auth")
+	{
+		auth.POST("/register", h.register)
+		auth.POST("/login", h.login)
+	}
+}
+
+// RegisterRequest es la estructura para crear una cuenta con su Guiver
+type RegisterRequest struct {
+	Email       string            `json:"email" binding:"required,email"`
+	Password    string            `json:"password" binding:"required"`
+	DisplayName string            `json:"displayName" binding:"required"`
+	Type        models.GuiverType `json:"type" binding:"required"`
+	Bio         string            `json:"bio"`
+	WhatsApp    string            `json:"whatsApp"`
+	Instagram   string            `json:"instagram"`
+}
+
+// LoginRequest es la estructura para iniciar sesión
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginResponse son los tokens de la sesión junto con el Guiver de la cuenta,
+// que falta si la cuenta no tiene uno
+type LoginResponse struct {
+	identity.Session
+	Guiver *models.Guiver `json:"guiver,omitempty"`
+}
+
+// register crea la cuenta en el proveedor y su Guiver, cuyo ID es el UID de
+// la cuenta. Si el Guiver no se puede crear se elimina la cuenta, para que no
+// quede una cuenta sin perfil que impida volver a registrarse.
+func (h *AuthHandler) register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Type != models.GuiverTypeHelper && req.Type != models.GuiverTypeEntrepreneur {
+		h.sendError(c, http.StatusBadRequest, "Invalid guiver type")
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.identity.CreateUser(ctx, identity.NewUser{
+		Email:       req.Email,
+		Password:    req.Password,
+		DisplayName: req.DisplayName,
+	})
+	if err != nil {
+		h.sendIdentityError(c, err, "Error creating account")
+		return
+	}
+
+	guiver := &models.Guiver{
+		ID:          user.UID,
+		Email:       user.Email,
+		DisplayName: req.DisplayName,
+		Type:        req.Type,
+		Bio:         req.Bio,
+		WhatsApp:    req.WhatsApp,
+		Instagram:   req.Instagram,
+	}
+	if err := h.guiverRepo.Create(ctx, guiver); err != nil {
+		deleteAccount(ctx, h.identity, user.UID)
+		h.sendRepositoryError(c, err, "Guiver", "Error creating guiver")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{
+		"user":   user,
+		"guiver": guiver,
+	})
+}
+
+// login cambia email y contraseña por los tokens de la cuenta. Las cuentas
+// con el email sin verificar no pueden iniciar sesión.
+func (h *AuthHandler) login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ctx := c.Request.Context()
+	session, err := h.identity.SignIn(ctx, req.Email, req.Password)
+	if err != nil {
+		h.sendIdentityError(c, err, "Error signing in")
+		return
+	}
+	if !session.User.EmailVerified {
+		h.sendIdentityError(c, identity.ErrEmailNotVerified, "Error signing in")
+		return
+	}
+
+	resp := LoginResponse{Session: *session}
+	guiver, err := h.guiverRepo.GetByID(ctx, session.User.UID)
+	switch {
+	case err == nil:
+		resp.Guiver = guiver
+	case !errors.Is(err, repository.ErrNotFound):
+		h.sendRepositoryError(c, err, "Guiver", "Error getting guiver")
+		return
+	}
+
+	h.sendSuccess(c, resp)
+}
+
+// sendIdentityError traduce un error del proveedor de cuentas a la respuesta
+// HTTP correspondiente. message se usa para los errores inesperados.
+func (h *BaseHandler) sendIdentityError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, identity.ErrEmailExists):
+		h.sendErrorCode(c, http.StatusConflict, responses.CodeEmailExists, "Email is already registered")
+	case errors.Is(err, identity.ErrWeakPassword):
+		h.sendErrorCode(c, http.StatusBadRequest, responses.CodeWeakPassword, "Password must have at least 8 characters, including letters and numbers")
+	case errors.Is(err, identity.ErrInvalidCredentials):
+		h.sendErrorCode(c, http.StatusUnauthorized, responses.CodeInvalidCredentials, "Invalid email or password")
+	case errors.Is(err, identity.ErrEmailNotVerified):
+		h.sendErrorCode(c, http.StatusForbidden, responses.CodeEmailNotVerified, "Email has not been verified, check your inbox")
+	default:
+		log.Printf("%s: %v", message, err)
+		h.sendErrorCode(c, http.StatusBadGateway, responses.CodeAuthProvider, message)
+	}
+}
+
+// deleteAccount elimina una cuenta recién creada cuyo registro no pudo
+// completarse. Si falla solo se registra: la cuenta queda sin Guiver.
+func deleteAccount(ctx context.Context, provider identity.Provider, uid string) {
+	if err := provider.DeleteUser(ctx, uid); err != nil {
+		log.Printf("Error deleting account %s after failed registration: %v", uid, err)
+	}
+}
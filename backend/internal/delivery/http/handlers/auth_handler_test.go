@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/identity"
+	"github.com/guiver/internal/identity/fake"
+	"github.com/guiver/internal/infrastructure/memory"
+)
+
+// unavailableGuivers no puede crear Guivers
+type unavailableGuivers struct{ *memory.GuiverRepository }
+
+func (unavailableGuivers) Create(ctx context.Context, guiver *models.Guiver) error {
+	return repository.ErrUnavailable
+}
+
+func registerRequest(email, password string) RegisterRequest {
+	return RegisterRequest{Email: email, Password: password, DisplayName: "Alice", Type: models.GuiverTypeHelper}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    interface{}
+		status int
+		code   string
+	}{
+		{"Valid", registerRequest("alice@example.com", "secreto123"), http.StatusOK, ""},
+		{"DuplicateEmail", registerRequest("Taken@Example.com", "secreto123"), http.StatusConflict, responses.CodeEmailExists},
+		{"WeakPassword", registerRequest("bob@example.com", "secreto"), http.StatusBadRequest, responses.CodeWeakPassword},
+		{"InvalidEmail", registerRequest("bob", "secreto123"), http.StatusBadRequest, ""},
+		{"InvalidType", RegisterRequest{Email: "bob@example.com", Password: "secreto123", DisplayName: "Bob", Type: "investor"}, http.StatusBadRequest, ""},
+		{"MissingFields", map[string]string{"email": "bob@example.com"}, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := fake.New(false)
+			if _, err := provider.CreateUser(context.Background(), identity.NewUser{Email: "taken@example.com", Password: "secreto123"}); err != nil {
+				t.Fatalf("CreateUser: %v", err)
+			}
+			guivers := memory.NewGuiverRepository()
+			router := newRouter(NewAuthHandler(provider, guivers).Register)
+
+			w := serve(t, router, http.MethodPost, "/api/v1/auth/register", "", tt.req)
+			var got struct {
+				User   identity.User `json:"user"`
+				Guiver models.Guiver `json:"guiver"`
+			}
+			if tt.status != http.StatusOK {
+				expect(t, w, tt.status, nil)
+				if code := errorCode(t, w); code != tt.code {
+					t.Errorf("code = %q, want %q", code, tt.code)
+				}
+				return
+			}
+			expect(t, w, tt.status, &got)
+			if got.User.EmailVerified {
+				t.Error("new account has its email verified")
+			}
+			if got.Guiver.ID != got.User.UID || got.Guiver.Email != "alice@example.com" {
+				t.Errorf("guiver = %s %s, want %s alice@example.com", got.Guiver.ID, got.Guiver.Email, got.User.UID)
+			}
+			if _, err := guivers.GetByID(context.Background(), got.User.UID); err != nil {
+				t.Errorf("GetByID(%s): %v", got.User.UID, err)
+			}
+		})
+	}
+}
+
+func TestRegisterDeletesAccountWithoutGuiver(t *testing.T) {
+	provider := fake.New(true)
+	router := newRouter(NewAuthHandler(provider, unavailableGuivers{memory.NewGuiverRepository()}).Register)
+
+	w := serve(t, router, http.MethodPost, "/api/v1/auth/register", "", registerRequest("alice@example.com", "secreto123"))
+	expect(t, w, http.StatusServiceUnavailable, nil)
+
+	// La cuenta se eliminó, así que no puede iniciar sesión pero sí volver a registrarse
+	if _, err := provider.SignIn(context.Background(), "alice@example.com", "secreto123"); !errors.Is(err, identity.ErrInvalidCredentials) {
+		t.Errorf("SignIn after failed registration error = %v, want %v", err, identity.ErrInvalidCredentials)
+	}
+	if _, err := provider.CreateUser(context.Background(), identity.NewUser{Email: "alice@example.com", Password: "secreto123"}); err != nil {
+		t.Errorf("CreateUser after failed registration: %v", err)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	provider := fake.New(false)
+	guivers := memory.NewGuiverRepository()
+	router := newRouter(NewAuthHandler(provider, guivers).Register)
+
+	// alice tiene el email verificado y Guiver, bob solo el email verificado y
+	// carol ninguno de los dos
+	users := map[string]string{}
+	for _, email := range []string{"alice@example.com", "bob@example.com", "carol@example.com"} {
+		user, err := provider.CreateUser(ctx, identity.NewUser{Email: email, Password: "secreto123"})
+		if err != nil {
+			t.Fatalf("CreateUser(%s): %v", email, err)
+		}
+		users[email] = user.UID
+	}
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		if err := provider.VerifyEmail(users[email]); err != nil {
+			t.Fatalf("VerifyEmail(%s): %v", email, err)
+		}
+	}
+	if err := guivers.Create(ctx, &models.Guiver{ID: users["alice@example.com"], Email: "alice@example.com", DisplayName: "Alice", Type: models.GuiverTypeHelper}); err != nil {
+		t.Fatalf("creating guiver: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		email     string
+		password  string
+		status    int
+		code      string
+		hasGuiver bool
+	}{
+		{"WithGuiver", "alice@example.com", "secreto123", http.StatusOK, "", true},
+		{"NormalizedEmail", " ALICE@example.com", "secreto123", http.StatusOK, "", true},
+		{"WithoutGuiver", "bob@example.com", "secreto123", http.StatusOK, "", false},
+		{"Unverified", "carol@example.com", "secreto123", http.StatusForbidden, responses.CodeEmailNotVerified, false},
+		{"WrongPassword", "alice@example.com", "secreto124", http.StatusUnauthorized, responses.CodeInvalidCredentials, false},
+		{"UnknownEmail", "dave@example.com", "secreto123", http.StatusUnauthorized, responses.CodeInvalidCredentials, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(t, router, http.MethodPost, "/api/v1/auth/login", "", LoginRequest{Email: tt.email, Password: tt.password})
+			if tt.status != http.StatusOK {
+				expect(t, w, tt.status, nil)
+				if code := errorCode(t, w); code != tt.code {
+					t.Errorf("code = %q, want %q", code, tt.code)
+				}
+				return
+			}
+
+			var got LoginResponse
+			expect(t, w, tt.status, &got)
+			if (got.Guiver != nil) != tt.hasGuiver {
+				t.Errorf("guiver = %v, want present %v", got.Guiver, tt.hasGuiver)
+			}
+			// El ID token emitido se acepta en las rutas protegidas
+			token, err := provider.Verifier().VerifyToken(ctx, got.IDToken)
+			if err != nil {
+				t.Fatalf("VerifyToken(%s): %v", got.IDToken, err)
+			}
+			if token.UID != got.User.UID || !token.EmailVerified() {
+				t.Errorf("token = %s (verified %v), want %s (verified)", token.UID, token.EmailVerified(), got.User.UID)
+			}
+		})
+	}
+}
@@ -1,14 +1,25 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/pagination"
 	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/middleware"
 )
 
 // BaseHandler contiene funciones de utilidad para los handlers
-type BaseHandler struct{}
+type BaseHandler struct {
+	cursors *pagination.CursorCodec
+	policy  *authz.Policy
+}
 
 // sendSuccess envía una respuesta exitosa
 func (h *BaseHandler) sendSuccess(c *gin.Context, data interface{}) {
@@ -26,6 +37,88 @@ func (h *BaseHandler) sendError(c *gin.Context, code int, message string) {
 	})
 }
 
+// sendErrorCode envía una respuesta de error con un código legible por el cliente
+func (h *BaseHandler) sendErrorCode(c *gin.Context, status int, code, message string) {
+	c.JSON(status, responses.ErrorResponse{
+		Status:  "error",
+		Message: message,
+		Code:    code,
+	})
+}
+
+// sendRepositoryError traduce un error del repositorio a la respuesta HTTP
+// correspondiente. resource nombra la entidad afectada ("Cause") y message se
+// usa para los errores inesperados.
+func (h *BaseHandler) sendRepositoryError(c *gin.Context, err error, resource, message string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		h.sendErrorCode(c, http.StatusNotFound, responses.CodeNotFound, resource+" not found")
+	case errors.Is(err, repository.ErrConflict):
+		h.sendErrorCode(c, http.StatusConflict, responses.CodeConflict, resource+" already exists or was modified concurrently")
+	case errors.Is(err, repository.ErrPermission):
+		h.sendErrorCode(c, http.StatusForbidden, responses.CodeForbidden, "Not authorized to access this resource")
+	case errors.Is(err, repository.ErrUnavailable):
+		h.sendErrorCode(c, http.StatusServiceUnavailable, responses.CodeUnavailable, "Service temporarily unavailable, please retry")
+	default:
+		log.Printf("%s: %v", message, err)
+		h.sendErrorCode(c, http.StatusInternalServerError, responses.CodeInternal, message)
+	}
+}
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// pageParams lee los parámetros page y limit de la query, con valores por defecto
+// y un tamaño de página máximo
+func (h *BaseHandler) pageParams(c *gin.Context) (page, limit int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	return page, h.limitParam(c)
+}
+
+// limitParam lee el parámetro limit de la query, con valor por defecto y máximo
+func (h *BaseHandler) limitParam(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// cursorParam indica si la petición usa paginación por cursor (parámetro
+// cursor presente, vacío para la primera página) y decodifica el cursor
+func (h *BaseHandler) cursorParam(c *gin.Context) (after *repository.Cursor, ok bool, err error) {
+	token, ok := c.GetQuery("cursor")
+	if !ok || token == "" {
+		return nil, ok, nil
+	}
+	after, err = h.cursors.Decode(token)
+	return after, true, err
+}
+
+// encodeCursor genera el token del cursor que apunta al elemento indicado
+func (h *BaseHandler) encodeCursor(createdAt time.Time, id string) string {
+	return h.cursors.Encode(repository.Cursor{CreatedAt: createdAt, ID: id})
+}
+
+// sendCursorPage envía una respuesta paginada por cursor
+func (h *BaseHandler) sendCursorPage(c *gin.Context, data interface{}, nextCursor string, pageSize int) {
+	c.JSON(http.StatusOK, responses.CursorPaginatedResponse{
+		Status:     "success",
+		Data:       data,
+		PageSize:   pageSize,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	})
+}
+
 // sendPaginated envía una respuesta paginada
 func (h *BaseHandler) sendPaginated(c *gin.Context, data interface{}, total int64, page, pageSize int) {
 	totalPages := int(total) / pageSize
@@ -42,3 +135,24 @@ func (h *BaseHandler) sendPaginated(c *gin.Context, data interface{}, total int6
 		TotalPages: totalPages,
 	})
 }
+
+// subject devuelve el Guiver autenticado de la petición
+func (h *BaseHandler) subject(c *gin.Context) authz.Subject {
+	return authz.Subject{ID: c.GetString("userId"), Claims: middleware.Claims(c)}
+}
+
+// authorize verifica con la política de permisos que el usuario actual pueda
+// hacer la acción sobre el recurso. Si no, envía forbidden o el error al leer
+// sus roles y devuelve false.
+func (h *BaseHandler) authorize(c *gin.Context, action authz.Action, resource authz.Resource, forbidden string) bool {
+	err := h.policy.Authorize(c.Request.Context(), h.subject(c), action, resource)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, authz.ErrDenied):
+		h.sendErrorCode(c, http.StatusForbidden, responses.CodeForbidden, forbidden)
+	default:
+		h.sendRepositoryError(c, err, "Role", "Error checking permissions")
+	}
+	return false
+}
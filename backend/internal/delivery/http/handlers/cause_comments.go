@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// maxMentions es la cantidad máxima de Guivers que se pueden mencionar en un comentario
+const maxMentions = 10
+
+// AddCommentRequest es la estructura para agregar un comentario. ParentID
+// indica el comentario al que se responde y Mentions los IDs de los Guivers
+// mencionados.
+type AddCommentRequest struct {
+	Content  string   `json:"content" binding:"required"`
+	ParentID string   `json:"parentId"`
+	Mentions []string `json:"mentions"`
+}
+
+// UpdateCommentRequest es la estructura para editar un comentario. Las
+// menciones reemplazan a las anteriores.
+type UpdateCommentRequest struct {
+	Content  string   `json:"content" binding:"required"`
+	Mentions []string `json:"mentions"`
+}
+
+// listComments lista los comentarios principales de una causa, del más nuevo
+// al más antiguo y paginados por cursor, cada uno con sus respuestas anidadas
+func (h *CauseHandler) listComments(c *gin.Context) {
+	id := c.Param("id")
+	after, _, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	limit := h.limitParam(c)
+	// Se pide un elemento de más para saber si hay otra página
+	comments, err := h.causeRepo.ListComments(c.Request.Context(), id, repository.CommentFilter{
+		Limit: limit + 1,
+		After: after,
+	})
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error listing comments")
+		return
+	}
+
+	nextCursor := ""
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	threadIDs := make([]string, len(comments))
+	for i, comment := range comments {
+		threadIDs[i] = comment.ID
+	}
+	replies, err := h.causeRepo.ListReplies(c.Request.Context(), id, threadIDs)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error listing replies")
+		return
+	}
+	models.NestReplies(comments, replies)
+
+	if err := h.attachAuthors(c, comments...); err != nil {
+		h.sendRepositoryError(c, err, "Guiver", "Error getting comment authors")
+		return
+	}
+
+	h.sendCursorPage(c, comments, nextCursor, limit)
+}
+
+// getComment devuelve un comentario con sus respuestas anidadas
+func (h *CauseHandler) getComment(c *gin.Context) {
+	id := c.Param("id")
+	comment, err := h.causeRepo.GetComment(c.Request.Context(), id, c.Param("commentId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Comment", "Error getting comment")
+		return
+	}
+
+	replies, err := h.causeRepo.ListReplies(c.Request.Context(), id, []string{models.ThreadRoot(comment)})
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error listing replies")
+		return
+	}
+	models.NestReplies([]*models.Comment{comment}, replies)
+
+	if err := h.attachAuthors(c, comment); err != nil {
+		h.sendRepositoryError(c, err, "Guiver", "Error getting comment authors")
+		return
+	}
+
+	h.sendSuccess(c, comment)
+}
+
+func (h *CauseHandler) addComment(c *gin.Context) {
+	id := c.Param("id")
+	var req AddCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	mentions, ok := h.resolveMentions(c, req.Mentions)
+	if !ok {
+		return
+	}
+
+	if req.ParentID != "" {
+		if _, err := h.causeRepo.GetComment(c.Request.Context(), id, req.ParentID); err != nil {
+			h.sendRepositoryError(c, err, "Parent comment", "Error getting parent comment")
+			return
+		}
+	}
+
+	comment := &models.Comment{
+		GuiverID: c.GetString("userId"),
+		Content:  req.Content,
+		ParentID: req.ParentID,
+		Mentions: mentions,
+	}
+
+	if err := h.causeRepo.AddComment(c.Request.Context(), id, comment); err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error adding comment")
+		return
+	}
+
+	if err := h.attachAuthors(c, comment); err != nil {
+		h.sendRepositoryError(c, err, "Guiver", "Error getting comment authors")
+		return
+	}
+
+	h.sendSuccess(c, comment)
+}
+
+func (h *CauseHandler) updateComment(c *gin.Context) {
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	mentions, ok := h.resolveMentions(c, req.Mentions)
+	if !ok {
+		return
+	}
+
+	comment, ok := h.authorizedComment(c, authz.ActionEditComment, "Not authorized to edit this comment")
+	if !ok {
+		return
+	}
+
+	comment.Content = req.Content
+	comment.Mentions = mentions
+	if err := h.causeRepo.UpdateComment(c.Request.Context(), c.Param("id"), comment); err != nil {
+		h.sendRepositoryError(c, err, "Comment", "Error updating comment")
+		return
+	}
+
+	if err := h.attachAuthors(c, comment); err != nil {
+		h.sendRepositoryError(c, err, "Guiver", "Error getting comment authors")
+		return
+	}
+
+	h.sendSuccess(c, comment)
+}
+
+func (h *CauseHandler) deleteComment(c *gin.Context) {
+	comment, ok := h.authorizedComment(c, authz.ActionDeleteComment, "Not authorized to delete this comment")
+	if !ok {
+		return
+	}
+
+	if err := h.causeRepo.DeleteComment(c.Request.Context(), c.Param("id"), comment.ID); err != nil {
+		h.sendRepositoryError(c, err, "Comment", "Error deleting comment")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{"message": "Comment deleted successfully"})
+}
+
+// authorizedComment obtiene el comentario de la ruta y verifica que el usuario
+// actual pueda hacer la acción sobre él. Si no, envía el error y devuelve
+// false.
+func (h *CauseHandler) authorizedComment(c *gin.Context, action authz.Action, forbidden string) (*models.Comment, bool) {
+	comment, err := h.causeRepo.GetComment(c.Request.Context(), c.Param("id"), c.Param("commentId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Comment", "Error getting comment")
+		return nil, false
+	}
+
+	if !h.authorize(c, action, authz.Owned(comment.GuiverID), forbidden) {
+		return nil, false
+	}
+	return comment, true
+}
+
+// resolveMentions verifica que los Guivers mencionados existan y quita los
+// repetidos. Si no, envía el error y devuelve false.
+func (h *CauseHandler) resolveMentions(c *gin.Context, ids []string) ([]string, bool) {
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		mentions = append(mentions, id)
+	}
+	if len(mentions) > maxMentions {
+		h.sendError(c, http.StatusBadRequest, "Too many mentions")
+		return nil, false
+	}
+
+	for _, id := range mentions {
+		if _, err := h.guiverRepo.GetByID(c.Request.Context(), id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				h.sendError(c, http.StatusBadRequest, "Mentioned guiver not found: "+id)
+			} else {
+				h.sendRepositoryError(c, err, "Guiver", "Error getting mentioned guiver")
+			}
+			return nil, false
+		}
+	}
+	return mentions, true
+}
+
+// attachAuthors completa el autor y los Guivers mencionados de cada
+// comentario y de sus respuestas. Los Guivers que ya no existen se omiten.
+func (h *CauseHandler) attachAuthors(c *gin.Context, comments ...*models.Comment) error {
+	authors := make(map[string]*models.Author)
+	author := func(id string) (*models.Author, error) {
+		if author, seen := authors[id]; seen {
+			return author, nil
+		}
+		guiver, err := h.guiverRepo.GetByID(c.Request.Context(), id)
+		switch {
+		case err == nil:
+			authors[id] = &models.Author{ID: guiver.ID, DisplayName: guiver.DisplayName, PhotoURL: guiver.PhotoURL}
+		case errors.Is(err, repository.ErrNotFound):
+			authors[id] = nil
+		default:
+			return nil, err
+		}
+		return authors[id], nil
+	}
+
+	pending := append([]*models.Comment(nil), comments...)
+	for len(pending) > 0 {
+		comment := pending[len(pending)-1]
+		pending = append(pending[:len(pending)-1], comment.Replies...)
+
+		var err error
+		if comment.Author, err = author(comment.GuiverID); err != nil {
+			return err
+		}
+		comment.Mentioned = nil
+		for _, id := range comment.Mentions {
+			mentioned, err := author(id)
+			if err != nil {
+				return err
+			}
+			if mentioned != nil {
+				comment.Mentioned = append(comment.Mentioned, mentioned)
+			}
+		}
+	}
+	return nil
+}
@@ -1,43 +1,72 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/pagination"
 	"github.com/guiver/internal/domain/models"
 	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/middleware"
 )
 
 // CauseHandler maneja las rutas relacionadas con las causas
 type CauseHandler struct {
 	BaseHandler
-	causeRepo repository.CauseRepository
+	causeRepo  repository.CauseRepository
+	guiverRepo repository.GuiverRepository
 }
 
 // NewCauseHandler crea una nueva instancia de CauseHandler
-func NewCauseHandler(causeRepo repository.CauseRepository) *CauseHandler {
+func NewCauseHandler(causeRepo repository.CauseRepository, guiverRepo repository.GuiverRepository, policy *authz.Policy, cursors *pagination.CursorCodec) *CauseHandler {
 	return &CauseHandler{
-		causeRepo: causeRepo,
+		BaseHandler: BaseHandler{cursors: cursors, policy: policy},
+		causeRepo:   causeRepo,
+		guiverRepo:  guiverRepo,
 	}
 }
 
 // Register registra las rutas del handler
 func (h *CauseHandler) Register(r *gin.RouterGroup) {
+	// Las rutas que modifican datos requieren el email verificado
+	verified := middleware.RequireVerifiedEmail()
 	causes := r.Group("/causes")
 	{
-		causes.POST("", h.createCause)
+		causes.POST("", verified, h.createCause)
 		causes.GET("", h.listCauses)
 		causes.GET("/:id", h.getCause)
-		causes.PUT("/:id", h.updateCause)
-		causes.DELETE("/:id", h.deleteCause)
-		causes.POST("/:id/updates", h.addUpdate)
-		causes.POST("/:id/comments", h.addComment)
-		causes.POST("/:id/like", h.likeCause)
-		causes.POST("/:id/unlike", h.unlikeCause)
+		causes.PUT("/:id", verified, h.updateCause)
+		causes.DELETE("/:id", verified, h.deleteCause)
+		causes.GET("/:id/updates", h.listUpdates)
+		causes.POST("/:id/updates", verified, h.addUpdate)
+		causes.PUT("/:id/updates/:updateId", verified, h.editUpdate)
+		causes.DELETE("/:id/updates/:updateId", verified, h.deleteUpdate)
+		causes.POST("/:id/updates/:updateId/pin", verified, h.pinUpdate)
+		causes.DELETE("/:id/updates/:updateId/pin", verified, h.unpinUpdate)
+		causes.POST("/:id/collaborators", verified, h.addCollaborator)
+		causes.DELETE("/:id/collaborators/:guiverId", verified, h.removeCollaborator)
+		causes.GET("/:id/comments", h.listComments)
+		causes.GET("/:id/comments/:commentId", h.getComment)
+		causes.POST("/:id/comments", verified, h.addComment)
+		causes.PUT("/:id/comments/:commentId", verified, h.updateComment)
+		causes.DELETE("/:id/comments/:commentId", verified, h.deleteComment)
+		causes.POST("/:id/like", verified, h.likeCause)
+		causes.DELETE("/:id/like", verified, h.unlikeCause)
+		causes.POST("/:id/unlike", verified, h.unlikeCause)
 	}
 }
 
+// RegisterAdmin registra en el grupo de administración las rutas para
+// modificar o eliminar cualquier causa
+func (h *CauseHandler) RegisterAdmin(r *gin.RouterGroup) {
+	r.PUT("/causes/:id", h.updateCause)
+	r.DELETE("/causes/:id", h.deleteCause)
+}
+
 // CreateCauseRequest es la estructura para crear una causa
 type CreateCauseRequest struct {
 	Title       string          `json:"title" binding:"required"`
@@ -46,6 +75,10 @@ type CreateCauseRequest struct {
 	Location    string          `json:"location" binding:"required"`
 	ImageURLs   []string        `json:"imageUrls"`
 	ContactInfo models.ContactInfo `json:"contactInfo"`
+	// Meta de recaudación opcional. Su moneda es la de la causa y se puede
+	// indicar con un importe cero para recibir aportes sin meta.
+	GoalAmount models.Money `json:"goalAmount"`
+	Deadline   *time.Time   `json:"deadline"`
 }
 
 func (h *CauseHandler) createCause(c *gin.Context) {
@@ -55,6 +88,12 @@ func (h *CauseHandler) createCause(c *gin.Context) {
 		return
 	}
 
+	req.GoalAmount.Currency = strings.ToUpper(req.GoalAmount.Currency)
+	if msg := goalError(req.GoalAmount, req.Deadline); msg != "" {
+		h.sendError(c, http.StatusBadRequest, msg)
+		return
+	}
+
 	// Obtener el ID del Guiver del contexto (establecido por el middleware de auth)
 	guiverID, exists := c.Get("userId")
 	if !exists {
@@ -70,45 +109,110 @@ func (h *CauseHandler) createCause(c *gin.Context) {
 		Location:    req.Location,
 		ImageURLs:   req.ImageURLs,
 		ContactInfo: req.ContactInfo,
+		GoalAmount:  req.GoalAmount,
+		Deadline:    req.Deadline,
 		Status:      models.CauseStatusActive,
 	}
 
 	if err := h.causeRepo.Create(c.Request.Context(), cause); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error creating cause")
+		h.sendRepositoryError(c, err, "Cause", "Error creating cause")
 		return
 	}
 
 	h.sendSuccess(c, cause)
 }
 
+// listCauses lista las causas. Con el parámetro cursor (vacío para la primera
+// página) pagina por cursor; si no, mantiene la paginación por page y limit.
 func (h *CauseHandler) listCauses(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
 	filter := repository.CauseFilter{
 		Type:     models.CauseType(c.Query("type")),
 		Status:   models.CauseStatus(c.Query("status")),
 		Location: c.Query("location"),
 		Search:   c.Query("search"),
-		Limit:    limit,
-		Offset:   (page - 1) * limit,
 	}
 
+	after, cursorMode, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if cursorMode {
+		h.listCausesByCursor(c, filter, after)
+		return
+	}
+
+	page, limit := h.pageParams(c)
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
+
+	causes, err := h.causeRepo.List(c.Request.Context(), filter)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error listing causes")
+		return
+	}
+
+	total, err := h.causeRepo.Count(c.Request.Context(), filter)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error counting causes")
+		return
+	}
+
+	if err := h.markLikedByMe(c, causes...); err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error reading likes")
+		return
+	}
+
+	h.sendPaginated(c, causes, total, page, limit)
+}
+
+func (h *CauseHandler) listCausesByCursor(c *gin.Context, filter repository.CauseFilter, after *repository.Cursor) {
+	limit := h.limitParam(c)
+	// Se pide un elemento de más para saber si hay otra página
+	filter.Limit = limit + 1
+	filter.After = after
+
 	causes, err := h.causeRepo.List(c.Request.Context(), filter)
 	if err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error listing causes")
+		h.sendRepositoryError(c, err, "Cause", "Error listing causes")
 		return
 	}
 
-	// TODO: Implementar el conteo total para la paginación
-	h.sendPaginated(c, causes, int64(len(causes)), page, limit)
+	nextCursor := ""
+	if len(causes) > limit {
+		causes = causes[:limit]
+		last := causes[len(causes)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	if err := h.markLikedByMe(c, causes...); err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error reading likes")
+		return
+	}
+
+	h.sendCursorPage(c, causes, nextCursor, limit)
 }
 
+// getCause devuelve la causa con todas sus actualizaciones o, con
+// view=summary, solo el resumen; las actualizaciones se leen paginadas de
+// /causes/:id/updates
 func (h *CauseHandler) getCause(c *gin.Context) {
 	id := c.Param("id")
-	cause, err := h.causeRepo.GetByID(c.Request.Context(), id)
+	get := h.causeRepo.GetByID
+	if c.Query("view") == "summary" {
+		get = h.causeRepo.GetSummary
+	}
+	cause, err := get(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Cause not found")
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+	for i := range cause.Updates {
+		cause.Updates[i].Pinned = cause.Updates[i].ID == cause.PinnedUpdateID
+	}
+
+	if err := h.markLikedByMe(c, cause); err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error reading likes")
 		return
 	}
 
@@ -123,6 +227,8 @@ type UpdateCauseRequest struct {
 	ImageURLs   []string         `json:"imageUrls"`
 	Status      models.CauseStatus `json:"status"`
 	ContactInfo models.ContactInfo  `json:"contactInfo"`
+	GoalAmount  *models.Money      `json:"goalAmount"`
+	Deadline    *time.Time         `json:"deadline"`
 }
 
 func (h *CauseHandler) updateCause(c *gin.Context) {
@@ -135,14 +241,12 @@ func (h *CauseHandler) updateCause(c *gin.Context) {
 
 	cause, err := h.causeRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Cause not found")
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
 		return
 	}
 
-	// Verificar que el usuario actual es el dueño de la causa
-	guiverID, _ := c.Get("userId")
-	if cause.GuiverID != guiverID.(string) {
-		h.sendError(c, http.StatusForbidden, "Not authorized to update this cause")
+	// Verificar que el usuario actual puede modificar la causa
+	if !h.authorize(c, authz.ActionUpdateCause, authz.CauseResource(cause), "Not authorized to update this cause") {
 		return
 	}
 
@@ -165,9 +269,31 @@ func (h *CauseHandler) updateCause(c *gin.Context) {
 	if req.ContactInfo != (models.ContactInfo{}) {
 		cause.ContactInfo = req.ContactInfo
 	}
+	if req.GoalAmount != nil {
+		goal := *req.GoalAmount
+		goal.Currency = strings.ToUpper(goal.Currency)
+		if goal.Currency == "" {
+			goal.Currency = cause.GoalAmount.Currency
+		}
+		cause.GoalAmount = goal
+	}
+	// Solo se exige una fecha límite futura al cambiarla
+	if msg := goalError(cause.GoalAmount, req.Deadline); msg != "" {
+		h.sendError(c, http.StatusBadRequest, msg)
+		return
+	}
+	if req.Deadline != nil {
+		cause.Deadline = req.Deadline
+	}
 
-	if err := h.causeRepo.Update(c.Request.Context(), cause); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error updating cause")
+	// El repositorio rechaza cambiar la moneda si ya hay aportes en la actual
+	err = h.causeRepo.Update(c.Request.Context(), cause)
+	if errors.Is(err, repository.ErrConflict) {
+		h.sendError(c, http.StatusConflict, "Currency cannot change once the cause has pledges")
+		return
+	}
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error updating cause")
 		return
 	}
 
@@ -177,97 +303,73 @@ func (h *CauseHandler) updateCause(c *gin.Context) {
 func (h *CauseHandler) deleteCause(c *gin.Context) {
 	id := c.Param("id")
 	
-	// Verificar que el usuario actual es el dueño de la causa
+	// Verificar que el usuario actual puede eliminar la causa
 	cause, err := h.causeRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Cause not found")
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
 		return
 	}
 
-	guiverID, _ := c.Get("userId")
-	if cause.GuiverID != guiverID.(string) {
-		h.sendError(c, http.StatusForbidden, "Not authorized to delete this cause")
+	if !h.authorize(c, authz.ActionDeleteCause, authz.CauseResource(cause), "Not authorized to delete this cause") {
 		return
 	}
 
-	if err := h.causeRepo.Delete(c.Request.Context(), id); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error deleting cause")
+	// Borrar la causa borraría también el registro de los aportes que se
+	// están cobrando o ya se cobraron: el repositorio lo rechaza
+	err = h.causeRepo.Delete(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrConflict) {
+		h.sendError(c, http.StatusConflict, "Cause has pledges and cannot be deleted, cancel it instead")
 		return
 	}
-
-	h.sendSuccess(c, gin.H{"message": "Cause deleted successfully"})
-}
-
-// AddUpdateRequest es la estructura para agregar una actualización
-type AddUpdateRequest struct {
-	Content   string   `json:"content" binding:"required"`
-	ImageURLs []string `json:"imageUrls"`
-}
-
-func (h *CauseHandler) addUpdate(c *gin.Context) {
-	id := c.Param("id")
-	var req AddUpdateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.sendError(c, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	update := &models.Update{
-		Content:   req.Content,
-		ImageURLs: req.ImageURLs,
-	}
-
-	if err := h.causeRepo.AddUpdate(c.Request.Context(), id, update); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error adding update")
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error deleting cause")
 		return
 	}
 
-	h.sendSuccess(c, update)
-}
-
-// AddCommentRequest es la estructura para agregar un comentario
-type AddCommentRequest struct {
-	Content string `json:"content" binding:"required"`
+	h.sendSuccess(c, gin.H{"message": "Cause deleted successfully"})
 }
 
-func (h *CauseHandler) addComment(c *gin.Context) {
+// likeCause registra el like del usuario. Repetirlo no suma otro like.
+func (h *CauseHandler) likeCause(c *gin.Context) {
 	id := c.Param("id")
-	var req AddCommentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.sendError(c, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	guiverID, _ := c.Get("userId")
-	comment := &models.Comment{
-		GuiverID: guiverID.(string),
-		Content:  req.Content,
-	}
-
-	if err := h.causeRepo.AddComment(c.Request.Context(), id, comment); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error adding comment")
+	likes, err := h.causeRepo.Like(c.Request.Context(), id, c.GetString("userId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error liking cause")
 		return
 	}
 
-	h.sendSuccess(c, comment)
+	h.sendSuccess(c, gin.H{"likes": likes, "likedByMe": true})
 }
 
-func (h *CauseHandler) likeCause(c *gin.Context) {
+// unlikeCause quita el like del usuario, si lo había
+func (h *CauseHandler) unlikeCause(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.causeRepo.UpdateLikes(c.Request.Context(), id, true); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error liking cause")
+	likes, err := h.causeRepo.Unlike(c.Request.Context(), id, c.GetString("userId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error unliking cause")
 		return
 	}
 
-	h.sendSuccess(c, gin.H{"message": "Cause liked successfully"})
+	h.sendSuccess(c, gin.H{"likes": likes, "likedByMe": false})
 }
 
-func (h *CauseHandler) unlikeCause(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.causeRepo.UpdateLikes(c.Request.Context(), id, false); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error unliking cause")
-		return
+// markLikedByMe completa LikedByMe de las Causas para el usuario autenticado
+func (h *CauseHandler) markLikedByMe(c *gin.Context, causes ...*models.Cause) error {
+	guiverID := c.GetString("userId")
+	if guiverID == "" || len(causes) == 0 {
+		return nil
 	}
 
-	h.sendSuccess(c, gin.H{"message": "Cause unliked successfully"})
+	ids := make([]string, len(causes))
+	for i, cause := range causes {
+		ids[i] = cause.ID
+	}
+	liked, err := h.causeRepo.LikedBy(c.Request.Context(), guiverID, ids)
+	if err != nil {
+		return err
+	}
+	for _, cause := range causes {
+		cause.LikedByMe = liked[cause.ID]
+	}
+	return nil
 }
@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// AddUpdateRequest es la estructura para agregar una actualización. Solo el
+// dueño de la causa puede rendir gastos en ella.
+type AddUpdateRequest struct {
+	Content   string           `json:"content" binding:"required"`
+	ImageURLs []string         `json:"imageUrls"`
+	Expenses  []models.Expense `json:"expenses"`
+}
+
+// EditUpdateRequest es la estructura para editar una actualización. Las
+// imágenes reemplazan a las anteriores; los gastos también, pero si se omiten
+// se conservan.
+type EditUpdateRequest struct {
+	Content   string            `json:"content" binding:"required"`
+	ImageURLs []string          `json:"imageUrls"`
+	Expenses  *[]models.Expense `json:"expenses"`
+}
+
+// AddCollaboratorRequest es la estructura para sumar un colaborador a una causa
+type AddCollaboratorRequest struct {
+	GuiverID string `json:"guiverId" binding:"required"`
+}
+
+// addUpdate publica una actualización. Pueden hacerlo el dueño de la causa y
+// sus colaboradores.
+func (h *CauseHandler) addUpdate(c *gin.Context) {
+	id := c.Param("id")
+	var req AddUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cause, err := h.causeRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	if !h.authorize(c, authz.ActionPostUpdate, authz.CauseResource(cause), "Not authorized to post updates on this cause") {
+		return
+	}
+	if len(req.Expenses) > 0 &&
+		!h.authorize(c, authz.ActionManageFunds, authz.CauseResource(cause), "Only the cause owner can report expenses") {
+		return
+	}
+	if msg := normalizeExpenses(req.Expenses, cause.GoalAmount.Currency); msg != "" {
+		h.sendError(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	update := &models.Update{
+		GuiverID:  c.GetString("userId"),
+		Content:   req.Content,
+		ImageURLs: req.ImageURLs,
+		Expenses:  req.Expenses,
+	}
+
+	if err := h.causeRepo.AddUpdate(c.Request.Context(), id, update); err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error adding update")
+		return
+	}
+
+	h.sendSuccess(c, update)
+}
+
+// listUpdates lista las actualizaciones de una causa, de la más nueva a la más
+// antigua y paginadas por cursor. La primera página empieza con la
+// actualización fijada, que no se repite en su lugar cronológico.
+func (h *CauseHandler) listUpdates(c *gin.Context) {
+	id := c.Param("id")
+	after, _, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	cause, err := h.causeRepo.GetSummary(c.Request.Context(), id)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	limit := h.limitParam(c)
+	// Se piden dos de más: uno por si aparece la fijada y otro para saber si
+	// hay otra página
+	listed, err := h.causeRepo.ListUpdates(c.Request.Context(), id, repository.UpdateFilter{
+		Limit: limit + 2,
+		After: after,
+	})
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error listing updates")
+		return
+	}
+
+	updates := []*models.Update{}
+	for _, update := range listed {
+		if update.ID != cause.PinnedUpdateID {
+			updates = append(updates, update)
+		}
+	}
+
+	nextCursor := ""
+	if len(updates) > limit {
+		updates = updates[:limit]
+		last := updates[len(updates)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	if after == nil && cause.PinnedUpdateID != "" {
+		pinned, err := h.causeRepo.GetUpdate(c.Request.Context(), id, cause.PinnedUpdateID)
+		switch {
+		case err == nil:
+			pinned.Pinned = true
+			updates = append([]*models.Update{pinned}, updates...)
+		case !errors.Is(err, repository.ErrNotFound):
+			h.sendRepositoryError(c, err, "Update", "Error getting pinned update")
+			return
+		}
+	}
+
+	h.sendCursorPage(c, updates, nextCursor, limit)
+}
+
+func (h *CauseHandler) editUpdate(c *gin.Context) {
+	var req EditUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cause, update, ok := h.manageableUpdate(c, authz.ActionEditUpdate, "Not authorized to edit this update")
+	if !ok {
+		return
+	}
+
+	if req.Expenses != nil {
+		if !h.authorize(c, authz.ActionManageFunds, authz.CauseResource(cause), "Only the cause owner can report expenses") {
+			return
+		}
+		if msg := normalizeExpenses(*req.Expenses, cause.GoalAmount.Currency); msg != "" {
+			h.sendError(c, http.StatusBadRequest, msg)
+			return
+		}
+		update.Expenses = *req.Expenses
+	}
+
+	update.Content = req.Content
+	update.ImageURLs = req.ImageURLs
+	if err := h.causeRepo.EditUpdate(c.Request.Context(), c.Param("id"), update); err != nil {
+		h.sendRepositoryError(c, err, "Update", "Error editing update")
+		return
+	}
+
+	h.sendSuccess(c, update)
+}
+
+func (h *CauseHandler) deleteUpdate(c *gin.Context) {
+	_, update, ok := h.manageableUpdate(c, authz.ActionDeleteUpdate, "Not authorized to delete this update")
+	if !ok {
+		return
+	}
+
+	if err := h.causeRepo.DeleteUpdate(c.Request.Context(), c.Param("id"), update.ID); err != nil {
+		h.sendRepositoryError(c, err, "Update", "Error deleting update")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{"message": "Update deleted successfully"})
+}
+
+// pinUpdate fija la actualización al principio del historial de la causa,
+// reemplazando a la que estuviera fijada
+func (h *CauseHandler) pinUpdate(c *gin.Context) {
+	h.setPinnedUpdate(c, c.Param("updateId"))
+}
+
+// unpinUpdate desfija la actualización si es la fijada
+func (h *CauseHandler) unpinUpdate(c *gin.Context) {
+	h.setPinnedUpdate(c, "")
+}
+
+// setPinnedUpdate fija la actualización updateID o, si está vacío, desfija la
+// de la ruta. Pueden hacerlo el dueño de la causa y sus colaboradores.
+func (h *CauseHandler) setPinnedUpdate(c *gin.Context, updateID string) {
+	id := c.Param("id")
+	cause, err := h.causeRepo.GetSummary(c.Request.Context(), id)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	if !h.authorize(c, authz.ActionPinUpdate, authz.CauseResource(cause), "Not authorized to pin updates on this cause") {
+		return
+	}
+
+	if updateID == "" && cause.PinnedUpdateID != c.Param("updateId") {
+		h.sendError(c, http.StatusNotFound, "Update is not pinned")
+		return
+	}
+
+	if err := h.causeRepo.PinUpdate(c.Request.Context(), id, updateID); err != nil {
+		h.sendRepositoryError(c, err, "Update", "Error pinning update")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{"pinnedUpdateId": updateID})
+}
+
+// manageableUpdate obtiene la causa y la actualización de la ruta y verifica
+// que el usuario actual pueda hacer la acción sobre ella: el dueño de la causa
+// puede con cualquiera y cada colaborador con las que publicó mientras siga
+// siéndolo. Si no, envía el error y devuelve false.
+func (h *CauseHandler) manageableUpdate(c *gin.Context, action authz.Action, forbidden string) (*models.Cause, *models.Update, bool) {
+	id := c.Param("id")
+	cause, err := h.causeRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return nil, nil, false
+	}
+
+	update, err := h.causeRepo.GetUpdate(c.Request.Context(), id, c.Param("updateId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Update", "Error getting update")
+		return nil, nil, false
+	}
+
+	if !h.authorize(c, action, authz.UpdateResource(cause, update), forbidden) {
+		return nil, nil, false
+	}
+	return cause, update, true
+}
+
+// addCollaborator suma un Guiver a los colaboradores de la causa. Solo el
+// dueño y los administradores pueden hacerlo.
+func (h *CauseHandler) addCollaborator(c *gin.Context) {
+	var req AddCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cause, ok := h.collaboratorsCause(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.guiverRepo.GetByID(c.Request.Context(), req.GuiverID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			h.sendError(c, http.StatusBadRequest, "Collaborator guiver not found")
+		} else {
+			h.sendRepositoryError(c, err, "Guiver", "Error getting guiver")
+		}
+		return
+	}
+
+	if req.GuiverID != cause.GuiverID && !isCollaborator(cause, req.GuiverID) {
+		cause.Collaborators = append(cause.Collaborators, req.GuiverID)
+		if err := h.causeRepo.Update(c.Request.Context(), cause); err != nil {
+			h.sendRepositoryError(c, err, "Cause", "Error updating cause")
+			return
+		}
+	}
+
+	h.sendSuccess(c, cause)
+}
+
+// removeCollaborator quita un Guiver de los colaboradores de la causa. Solo el
+// dueño y los administradores pueden hacerlo.
+func (h *CauseHandler) removeCollaborator(c *gin.Context) {
+	cause, ok := h.collaboratorsCause(c)
+	if !ok {
+		return
+	}
+
+	guiverID := c.Param("guiverId")
+	if !isCollaborator(cause, guiverID) {
+		h.sendError(c, http.StatusNotFound, "Collaborator not found")
+		return
+	}
+
+	collaborators := []string{}
+	for _, id := range cause.Collaborators {
+		if id != guiverID {
+			collaborators = append(collaborators, id)
+		}
+	}
+	cause.Collaborators = collaborators
+	if err := h.causeRepo.Update(c.Request.Context(), cause); err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error updating cause")
+		return
+	}
+
+	h.sendSuccess(c, cause)
+}
+
+// collaboratorsCause obtiene la causa de la ruta y verifica que el usuario
+// actual pueda administrar sus colaboradores. Si no, envía el error y
+// devuelve false.
+func (h *CauseHandler) collaboratorsCause(c *gin.Context) (*models.Cause, bool) {
+	cause, err := h.causeRepo.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return nil, false
+	}
+
+	if !h.authorize(c, authz.ActionManageCollaborators, authz.CauseResource(cause), "Not authorized to manage collaborators of this cause") {
+		return nil, false
+	}
+	return cause, true
+}
+
+func isCollaborator(cause *models.Cause, guiverID string) bool {
+	for _, id := range cause.Collaborators {
+		if id == guiverID {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeExpenses valida los gastos informados y completa los valores por
+// defecto: la moneda de la causa y la categoría "other". Devuelve el mensaje de
+// error o "" si son válidos.
+func normalizeExpenses(expenses []models.Expense, currency string) string {
+	for i := range expenses {
+		expense := &expenses[i]
+		expense.Concept = strings.TrimSpace(expense.Concept)
+		expense.Amount.Currency = strings.ToUpper(expense.Amount.Currency)
+		if expense.Amount.Currency == "" {
+			expense.Amount.Currency = currency
+		}
+		if expense.Category == "" {
+			expense.Category = models.ExpenseCategoryOther
+		}
+
+		prefix := fmt.Sprintf("Expense %d: ", i+1)
+		switch {
+		case expense.Concept == "":
+			return prefix + "concept is required"
+		case !expense.Category.Valid():
+			return prefix + "invalid category"
+		case expense.Amount.Amount <= 0:
+			return prefix + "amount must be positive"
+		case !models.IsCurrency(expense.Amount.Currency):
+			return prefix + "invalid currency"
+		case expense.Date.IsZero():
+			return prefix + "date is required"
+		case expense.Date.After(time.Now()):
+			return prefix + "date cannot be in the future"
+		}
+	}
+	return ""
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/responses"
 	"github.com/guiver/internal/domain/models"
 	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/middleware"
 )
 
 // GuiverHandler maneja las rutas relacionadas con los Guivers
@@ -15,45 +19,69 @@ type GuiverHandler struct {
 }
 
 // NewGuiverHandler crea una nueva instancia de GuiverHandler
-func NewGuiverHandler(guiverRepo repository.GuiverRepository) *GuiverHandler {
+func NewGuiverHandler(guiverRepo repository.GuiverRepository, policy *authz.Policy) *GuiverHandler {
 	return &GuiverHandler{
-		guiverRepo: guiverRepo,
+		BaseHandler: BaseHandler{policy: policy},
+		guiverRepo:  guiverRepo,
 	}
 }
 
 // Register registra las rutas del handler
 func (h *GuiverHandler) Register(r *gin.RouterGroup) {
+	// Las rutas que modifican datos requieren el email verificado
+	verified := middleware.RequireVerifiedEmail()
 	guivers := r.Group("/guivers")
 	{
-		guivers.POST("", h.createGuiver)
+		guivers.POST("", verified, h.createGuiver)
+		guivers.GET("/me", h.getMe)
+		guivers.PUT("/me", verified, h.updateMe)
 		guivers.GET("/:id", h.getGuiver)
-		guivers.PUT("/:id", h.updateGuiver)
-		guivers.DELETE("/:id", h.deleteGuiver)
+		guivers.PUT("/:id", verified, h.updateGuiver)
+		guivers.DELETE("/:id", verified, h.deleteGuiver)
 		guivers.GET("/:id/causes", h.getGuiverCauses)
 		guivers.GET("/:id/products", h.getGuiverProducts)
 	}
 }
 
-// CreateGuiverRequest es la estructura para crear un Guiver
+// RegisterAdmin registra en el grupo de administración las rutas para
+// modificar o eliminar cualquier Guiver
+func (h *GuiverHandler) RegisterAdmin(r *gin.RouterGroup) {
+	r.PUT("/guivers/:id", h.updateGuiver)
+	r.DELETE("/guivers/:id", h.deleteGuiver)
+}
+
+// CreateGuiverRequest es la estructura para crear un Guiver. El email es el
+// verificado de la cuenta.
 type CreateGuiverRequest struct {
 	DisplayName string          `json:"displayName" binding:"required"`
-	Email      string          `json:"email" binding:"required,email"`
 	Type       models.GuiverType `json:"type" binding:"required"`
 	Bio        string          `json:"bio"`
 	WhatsApp   string          `json:"whatsApp"`
 	Instagram  string          `json:"instagram"`
 }
 
+// createGuiver crea el perfil de la cuenta autenticada, con su UID como ID.
+// Cada cuenta puede tener un solo perfil.
 func (h *GuiverHandler) createGuiver(c *gin.Context) {
 	var req CreateGuiverRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.sendError(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if req.Type != models.GuiverTypeHelper && req.Type != models.GuiverTypeEntrepreneur {
+		h.sendError(c, http.StatusBadRequest, "Invalid guiver type")
+		return
+	}
+	email := c.GetString(middleware.ContextEmail)
+	if email == "" {
+		h.sendError(c, http.StatusBadRequest, "The account has no email")
+		return
+	}
 
 	guiver := &models.Guiver{
+		ID:          c.GetString(middleware.ContextUserID),
 		DisplayName: req.DisplayName,
-		Email:      req.Email,
+		Email:      email,
 		Type:       req.Type,
 		Bio:        req.Bio,
 		WhatsApp:   req.WhatsApp,
@@ -61,7 +89,11 @@ func (h *GuiverHandler) createGuiver(c *gin.Context) {
 	}
 
 	if err := h.guiverRepo.Create(c.Request.Context(), guiver); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error creating guiver")
+		if errors.Is(err, repository.ErrConflict) {
+			h.sendErrorCode(c, http.StatusConflict, responses.CodeConflict, "This account already has a guiver profile")
+			return
+		}
+		h.sendRepositoryError(c, err, "Guiver", "Error creating guiver")
 		return
 	}
 
@@ -72,7 +104,18 @@ func (h *GuiverHandler) getGuiver(c *gin.Context) {
 	id := c.Param("id")
 	guiver, err := h.guiverRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Guiver not found")
+		h.sendRepositoryError(c, err, "Guiver", "Error getting guiver")
+		return
+	}
+
+	h.sendSuccess(c, guiver)
+}
+
+// getMe devuelve el perfil de la cuenta autenticada
+func (h *GuiverHandler) getMe(c *gin.Context) {
+	guiver, err := h.guiverRepo.GetByID(c.Request.Context(), c.GetString("userId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Guiver", "Error getting guiver")
 		return
 	}
 
@@ -87,8 +130,22 @@ type UpdateGuiverRequest struct {
 	Instagram  string `json:"instagram"`
 }
 
+// updateMe actualiza el perfil de la cuenta autenticada
+func (h *GuiverHandler) updateMe(c *gin.Context) {
+	h.update(c, c.GetString("userId"))
+}
+
+// updateGuiver actualiza un Guiver. Solo pueden hacerlo su dueño y los
+// administradores.
 func (h *GuiverHandler) updateGuiver(c *gin.Context) {
 	id := c.Param("id")
+	if !h.authorize(c, authz.ActionUpdateGuiver, authz.Owned(id), "Not authorized to update this guiver") {
+		return
+	}
+	h.update(c, id)
+}
+
+func (h *GuiverHandler) update(c *gin.Context, id string) {
 	var req UpdateGuiverRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.sendError(c, http.StatusBadRequest, "Invalid request body")
@@ -97,7 +154,7 @@ func (h *GuiverHandler) updateGuiver(c *gin.Context) {
 
 	guiver, err := h.guiverRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Guiver not found")
+		h.sendRepositoryError(c, err, "Guiver", "Error getting guiver")
 		return
 	}
 
@@ -116,17 +173,23 @@ func (h *GuiverHandler) updateGuiver(c *gin.Context) {
 	}
 
 	if err := h.guiverRepo.Update(c.Request.Context(), guiver); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error updating guiver")
+		h.sendRepositoryError(c, err, "Guiver", "Error updating guiver")
 		return
 	}
 
 	h.sendSuccess(c, guiver)
 }
 
+// deleteGuiver elimina un Guiver. Solo pueden hacerlo su dueño y los
+// administradores.
 func (h *GuiverHandler) deleteGuiver(c *gin.Context) {
 	id := c.Param("id")
+	if !h.authorize(c, authz.ActionDeleteGuiver, authz.Owned(id), "Not authorized to delete this guiver") {
+		return
+	}
+
 	if err := h.guiverRepo.Delete(c.Request.Context(), id); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error deleting guiver")
+		h.sendRepositoryError(c, err, "Guiver", "Error deleting guiver")
 		return
 	}
 
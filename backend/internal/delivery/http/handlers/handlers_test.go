@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/delivery/http/responses"
+)
+
+// newRouter crea un router de pruebas con las rutas de register bajo /api/v1
+func newRouter(register func(*gin.RouterGroup), middlewares ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	register(router.Group("/api/v1", middlewares...))
+	return router
+}
+
+// serve envía body como JSON; token, si no está vacío, va en la cabecera
+// Authorization
+func serve(t *testing.T, router http.Handler, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encoding body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// expect verifica el estado de la respuesta y decodifica sus datos en data,
+// si no es nil
+func expect(t *testing.T, w *httptest.ResponseRecorder, status int, data interface{}) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d: %s", w.Code, status, w.Body)
+	}
+	if data == nil {
+		return
+	}
+	resp := responses.Response{Data: data}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+}
+
+// errorCode devuelve el código de una respuesta de error
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp responses.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding error response: %v", err)
+	}
+	return resp.Code
+}
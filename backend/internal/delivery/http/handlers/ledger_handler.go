@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/pagination"
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/middleware"
+)
+
+// LedgerHandler maneja las rutas del libro de las causas
+type LedgerHandler struct {
+	BaseHandler
+	ledgerRepo repository.LedgerRepository
+	causeRepo  repository.CauseRepository
+}
+
+// NewLedgerHandler crea una nueva instancia de LedgerHandler
+func NewLedgerHandler(ledgerRepo repository.LedgerRepository, causeRepo repository.CauseRepository, policy *authz.Policy, cursors *pagination.CursorCodec) *LedgerHandler {
+	return &LedgerHandler{
+		BaseHandler: BaseHandler{cursors: cursors, policy: policy},
+		ledgerRepo:  ledgerRepo,
+		causeRepo:   causeRepo,
+	}
+}
+
+// Register registra las rutas del handler
+func (h *LedgerHandler) Register(r *gin.RouterGroup) {
+	// Las rutas que mueven dinero requieren el email verificado
+	verified := middleware.RequireVerifiedEmail()
+	causes := r.Group("/causes")
+	{
+		causes.GET("/:id/ledger", h.listEntries)
+		causes.POST("/:id/ledger", verified, h.addEntry)
+		causes.GET("/:id/statement", h.getStatement)
+		causes.GET("/:id/expenses/summary", h.getExpenseSummary)
+	}
+}
+
+// CreateLedgerEntryRequest es la estructura para registrar a mano una entrega
+// de fondos o un gasto. Los aportes, las ventas y los reembolsos se registran
+// solos al cobrarse. La moneda de Amount es opcional y por defecto es la de la
+// causa.
+type CreateLedgerEntryRequest struct {
+	Type        models.LedgerEntryType `json:"type" binding:"required"`
+	Amount      models.Money           `json:"amount"`
+	Description string                 `json:"description" binding:"required"`
+}
+
+// addEntry registra una entrega de fondos o un gasto en el libro de la causa,
+// sin superar el saldo disponible. Los gastos los rinde el dueño de la causa y
+// las entregas las registra el dueño si es organizador; los administradores
+// pueden hacer ambas cosas.
+func (h *LedgerHandler) addEntry(c *gin.Context) {
+	var req CreateLedgerEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Type != models.LedgerEntryDisbursement && req.Type != models.LedgerEntryExpense {
+		h.sendError(c, http.StatusBadRequest, "Only disbursements and expenses can be recorded manually")
+		return
+	}
+	if req.Amount.Amount <= 0 {
+		h.sendError(c, http.StatusBadRequest, "Amount must be positive")
+		return
+	}
+
+	cause, err := h.causeRepo.GetSummary(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+	action, forbidden := authz.ActionManageFunds, "Only the cause owner can record expenses"
+	if req.Type == models.LedgerEntryDisbursement {
+		action, forbidden = authz.ActionDisburseFunds, "Only organizers can record disbursements of their causes"
+	}
+	if !h.authorize(c, action, authz.CauseResource(cause), forbidden) {
+		return
+	}
+
+	amount := models.NewMoney(req.Amount.Amount, strings.ToUpper(req.Amount.Currency))
+	if amount.Currency == "" {
+		amount.Currency = cause.GoalAmount.Currency
+	}
+	if !models.IsCurrency(amount.Currency) {
+		h.sendError(c, http.StatusBadRequest, "Invalid currency")
+		return
+	}
+
+	entry := models.NewLedgerEntry(cause.ID, req.Type, amount)
+	entry.Description = req.Description
+	entry.CreatedBy = c.GetString("userId")
+	// Solo se entrega lo retenido y solo se rinde lo entregado: el repositorio
+	// lo verifica al registrar el movimiento
+	if err := h.ledgerRepo.Append(c.Request.Context(), entry); err != nil {
+		if errors.Is(err, repository.ErrInsufficientFunds) {
+			source, _ := entry.FundingAccount()
+			h.sendErrorCode(c, http.StatusConflict, responses.CodeConflict, "Amount exceeds the "+string(source)+" balance")
+			return
+		}
+		h.sendRepositoryError(c, err, "Ledger entry", "Error recording ledger entry")
+		return
+	}
+
+	h.sendSuccess(c, entry)
+}
+
+// listEntries lista los movimientos del libro de una causa, del más nuevo al
+// más antiguo y paginados por cursor. Acepta ?type= para filtrar por tipo.
+func (h *LedgerHandler) listEntries(c *gin.Context) {
+	after, _, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	cause, err := h.causeRepo.GetSummary(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	limit := h.limitParam(c)
+	entries, err := h.ledgerRepo.ListByCause(c.Request.Context(), cause.ID, repository.LedgerFilter{
+		Type:  models.LedgerEntryType(c.Query("type")),
+		Limit: limit + 1,
+		After: after,
+	})
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error listing ledger entries")
+		return
+	}
+
+	nextCursor := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[len(entries)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	h.sendCursorPage(c, entries, nextCursor, limit)
+}
+
+// getStatement devuelve el estado financiero de una causa por moneda,
+// calculado a partir de todos los movimientos de su libro
+func (h *LedgerHandler) getStatement(c *gin.Context) {
+	cause, err := h.causeRepo.GetSummary(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	balances, err := h.ledgerRepo.Balances(c.Request.Context(), cause.ID)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting ledger balances")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{
+		"causeId":    cause.ID,
+		"statements": balances.Statements(),
+		"balances":   balances,
+	})
+}
+
+// getExpenseSummary suma los gastos rendidos en las actualizaciones de una
+// causa por moneda y categoría y los concilia con lo recaudado según su libro
+func (h *LedgerHandler) getExpenseSummary(c *gin.Context) {
+	cause, err := h.causeRepo.GetSummary(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	totals, err := h.causeRepo.ExpenseTotals(c.Request.Context(), cause.ID)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting expense totals")
+		return
+	}
+	balances, err := h.ledgerRepo.Balances(c.Request.Context(), cause.ID)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting ledger balances")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{
+		"causeId":        cause.ID,
+		"categories":     totals,
+		"reconciliation": models.ReconcileExpenses(totals, balances.Statements()),
+	})
+}
+
+// recordPledge registra en el libro de la causa el cobro de un aporte pagado
+// y, si se reembolsó, también el reembolso. Los movimientos se derivan del
+// aporte, así que repetirlo no los duplica.
+func recordPledge(ctx context.Context, ledger repository.LedgerRepository, pledge *models.Pledge) error {
+	if pledge.Status != models.PledgeStatusPaid && pledge.Status != models.PledgeStatusRefunded {
+		return nil
+	}
+	reference := paymentReference(referencePledge, pledge.CauseID, pledge.ID)
+	entries := []*models.LedgerEntry{
+		ledgerEntry(pledge.CauseID, models.LedgerEntryDonation, pledge.Amount, reference, ""),
+	}
+	if pledge.Status == models.PledgeStatusRefunded {
+		entries = append(entries,
+			ledgerEntry(pledge.CauseID, models.LedgerEntryRefund, pledge.Amount, reference, "refund"))
+	}
+	return appendEntries(ctx, ledger, entries)
+}
+
+// recordOrder registra en el libro de la causa la parte donada de un pedido
+// pagado y, si se reembolsó o, con wasPaid, se canceló, su reembolso. Como
+// recordPledge, puede repetirse sin duplicar movimientos.
+func recordOrder(ctx context.Context, ledger repository.LedgerRepository, order *models.Order, wasPaid bool) error {
+	cancelled := order.Status == models.OrderStatusCancelled
+	if order.DonationAmount.Amount <= 0 || order.Status == models.OrderStatusPending || (cancelled && !wasPaid) {
+		return nil
+	}
+	reference := paymentReference(referenceOrder, order.ID)
+	entries := []*models.LedgerEntry{
+		ledgerEntry(order.CauseID, models.LedgerEntryProductDonation, order.DonationAmount, reference, ""),
+	}
+	if cancelled || order.Status == models.OrderStatusRefunded {
+		entries = append(entries,
+			ledgerEntry(order.CauseID, models.LedgerEntryProductRefund, order.DonationAmount, reference, "refund"))
+	}
+	for _, entry := range entries {
+		entry.GuiverID = order.SellerID
+	}
+	return appendEntries(ctx, ledger, entries)
+}
+
+// ledgerEntry crea un movimiento automático cuyo ID es la referencia de la
+// operación más suffix, para registrarlo una sola vez
+func ledgerEntry(causeID string, entryType models.LedgerEntryType, amount models.Money, reference, suffix string) *models.LedgerEntry {
+	entry := models.NewLedgerEntry(causeID, entryType, amount)
+	entry.Reference = reference
+	entry.ID = reference
+	if suffix != "" {
+		entry.ID += ":" + suffix
+	}
+	return entry
+}
+
+// appendEntries registra los movimientos, ignorando los ya registrados
+func appendEntries(ctx context.Context, ledger repository.LedgerRepository, entries []*models.LedgerEntry) error {
+	for _, entry := range entries {
+		if err := ledger.Append(ctx, entry); err != nil && !errors.Is(err, repository.ErrConflict) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/guiver/internal/delivery/http/pagination"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/middleware"
+	"github.com/guiver/internal/payments"
+)
+
+// OrderHandler maneja las rutas de los pedidos de productos y de las
+// donaciones que generan
+type OrderHandler struct {
+	BaseHandler
+	orderRepo   repository.OrderRepository
+	productRepo repository.ProductRepository
+	ledgerRepo  repository.LedgerRepository
+	payments    payments.Provider
+}
+
+// NewOrderHandler crea una nueva instancia de OrderHandler
+func NewOrderHandler(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, ledgerRepo repository.LedgerRepository, provider payments.Provider, cursors *pagination.CursorCodec) *OrderHandler {
+	return &OrderHandler{
+		BaseHandler: BaseHandler{cursors: cursors},
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		ledgerRepo:  ledgerRepo,
+		payments:    provider,
+	}
+}
+
+// Register registra las rutas del handler
+func (h *OrderHandler) Register(r *gin.RouterGroup) {
+	// Las rutas que mueven dinero requieren el email verificado
+	verified := middleware.RequireVerifiedEmail()
+	orders := r.Group("/orders")
+	{
+		orders.POST("", verified, h.createOrder)
+		orders.GET("", h.listOrders)
+		orders.GET("/:id", h.getOrder)
+		orders.POST("/:id/confirm", verified, h.confirmOrder)
+		orders.PUT("/:id/status", verified, h.updateOrderStatus)
+	}
+
+	r.GET("/causes/:id/donations", h.listCauseDonations)
+	r.GET("/causes/:id/donations/summary", h.causeDonationSummary)
+	r.GET("/guivers/:id/donations", h.listGuiverDonations)
+	r.GET("/guivers/:id/donations/summary", h.guiverDonationSummary)
+}
+
+// CreateOrderRequest es la estructura para comprar un producto
+type CreateOrderRequest struct {
+	ProductID string `json:"productId" binding:"required"`
+	VariantID string `json:"variantId"`
+	Quantity  int    `json:"quantity" binding:"omitempty,min=1"`
+}
+
+// createOrder registra un pedido pendiente e inicia su cobro en la pasarela.
+// El precio y el porcentaje de donación se fijan al momento de la compra, y
+// las unidades se descuentan del stock hasta que el pedido se cancele.
+func (h *OrderHandler) createOrder(c *gin.Context) {
+	var req CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	product, err := h.productRepo.GetByID(c.Request.Context(), req.ProductID)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Product", "Error getting product")
+		return
+	}
+	if product.Status == models.ProductStatusSoldOut {
+		h.sendError(c, http.StatusConflict, "Product is sold out")
+		return
+	}
+	if product.Status != models.ProductStatusActive {
+		h.sendError(c, http.StatusConflict, "Product is not available")
+		return
+	}
+	if err := product.Price.Validate(); err != nil {
+		h.sendError(c, http.StatusConflict, "Product has no valid price")
+		return
+	}
+	buyerID := c.GetString("userId")
+	if product.GuiverID == buyerID {
+		h.sendError(c, http.StatusBadRequest, "Cannot buy your own product")
+		return
+	}
+
+	product, err = h.productRepo.AdjustStock(c.Request.Context(), product.ID, req.VariantID, -req.Quantity)
+	switch {
+	case errors.Is(err, models.ErrOutOfStock):
+		h.sendError(c, http.StatusConflict, "Not enough stock")
+		return
+	case errors.Is(err, models.ErrVariantRequired):
+		h.sendError(c, http.StatusBadRequest, "A variant of the product is required")
+		return
+	case errors.Is(err, models.ErrUnknownVariant):
+		h.sendError(c, http.StatusBadRequest, "Invalid variant")
+		return
+	case err != nil:
+		h.sendRepositoryError(c, err, "Product", "Error reserving stock")
+		return
+	}
+
+	order := &models.Order{
+		ID:                 uuid.New().String(),
+		ProductID:          product.ID,
+		VariantID:          req.VariantID,
+		CauseID:            product.CauseID,
+		SellerID:           product.GuiverID,
+		BuyerID:            buyerID,
+		Quantity:           req.Quantity,
+		UnitPrice:          product.Price,
+		DonationPercentage: product.DonationPercentage,
+	}
+	order.CalculateTotals()
+
+	checkout, err := h.payments.CreateCheckout(c.Request.Context(), payments.CheckoutRequest{
+		Reference:   paymentReference(referenceOrder, order.ID),
+		Description: product.Title,
+		Amount:      order.Total.Amount,
+		Currency:    order.Total.Currency,
+	})
+	if err != nil {
+		releaseStock(c.Request.Context(), h.productRepo, order)
+		h.sendPaymentError(c, err, "Error creating checkout")
+		return
+	}
+
+	order.PaymentID = checkout.PaymentID
+	if err := h.orderRepo.Create(c.Request.Context(), order); err != nil {
+		releaseStock(c.Request.Context(), h.productRepo, order)
+		h.sendRepositoryError(c, err, "Order", "Error creating order")
+		return
+	}
+
+	order.CheckoutURL = checkout.URL
+	h.sendSuccess(c, order)
+}
+
+// listOrders lista los pedidos del Guiver autenticado, del más nuevo al más
+// antiguo y paginados por cursor. Con role=seller lista los que recibió como
+// emprendedor; si no, los que hizo como comprador.
+func (h *OrderHandler) listOrders(c *gin.Context) {
+	after, _, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	limit := h.limitParam(c)
+	filter := repository.OrderFilter{
+		Status: models.OrderStatus(c.Query("status")),
+		Limit:  limit + 1,
+		After:  after,
+	}
+	switch c.DefaultQuery("role", "buyer") {
+	case "buyer":
+		filter.BuyerID = c.GetString("userId")
+	case "seller":
+		filter.SellerID = c.GetString("userId")
+	default:
+		h.sendError(c, http.StatusBadRequest, "Invalid role")
+		return
+	}
+
+	orders, err := h.orderRepo.List(c.Request.Context(), filter)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Order", "Error listing orders")
+		return
+	}
+
+	nextCursor := ""
+	if len(orders) > limit {
+		orders = orders[:limit]
+		last := orders[len(orders)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	h.sendCursorPage(c, orders, nextCursor, limit)
+}
+
+// getOrder obtiene un pedido. Solo lo ven el comprador y el emprendedor.
+func (h *OrderHandler) getOrder(c *gin.Context) {
+	order, ok := h.participantOrder(c)
+	if !ok {
+		return
+	}
+	h.sendSuccess(c, order)
+}
+
+// confirmOrder captura el pago de un pedido cuando el comprador vuelve del
+// checkout, sin esperar la notificación de la pasarela
+func (h *OrderHandler) confirmOrder(c *gin.Context) {
+	order, ok := h.participantOrder(c)
+	if !ok {
+		return
+	}
+	if order.BuyerID != c.GetString("userId") {
+		h.sendError(c, http.StatusForbidden, "Only the buyer can confirm this order")
+		return
+	}
+	if order.Status == models.OrderStatusCancelled {
+		h.sendError(c, http.StatusConflict, "Order was cancelled")
+		return
+	}
+
+	order, err := captureOrder(c.Request.Context(), h.payments, h.orderRepo, order)
+	if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrConflict) {
+		h.sendRepositoryError(c, err, "Order", "Error confirming order")
+		return
+	}
+	if err != nil {
+		h.sendPaymentError(c, err, "Error capturing payment")
+		return
+	}
+	if err := recordOrder(c.Request.Context(), h.ledgerRepo, order, false); err != nil {
+		h.sendRepositoryError(c, err, "Order", "Error recording order in the ledger")
+		return
+	}
+
+	h.sendSuccess(c, order)
+}
+
+// UpdateOrderStatusRequest es la estructura para avanzar o cancelar un pedido
+type UpdateOrderStatusRequest struct {
+	Status models.OrderStatus `json:"status" binding:"required"`
+}
+
+// updateOrderStatus avanza un pedido pagado. El emprendedor lo marca como
+// enviado y entregado, y el comprador puede confirmar la entrega. El comprador
+// puede cancelarlo mientras está pendiente; el emprendedor, también después de
+// cobrarlo, en cuyo caso se reembolsa el pago.
+func (h *OrderHandler) updateOrderStatus(c *gin.Context) {
+	var req UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	order, ok := h.participantOrder(c)
+	if !ok {
+		return
+	}
+	seller := order.SellerID == c.GetString("userId")
+
+	switch req.Status {
+	case models.OrderStatusShipped:
+		if !seller {
+			h.sendError(c, http.StatusForbidden, "Only the seller can ship this order")
+			return
+		}
+	case models.OrderStatusDelivered:
+	case models.OrderStatusCancelled:
+		if !seller && order.Status != models.OrderStatusPending {
+			h.sendError(c, http.StatusForbidden, "Only the seller can cancel a paid order")
+			return
+		}
+	default:
+		h.sendError(c, http.StatusBadRequest, "Invalid status")
+		return
+	}
+	if order.Status != req.Status && !order.Status.CanBecome(req.Status) {
+		h.sendError(c, http.StatusConflict, "Cannot change order from "+string(order.Status)+" to "+string(req.Status))
+		return
+	}
+
+	wasCancelled := order.Status == models.OrderStatusCancelled
+	wasPaid := order.Status == models.OrderStatusPaid
+	if req.Status == models.OrderStatusCancelled && wasPaid {
+		if _, err := h.payments.Refund(c.Request.Context(), order.PaymentID, order.Total.Amount); err != nil {
+			h.sendPaymentError(c, err, "Error refunding payment")
+			return
+		}
+	}
+
+	order, err := h.orderRepo.UpdateStatus(c.Request.Context(), order.ID, req.Status)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Order", "Error updating order")
+		return
+	}
+	if order.Status == models.OrderStatusCancelled && !wasCancelled {
+		releaseStock(c.Request.Context(), h.productRepo, order)
+	}
+	if err := recordOrder(c.Request.Context(), h.ledgerRepo, order, wasPaid); err != nil {
+		h.sendRepositoryError(c, err, "Order", "Error recording order in the ledger")
+		return
+	}
+
+	h.sendSuccess(c, order)
+}
+
+// releaseStock devuelve al producto las unidades de un pedido que no se
+// concretó. Si falla solo se registra, porque el pedido ya no se puede
+// recuperar; los productos eliminados no tienen stock que devolver.
+func releaseStock(ctx context.Context, repo repository.ProductRepository, order *models.Order) {
+	_, err := repo.AdjustStock(ctx, order.ProductID, order.VariantID, order.Quantity)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("Error releasing stock of order %s: %v", order.ID, err)
+	}
+}
+
+// participantOrder obtiene el pedido de la ruta y verifica que el Guiver
+// autenticado sea su comprador o su emprendedor. Si no, responde el error y
+// devuelve false.
+func (h *OrderHandler) participantOrder(c *gin.Context) (*models.Order, bool) {
+	order, err := h.orderRepo.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Order", "Error getting order")
+		return nil, false
+	}
+	guiverID := c.GetString("userId")
+	if order.BuyerID != guiverID && order.SellerID != guiverID {
+		h.sendError(c, http.StatusForbidden, "Not authorized to access this order")
+		return nil, false
+	}
+	return order, true
+}
+
+func (h *OrderHandler) listCauseDonations(c *gin.Context) {
+	h.listDonations(c, repository.DonationFilter{CauseID: c.Param("id")})
+}
+
+func (h *OrderHandler) listGuiverDonations(c *gin.Context) {
+	h.listDonations(c, repository.DonationFilter{GuiverID: c.Param("id")})
+}
+
+func (h *OrderHandler) causeDonationSummary(c *gin.Context) {
+	h.donationSummary(c, repository.DonationFilter{CauseID: c.Param("id")})
+}
+
+func (h *OrderHandler) guiverDonationSummary(c *gin.Context) {
+	h.donationSummary(c, repository.DonationFilter{GuiverID: c.Param("id")})
+}
+
+// listDonations lista los movimientos del libro de las donaciones de los
+// pedidos según el filtro, del más nuevo al más antiguo y paginados por cursor
+func (h *OrderHandler) listDonations(c *gin.Context, filter repository.DonationFilter) {
+	after, _, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	limit := h.limitParam(c)
+	filter.Limit = limit + 1
+	filter.After = after
+	entries, err := h.ledgerRepo.ListDonations(c.Request.Context(), filter)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Donation", "Error listing donations")
+		return
+	}
+
+	nextCursor := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[len(entries)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	h.sendCursorPage(c, entries, nextCursor, limit)
+}
+
+// donationSummary devuelve el total donado por moneda según el filtro
+func (h *OrderHandler) donationSummary(c *gin.Context, filter repository.DonationFilter) {
+	totals, err := h.ledgerRepo.DonationTotals(c.Request.Context(), filter)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Donation", "Error getting donation totals")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{"totals": totals})
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/delivery/http/pagination"
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/identity"
+	"github.com/guiver/internal/identity/static"
+	"github.com/guiver/internal/infrastructure/memory"
+	"github.com/guiver/internal/middleware"
+	"github.com/guiver/internal/payments"
+	"github.com/guiver/internal/payments/fake"
+)
+
+// Productos de orderFixture: mug se cobra sin problemas y rejected, cuyo
+// precio termina en 99, lo rechaza la pasarela falsa
+const (
+	mugID      = "mug"
+	rejectedID = "rejected"
+	initStock  = 5
+)
+
+// orderFixture sirve las rutas de pedidos y de pagos sobre repositorios en
+// memoria, con los tokens "buyer" y "seller" verificados y "unverified" sin
+// verificar
+type orderFixture struct {
+	router   *gin.Engine
+	provider *fake.Provider
+	orders   *memory.OrderRepository
+	products *memory.ProductRepository
+	ledger   *memory.LedgerRepository
+}
+
+func newOrderFixture(t *testing.T) *orderFixture {
+	t.Helper()
+	f := &orderFixture{
+		provider: fake.New([]byte("webhook-secret"), "http://pay.test/checkout"),
+		orders:   memory.NewOrderRepository(),
+		products: memory.NewProductRepository(),
+		ledger:   memory.NewLedgerRepository(),
+	}
+	for id, price := range map[string]int64{mugID: 2000, rejectedID: 1099} {
+		stock := initStock
+		product := &models.Product{
+			ID:                 id,
+			GuiverID:           "seller",
+			CauseID:            "cause",
+			Title:              id,
+			Price:              models.NewMoney(price, "ARS"),
+			DonationPercentage: 10,
+			Status:             models.ProductStatusActive,
+			Stock:              &stock,
+		}
+		if err := f.products.Create(context.Background(), product); err != nil {
+			t.Fatalf("creating product %s: %v", id, err)
+		}
+	}
+
+	verifier := static.New()
+	verifier.Add("buyer", identity.Token{UID: "buyer", Claims: map[string]interface{}{"email_verified": true}})
+	verifier.Add("seller", identity.Token{UID: "seller", Claims: map[string]interface{}{"email_verified": true}})
+	verifier.Add("unverified", identity.Token{UID: "newcomer"})
+
+	f.router = newRouter(func(r *gin.RouterGroup) {
+		NewPaymentHandler(f.provider, nil, f.orders, f.products, f.ledger).Register(r)
+		protected := r.Group("", middleware.AuthMiddleware(verifier))
+		NewOrderHandler(f.orders, f.products, f.ledger, f.provider, pagination.NewCursorCodec([]byte("cursor-secret"))).Register(protected)
+	})
+	return f
+}
+
+// createOrder compra quantity unidades de productID como "buyer"
+func (f *orderFixture) createOrder(t *testing.T, productID string, quantity int) *models.Order {
+	t.Helper()
+	var order models.Order
+	w := serve(t, f.router, http.MethodPost, "/api/v1/orders", "buyer", CreateOrderRequest{ProductID: productID, Quantity: quantity})
+	expect(t, w, http.StatusOK, &order)
+	return &order
+}
+
+// setStatus cambia el estado de un pedido con el token indicado
+func (f *orderFixture) setStatus(t *testing.T, token, orderID string, status models.OrderStatus) *httptest.ResponseRecorder {
+	t.Helper()
+	return serve(t, f.router, http.MethodPut, "/api/v1/orders/"+orderID+"/status", token, UpdateOrderStatusRequest{Status: status})
+}
+
+// notify envía la notificación firmada de la pasarela sobre un pago
+func (f *orderFixture) notify(t *testing.T, paymentID string, eventType payments.EventType) {
+	t.Helper()
+	payload, header, err := f.provider.Notification(paymentID, eventType)
+	if err != nil {
+		t.Fatalf("Notification(%s): %v", paymentID, err)
+	}
+	expect(t, f.webhook(payload, header), http.StatusOK, nil)
+}
+
+func (f *orderFixture) webhook(payload []byte, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhook", bytes.NewReader(payload))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+// refund reembolsa el pago completo de un pedido en la pasarela, como lo haría
+// el emprendedor desde su panel, y envía la notificación
+func (f *orderFixture) refund(t *testing.T, order *models.Order) {
+	t.Helper()
+	if _, err := f.provider.Refund(context.Background(), order.PaymentID, order.Total.Amount); err != nil {
+		t.Fatalf("Refund(%s): %v", order.PaymentID, err)
+	}
+	f.notify(t, order.PaymentID, payments.EventRefunded)
+}
+
+func (f *orderFixture) stock(t *testing.T, productID string) int {
+	t.Helper()
+	product, err := f.products.GetByID(context.Background(), productID)
+	if err != nil {
+		t.Fatalf("GetByID(%s): %v", productID, err)
+	}
+	return *product.Stock
+}
+
+// entries devuelve los tipos de los movimientos de donaciones de la causa,
+// ordenados, y verifica que todos sean del emprendedor
+func (f *orderFixture) entries(t *testing.T) []models.LedgerEntryType {
+	t.Helper()
+	list, err := f.ledger.ListDonations(context.Background(), repository.DonationFilter{CauseID: "cause"})
+	if err != nil {
+		t.Fatalf("ListDonations: %v", err)
+	}
+	types := []models.LedgerEntryType{}
+	for _, entry := range list {
+		if entry.GuiverID != "seller" {
+			t.Errorf("entry %s guiverId = %q, want seller", entry.ID, entry.GuiverID)
+		}
+		types = append(types, entry.Type)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+func TestOrderFlows(t *testing.T) {
+	donated := []models.LedgerEntryType{models.LedgerEntryProductDonation}
+	refunded := []models.LedgerEntryType{models.LedgerEntryProductDonation, models.LedgerEntryProductRefund}
+
+	tests := []struct {
+		name    string
+		product string
+		steps   func(t *testing.T, f *orderFixture, order *models.Order)
+		status  models.OrderStatus
+		stock   int // stock del producto al terminar
+		entries []models.LedgerEntryType
+	}{
+		{
+			name:    "Pending",
+			product: mugID,
+			steps:   func(t *testing.T, f *orderFixture, order *models.Order) {},
+			status:  models.OrderStatusPending,
+			stock:   initStock - 1,
+			entries: []models.LedgerEntryType{},
+		},
+		{
+			name:    "PaidByWebhook",
+			product: mugID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				f.notify(t, order.PaymentID, payments.EventAuthorized)
+				f.notify(t, order.PaymentID, payments.EventCaptured)
+			},
+			status:  models.OrderStatusPaid,
+			stock:   initStock - 1,
+			entries: donated,
+		},
+		{
+			name:    "ConfirmedByBuyer",
+			product: mugID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				expect(t, serve(t, f.router, http.MethodPost, "/api/v1/orders/"+order.ID+"/confirm", "buyer", nil), http.StatusOK, nil)
+				// La notificación posterior no duplica la donación
+				f.notify(t, order.PaymentID, payments.EventCaptured)
+			},
+			status:  models.OrderStatusPaid,
+			stock:   initStock - 1,
+			entries: donated,
+		},
+		{
+			name:    "CancelledByBuyerWhilePending",
+			product: mugID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				expect(t, f.setStatus(t, "buyer", order.ID, models.OrderStatusCancelled), http.StatusOK, nil)
+			},
+			status:  models.OrderStatusCancelled,
+			stock:   initStock,
+			entries: []models.LedgerEntryType{},
+		},
+		{
+			name:    "CancelledBySellerAfterPayment",
+			product: mugID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				f.notify(t, order.PaymentID, payments.EventAuthorized)
+				expect(t, f.setStatus(t, "seller", order.ID, models.OrderStatusCancelled), http.StatusOK, nil)
+				// La pasarela notifica el reembolso que pidió la cancelación
+				f.notify(t, order.PaymentID, payments.EventRefunded)
+			},
+			status:  models.OrderStatusCancelled,
+			stock:   initStock,
+			entries: refunded,
+		},
+		{
+			name:    "RefundedBeforeShipping",
+			product: mugID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				f.notify(t, order.PaymentID, payments.EventAuthorized)
+				f.refund(t, order)
+			},
+			status:  models.OrderStatusCancelled,
+			stock:   initStock,
+			entries: refunded,
+		},
+		{
+			name:    "RefundedAfterShipping",
+			product: mugID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				f.notify(t, order.PaymentID, payments.EventAuthorized)
+				expect(t, f.setStatus(t, "seller", order.ID, models.OrderStatusShipped), http.StatusOK, nil)
+				f.refund(t, order)
+			},
+			status:  models.OrderStatusRefunded,
+			stock:   initStock - 1,
+			entries: refunded,
+		},
+		{
+			name:    "RefundedAfterDelivery",
+			product: mugID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				f.notify(t, order.PaymentID, payments.EventAuthorized)
+				expect(t, f.setStatus(t, "seller", order.ID, models.OrderStatusShipped), http.StatusOK, nil)
+				expect(t, f.setStatus(t, "buyer", order.ID, models.OrderStatusDelivered), http.StatusOK, nil)
+				f.refund(t, order)
+				// Una notificación repetida no cambia nada
+				f.notify(t, order.PaymentID, payments.EventRefunded)
+			},
+			status:  models.OrderStatusRefunded,
+			stock:   initStock - 1,
+			entries: refunded,
+		},
+		{
+			name:    "PaymentFailed",
+			product: rejectedID,
+			steps: func(t *testing.T, f *orderFixture, order *models.Order) {
+				f.notify(t, order.PaymentID, payments.EventAuthorized)
+				f.notify(t, order.PaymentID, payments.EventFailed)
+			},
+			status:  models.OrderStatusCancelled,
+			stock:   initStock,
+			entries: []models.LedgerEntryType{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t)
+			order := f.createOrder(t, tt.product, 1)
+			if order.CheckoutURL == "" || order.Total.Amount <= 0 {
+				t.Fatalf("created order = %+v, want checkout URL and total", order)
+			}
+			tt.steps(t, f, order)
+
+			got, err := f.orders.GetByID(context.Background(), order.ID)
+			if err != nil {
+				t.Fatalf("GetByID(%s): %v", order.ID, err)
+			}
+			if got.Status != tt.status {
+				t.Errorf("status = %s, want %s", got.Status, tt.status)
+			}
+			if stock := f.stock(t, tt.product); stock != tt.stock {
+				t.Errorf("stock = %d, want %d", stock, tt.stock)
+			}
+			if entries := f.entries(t); !reflect.DeepEqual(entries, tt.entries) {
+				t.Errorf("ledger entries = %v, want %v", entries, tt.entries)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderStatusRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		paid   bool
+		ship   bool
+		token  string
+		to     models.OrderStatus
+		status int
+	}{
+		{"BuyerCannotShip", true, false, "buyer", models.OrderStatusShipped, http.StatusForbidden},
+		{"BuyerCannotCancelPaid", true, false, "buyer", models.OrderStatusCancelled, http.StatusForbidden},
+		{"ShipPending", false, false, "seller", models.OrderStatusShipped, http.StatusConflict},
+		{"CancelShipped", true, true, "seller", models.OrderStatusCancelled, http.StatusConflict},
+		{"RefundedIsNotSet", true, true, "seller", models.OrderStatusRefunded, http.StatusBadRequest},
+		{"Unverified", true, false, "unverified", models.OrderStatusShipped, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t)
+			order := f.createOrder(t, mugID, 1)
+			if tt.paid {
+				f.notify(t, order.PaymentID, payments.EventAuthorized)
+			}
+			if tt.ship {
+				expect(t, f.setStatus(t, "seller", order.ID, models.OrderStatusShipped), http.StatusOK, nil)
+			}
+			expect(t, f.setStatus(t, tt.token, order.ID, tt.to), tt.status, nil)
+		})
+	}
+}
+
+func TestCreateOrderRequiresVerifiedEmail(t *testing.T) {
+	f := newOrderFixture(t)
+	w := serve(t, f.router, http.MethodPost, "/api/v1/orders", "unverified", CreateOrderRequest{ProductID: mugID})
+	expect(t, w, http.StatusForbidden, nil)
+	if code := errorCode(t, w); code != responses.CodeEmailNotVerified {
+		t.Errorf("code = %q, want %q", code, responses.CodeEmailNotVerified)
+	}
+	if stock := f.stock(t, mugID); stock != initStock {
+		t.Errorf("stock = %d, want %d", stock, initStock)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	f := newOrderFixture(t)
+	order := f.createOrder(t, mugID, 1)
+	payload, header, err := f.provider.Notification(order.PaymentID, payments.EventAuthorized)
+	if err != nil {
+		t.Fatalf("Notification: %v", err)
+	}
+	forged := bytes.Replace(payload, []byte(order.PaymentID), []byte("fake_pay_999999"), 1)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		header  http.Header
+	}{
+		{"Unsigned", payload, http.Header{}},
+		{"WrongSignature", payload, http.Header{fake.SignatureHeader: []string{"00ff"}}},
+		{"TamperedPayload", forged, header},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, f.webhook(tt.payload, tt.header), http.StatusUnauthorized, nil)
+		})
+	}
+
+	got, err := f.orders.GetByID(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.OrderStatusPending {
+		t.Errorf("status after rejected webhooks = %s, want %s", got.Status, models.OrderStatusPending)
+	}
+}
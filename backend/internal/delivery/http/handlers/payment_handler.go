@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/payments"
+)
+
+// Tipos de operación que se cobran, primer segmento de las referencias de pago
+const (
+	referencePledge = "pledge"
+	referenceOrder  = "order"
+)
+
+// PaymentHandler recibe las notificaciones de la pasarela de pagos
+type PaymentHandler struct {
+	BaseHandler
+	provider    payments.Provider
+	pledgeRepo  repository.PledgeRepository
+	orderRepo   repository.OrderRepository
+	productRepo repository.ProductRepository
+	ledgerRepo  repository.LedgerRepository
+}
+
+// NewPaymentHandler crea una nueva instancia de PaymentHandler
+func NewPaymentHandler(provider payments.Provider, pledgeRepo repository.PledgeRepository, orderRepo repository.OrderRepository, productRepo repository.ProductRepository, ledgerRepo repository.LedgerRepository) *PaymentHandler {
+	return &PaymentHandler{
+		provider:    provider,
+		pledgeRepo:  pledgeRepo,
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		ledgerRepo:  ledgerRepo,
+	}
+}
+
+// Register registra las rutas del handler. Son públicas: la pasarela se
+// autentica con la firma de cada notificación.
+func (h *PaymentHandler) Register(r *gin.RouterGroup) {
+	r.POST("/payments/webhook", h.webhook)
+}
+
+// webhook aplica una notificación de la pasarela. Las notificaciones repetidas
+// o que llegan fuera de orden se aceptan sin cambios; los errores inesperados
+// responden 500 para que la pasarela la reintente.
+func (h *PaymentHandler) webhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	event, err := h.provider.VerifyWebhook(payload, c.Request.Header)
+	if errors.Is(err, payments.ErrInvalidSignature) {
+		h.sendError(c, http.StatusUnauthorized, "Invalid webhook signature")
+		return
+	}
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid webhook payload")
+		return
+	}
+
+	resource := "Pledge"
+	kind, ids := parseReference(event.Payment.Reference)
+	switch {
+	case kind == referencePledge && len(ids) == 2:
+		err = h.applyPledgeEvent(c.Request.Context(), ids[0], ids[1], event)
+	case kind == referenceOrder && len(ids) == 1:
+		resource = "Order"
+		err = h.applyOrderEvent(c.Request.Context(), ids[0], event)
+	default:
+		log.Printf("Ignoring payment %s with unknown reference %q", event.Payment.ID, event.Payment.Reference)
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, repository.ErrConflict):
+		log.Printf("Ignoring %s for payment %s: %v", event.Type, event.Payment.ID, err)
+	default:
+		h.sendRepositoryError(c, err, resource, "Error applying payment notification")
+		return
+	}
+
+	h.sendSuccess(c, gin.H{"received": true})
+}
+
+// applyPledgeEvent actualiza el aporte según la notificación de su pago y lo
+// registra en el libro de la causa
+func (h *PaymentHandler) applyPledgeEvent(ctx context.Context, causeID, pledgeID string, event *payments.Event) error {
+	var pledge *models.Pledge
+	var err error
+	switch event.Type {
+	case payments.EventAuthorized:
+		if pledge, err = h.pledgeRepo.GetByID(ctx, causeID, pledgeID); err == nil {
+			pledge, err = capturePledge(ctx, h.provider, h.pledgeRepo, pledge)
+		}
+	case payments.EventCaptured:
+		pledge, err = h.pledgeRepo.UpdateStatus(ctx, causeID, pledgeID, models.PledgeStatusPaid)
+	case payments.EventFailed:
+		pledge, err = h.pledgeRepo.UpdateStatus(ctx, causeID, pledgeID, models.PledgeStatusFailed)
+	case payments.EventRefunded:
+		pledge, err = h.pledgeRepo.UpdateStatus(ctx, causeID, pledgeID, models.PledgeStatusRefunded)
+	}
+	if errors.Is(err, payments.ErrNotAuthorized) {
+		// La pasarela notificará el rechazo por separado
+		return nil
+	}
+	if err != nil || pledge == nil {
+		return err
+	}
+	return recordPledge(ctx, h.ledgerRepo, pledge)
+}
+
+// applyOrderEvent actualiza el pedido según la notificación de su pago y lo
+// registra en el libro de la causa. Un pago fallido o reembolsado cancela el
+// pedido, como indica reverseOrder.
+func (h *PaymentHandler) applyOrderEvent(ctx context.Context, orderID string, event *payments.Event) error {
+	var order *models.Order
+	var err error
+	switch event.Type {
+	case payments.EventAuthorized:
+		if order, err = h.orderRepo.GetByID(ctx, orderID); err == nil {
+			order, err = captureOrder(ctx, h.provider, h.orderRepo, order)
+		}
+	case payments.EventCaptured:
+		order, err = h.orderRepo.UpdateStatus(ctx, orderID, models.OrderStatusPaid)
+		err = refundCancelledOrder(ctx, h.provider, h.orderRepo, orderID, err)
+	case payments.EventFailed, payments.EventRefunded:
+		if order, err = h.orderRepo.GetByID(ctx, orderID); err == nil {
+			order, err = reverseOrder(ctx, h.orderRepo, h.productRepo, order, event.Type == payments.EventRefunded)
+		}
+	}
+	if errors.Is(err, payments.ErrNotAuthorized) {
+		return nil
+	}
+	if err != nil || order == nil {
+		return err
+	}
+	// Solo se reembolsan los pagos capturados: el pedido estaba pagado
+	return recordOrder(ctx, h.ledgerRepo, order, event.Type == payments.EventRefunded)
+}
+
+// captureOrder cobra el pago aprobado de un pedido pendiente y lo marca como
+// pagado. Los pedidos en otro estado se devuelven sin cambios.
+func captureOrder(ctx context.Context, provider payments.Provider, repo repository.OrderRepository, order *models.Order) (*models.Order, error) {
+	if order.Status != models.OrderStatusPending {
+		return order, nil
+	}
+	if _, err := provider.Capture(ctx, order.PaymentID); err != nil {
+		return nil, err
+	}
+	paid, err := repo.UpdateStatus(ctx, order.ID, models.OrderStatusPaid)
+	return paid, refundCancelledOrder(ctx, provider, repo, order.ID, err)
+}
+
+// reverseOrder cancela un pedido cuyo pago falló o, si refunded, se
+// reembolsó, y devuelve sus unidades al stock del producto. Los pedidos ya
+// enviados o entregados no se cancelan: un reembolso los marca como
+// reembolsados y sus unidades no vuelven al stock. Los pedidos ya cancelados o
+// reembolsados se devuelven sin cambios.
+func reverseOrder(ctx context.Context, orders repository.OrderRepository, products repository.ProductRepository, order *models.Order, refunded bool) (*models.Order, error) {
+	status := models.OrderStatusCancelled
+	switch {
+	case order.Status == models.OrderStatusCancelled || order.Status == models.OrderStatusRefunded:
+		return order, nil
+	case refunded && (order.Status == models.OrderStatusShipped || order.Status == models.OrderStatusDelivered):
+		status = models.OrderStatusRefunded
+	}
+	order, err := orders.UpdateStatus(ctx, order.ID, status)
+	if err == nil && status == models.OrderStatusCancelled {
+		releaseStock(ctx, products, order)
+	}
+	return order, err
+}
+
+// refundCancelledOrder reembolsa el pago de un pedido que el comprador canceló
+// mientras se cobraba, cuando marcarlo como pagado falló con err por
+// ErrConflict. Devuelve err sin cambios salvo que el reembolso falle; si el
+// pago ya se había reembolsado no hace nada.
+func refundCancelledOrder(ctx context.Context, provider payments.Provider, repo repository.OrderRepository, orderID string, err error) error {
+	if !errors.Is(err, repository.ErrConflict) {
+		return err
+	}
+	order, getErr := repo.GetByID(ctx, orderID)
+	if getErr != nil {
+		return getErr
+	}
+	if order.Status != models.OrderStatusCancelled {
+		return err
+	}
+	_, refundErr := provider.Refund(ctx, order.PaymentID, order.Total.Amount)
+	switch {
+	case refundErr == nil:
+		log.Printf("Refunded payment %s of cancelled order %s", order.PaymentID, order.ID)
+	case !errors.Is(refundErr, payments.ErrNotCaptured) && !errors.Is(refundErr, payments.ErrInvalidAmount):
+		return refundErr
+	}
+	return err
+}
+
+// capturePledge cobra el pago aprobado de un aporte pendiente y lo marca como
+// pagado. Los aportes ya pagados se devuelven sin cambios.
+func capturePledge(ctx context.Context, provider payments.Provider, repo repository.PledgeRepository, pledge *models.Pledge) (*models.Pledge, error) {
+	if pledge.Status != models.PledgeStatusPending {
+		return pledge, nil
+	}
+	if _, err := provider.Capture(ctx, pledge.PaymentID); err != nil {
+		return nil, err
+	}
+	return repo.UpdateStatus(ctx, pledge.CauseID, pledge.ID, models.PledgeStatusPaid)
+}
+
+// paymentReference arma la referencia de pago de una operación:
+// "pledge:causa:aporte" u "order:pedido"
+func paymentReference(kind string, ids ...string) string {
+	return strings.Join(append([]string{kind}, ids...), ":")
+}
+
+// parseReference separa una referencia de pago en su tipo y sus IDs
+func parseReference(reference string) (string, []string) {
+	parts := strings.Split(reference, ":")
+	return parts[0], parts[1:]
+}
+
+// sendPaymentError traduce un error de la pasarela de pagos a la respuesta
+// HTTP correspondiente. message se usa para los errores inesperados.
+func (h *BaseHandler) sendPaymentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, payments.ErrNotFound):
+		h.sendErrorCode(c, http.StatusNotFound, responses.CodeNotFound, "Payment not found")
+	case errors.Is(err, payments.ErrNotAuthorized):
+		h.sendErrorCode(c, http.StatusConflict, responses.CodePaymentNotApproved, "Payment has not been approved")
+	case errors.Is(err, payments.ErrNotCaptured):
+		h.sendErrorCode(c, http.StatusConflict, responses.CodeConflict, "Payment has not been captured")
+	case errors.Is(err, payments.ErrInvalidAmount):
+		h.sendError(c, http.StatusBadRequest, "Invalid payment amount")
+	default:
+		log.Printf("%s: %v", message, err)
+		h.sendErrorCode(c, http.StatusBadGateway, responses.CodePaymentProvider, message)
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/pagination"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/middleware"
+	"github.com/guiver/internal/payments"
+)
+
+// PledgeHandler maneja las rutas de los aportes a las causas
+type PledgeHandler struct {
+	BaseHandler
+	pledgeRepo repository.PledgeRepository
+	causeRepo  repository.CauseRepository
+	ledgerRepo repository.LedgerRepository
+	payments   payments.Provider
+}
+
+// NewPledgeHandler crea una nueva instancia de PledgeHandler
+func NewPledgeHandler(pledgeRepo repository.PledgeRepository, causeRepo repository.CauseRepository, ledgerRepo repository.LedgerRepository, provider payments.Provider, policy *authz.Policy, cursors *pagination.CursorCodec) *PledgeHandler {
+	return &PledgeHandler{
+		BaseHandler: BaseHandler{cursors: cursors, policy: policy},
+		pledgeRepo:  pledgeRepo,
+		causeRepo:   causeRepo,
+		ledgerRepo:  ledgerRepo,
+		payments:    provider,
+	}
+}
+
+// Register registra las rutas del handler
+func (h *PledgeHandler) Register(r *gin.RouterGroup) {
+	// Las rutas que mueven dinero requieren el email verificado
+	verified := middleware.RequireVerifiedEmail()
+	causes := r.Group("/causes")
+	{
+		causes.POST("/:id/pledges", verified, h.createPledge)
+		causes.GET("/:id/pledges", h.listPledges)
+		causes.POST("/:id/pledges/:pledgeId/confirm", verified, h.confirmPledge)
+		causes.POST("/:id/pledges/:pledgeId/refund", verified, h.refundPledge)
+	}
+}
+
+// CreatePledgeRequest es la estructura para aportar a una causa. La moneda de
+// Amount es opcional y, si se indica, debe ser la de la causa.
+type CreatePledgeRequest struct {
+	Amount    models.Money `json:"amount"`
+	Message   string       `json:"message"`
+	Anonymous bool         `json:"anonymous"`
+}
+
+// createPledge registra un aporte pendiente e inicia su cobro en la pasarela.
+// El aporte se suma a los totales de la causa recién cuando el pago se captura.
+func (h *PledgeHandler) createPledge(c *gin.Context) {
+	var req CreatePledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Amount.Amount <= 0 {
+		h.sendError(c, http.StatusBadRequest, "Pledge amount must be positive")
+		return
+	}
+
+	id := c.Param("id")
+	cause, err := h.causeRepo.GetSummary(c.Request.Context(), id)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	if !cause.AcceptsPledges(time.Now()) {
+		h.sendError(c, http.StatusConflict, "Cause is not accepting pledges")
+		return
+	}
+	currency := cause.GoalAmount.Currency
+	if req.Amount.Currency != "" && strings.ToUpper(req.Amount.Currency) != currency {
+		h.sendError(c, http.StatusBadRequest, "Pledge currency must be "+currency)
+		return
+	}
+
+	pledge := &models.Pledge{
+		ID:        uuid.New().String(),
+		CauseID:   id,
+		GuiverID:  c.GetString("userId"),
+		Amount:    models.NewMoney(req.Amount.Amount, currency),
+		Message:   req.Message,
+		Anonymous: req.Anonymous,
+		Status:    models.PledgeStatusPending,
+	}
+	checkout, err := h.payments.CreateCheckout(c.Request.Context(), payments.CheckoutRequest{
+		Reference:   paymentReference(referencePledge, id, pledge.ID),
+		Description: "Aporte a " + cause.Title,
+		Amount:      pledge.Amount.Amount,
+		Currency:    pledge.Amount.Currency,
+	})
+	if err != nil {
+		h.sendPaymentError(c, err, "Error creating checkout")
+		return
+	}
+
+	pledge.PaymentID = checkout.PaymentID
+	if err := h.pledgeRepo.Create(c.Request.Context(), pledge); err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error creating pledge")
+		return
+	}
+
+	pledge.CheckoutURL = checkout.URL
+	h.sendSuccess(c, pledge)
+}
+
+// confirmPledge captura el pago de un aporte cuando el pagador vuelve del
+// checkout, sin esperar la notificación de la pasarela. Solo puede hacerlo el
+// autor del aporte.
+func (h *PledgeHandler) confirmPledge(c *gin.Context) {
+	pledge, err := h.pledgeRepo.GetByID(c.Request.Context(), c.Param("id"), c.Param("pledgeId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Pledge", "Error getting pledge")
+		return
+	}
+	if pledge.GuiverID != c.GetString("userId") {
+		h.sendError(c, http.StatusForbidden, "Only the pledger can confirm this pledge")
+		return
+	}
+	if pledge.Status == models.PledgeStatusFailed {
+		h.sendError(c, http.StatusConflict, "Pledge payment failed")
+		return
+	}
+
+	pledge, err = capturePledge(c.Request.Context(), h.payments, h.pledgeRepo, pledge)
+	if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrConflict) {
+		h.sendRepositoryError(c, err, "Pledge", "Error confirming pledge")
+		return
+	}
+	if err != nil {
+		h.sendPaymentError(c, err, "Error capturing payment")
+		return
+	}
+	if err := recordPledge(c.Request.Context(), h.ledgerRepo, pledge); err != nil {
+		h.sendRepositoryError(c, err, "Pledge", "Error recording pledge in the ledger")
+		return
+	}
+
+	h.sendSuccess(c, pledge)
+}
+
+// refundPledge reembolsa un aporte pagado y lo descuenta de los totales de la
+// causa. Pueden hacerlo el dueño de la causa y los administradores. Repetirlo
+// sobre un aporte ya reembolsado solo completa el libro, por si falló al
+// registrarse.
+func (h *PledgeHandler) refundPledge(c *gin.Context) {
+	ctx := c.Request.Context()
+	cause, err := h.causeRepo.GetSummary(ctx, c.Param("id"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+	if !h.authorize(c, authz.ActionManageFunds, authz.CauseResource(cause), "Only the cause owner can refund pledges") {
+		return
+	}
+
+	pledge, err := h.pledgeRepo.GetByID(ctx, cause.ID, c.Param("pledgeId"))
+	if err != nil {
+		h.sendRepositoryError(c, err, "Pledge", "Error getting pledge")
+		return
+	}
+	if pledge.Status != models.PledgeStatusPaid && pledge.Status != models.PledgeStatusRefunded {
+		h.sendError(c, http.StatusConflict, "Only paid pledges can be refunded")
+		return
+	}
+
+	if pledge.Status == models.PledgeStatusPaid {
+		if _, err := h.payments.Refund(ctx, pledge.PaymentID, pledge.Amount.Amount); err != nil {
+			h.sendPaymentError(c, err, "Error refunding payment")
+			return
+		}
+		pledge, err = h.pledgeRepo.UpdateStatus(ctx, cause.ID, pledge.ID, models.PledgeStatusRefunded)
+		if err != nil {
+			h.sendRepositoryError(c, err, "Pledge", "Error refunding pledge")
+			return
+		}
+	}
+	if err := recordPledge(ctx, h.ledgerRepo, pledge); err != nil {
+		h.sendRepositoryError(c, err, "Pledge", "Error recording refund in the ledger")
+		return
+	}
+
+	h.sendSuccess(c, pledge)
+}
+
+// listPledges lista los aportes de una causa, del más nuevo al más antiguo y
+// paginados por cursor. De los aportes anónimos solo su autor ve el Guiver.
+func (h *PledgeHandler) listPledges(c *gin.Context) {
+	after, _, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+
+	limit := h.limitParam(c)
+	// Se pide un elemento de más para saber si hay otra página
+	pledges, err := h.pledgeRepo.ListByCause(c.Request.Context(), c.Param("id"), repository.PledgeFilter{
+		Limit: limit + 1,
+		After: after,
+	})
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error listing pledges")
+		return
+	}
+
+	nextCursor := ""
+	if len(pledges) > limit {
+		pledges = pledges[:limit]
+		last := pledges[len(pledges)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	guiverID := c.GetString("userId")
+	for _, pledge := range pledges {
+		if pledge.Anonymous && pledge.GuiverID != guiverID {
+			pledge.GuiverID = ""
+		}
+	}
+
+	h.sendCursorPage(c, pledges, nextCursor, limit)
+}
+
+// goalError valida la meta de recaudación de una causa y devuelve el mensaje
+// de error, o "" si es válida. La moneda ya debe estar en mayúsculas.
+func goalError(goal models.Money, deadline *time.Time) string {
+	switch {
+	case goal.Amount < 0:
+		return "Goal amount cannot be negative"
+	case goal.Amount > 0 && goal.Currency == "":
+		return "Currency is required to set a goal"
+	case goal.Currency != "" && !models.IsCurrency(goal.Currency):
+		return "Invalid currency"
+	case deadline != nil && !deadline.After(time.Now()):
+		return "Deadline must be in the future"
+	}
+	return ""
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/pagination"
 	"github.com/guiver/internal/domain/models"
 	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/middleware"
+	"github.com/guiver/internal/exchange"
 )
 
 // ProductHandler maneja las rutas relacionadas con los productos
@@ -14,37 +20,56 @@ type ProductHandler struct {
 	BaseHandler
 	productRepo repository.ProductRepository
 	causeRepo   repository.CauseRepository
+	rates       exchange.Source
+	currency    string
 }
 
-// NewProductHandler crea una nueva instancia de ProductHandler
-func NewProductHandler(productRepo repository.ProductRepository, causeRepo repository.CauseRepository) *ProductHandler {
+// NewProductHandler crea una nueva instancia de ProductHandler. currency es la
+// moneda de los precios que no indican otra y de los filtros por precio.
+func NewProductHandler(productRepo repository.ProductRepository, causeRepo repository.CauseRepository, rates exchange.Source, currency string, policy *authz.Policy, cursors *pagination.CursorCodec) *ProductHandler {
 	return &ProductHandler{
+		BaseHandler: BaseHandler{cursors: cursors, policy: policy},
 		productRepo: productRepo,
 		causeRepo:   causeRepo,
+		rates:       rates,
+		currency:    currency,
 	}
 }
 
 // Register registra las rutas del handler
 func (h *ProductHandler) Register(r *gin.RouterGroup) {
+	// Las rutas que modifican datos requieren el email verificado
+	verified := middleware.RequireVerifiedEmail()
 	products := r.Group("/products")
 	{
-		products.POST("", h.createProduct)
+		products.POST("", verified, h.createProduct)
 		products.GET("", h.listProducts)
 		products.GET("/:id", h.getProduct)
-		products.PUT("/:id", h.updateProduct)
-		products.DELETE("/:id", h.deleteProduct)
+		products.PUT("/:id", verified, h.updateProduct)
+		products.DELETE("/:id", verified, h.deleteProduct)
 		products.GET("/cause/:causeId", h.getProductsByCause)
 	}
 }
 
-// CreateProductRequest es la estructura para crear un producto
+// RegisterAdmin registra en el grupo de administración las rutas para
+// modificar o eliminar cualquier producto
+func (h *ProductHandler) RegisterAdmin(r *gin.RouterGroup) {
+	r.PUT("/products/:id", h.updateProduct)
+	r.DELETE("/products/:id", h.deleteProduct)
+}
+
+// CreateProductRequest es la estructura para crear un producto. Price va en
+// unidades menores; sin moneda, se usa la de la causa. Sin Stock ni Variants
+// el producto no controla existencias; con Variants, Stock se ignora.
 type CreateProductRequest struct {
 	CauseID           string           `json:"causeId" binding:"required"`
 	Title             string           `json:"title" binding:"required"`
 	Description       string           `json:"description" binding:"required"`
-	Price             float64          `json:"price" binding:"required"`
+	Price             models.Money     `json:"price"`
 	DonationPercentage int             `json:"donationPercentage" binding:"required,min=1,max=100"`
 	ImageURLs         []string         `json:"imageUrls"`
+	Stock             *int             `json:"stock"`
+	Variants          []models.ProductVariant `json:"variants"`
 	ContactInfo       models.ContactInfo `json:"contactInfo"`
 }
 
@@ -57,10 +82,29 @@ func (h *ProductHandler) createProduct(c *gin.Context) {
 
 	// Verificar que la causa existe
 	cause, err := h.causeRepo.GetByID(c.Request.Context(), req.CauseID)
-	if err != nil {
+	if errors.Is(err, repository.ErrNotFound) {
 		h.sendError(c, http.StatusBadRequest, "Invalid cause ID")
 		return
 	}
+	if err != nil {
+		h.sendRepositoryError(c, err, "Cause", "Error getting cause")
+		return
+	}
+
+	if req.Price.Currency == "" {
+		req.Price.Currency = cause.GoalAmount.Currency
+	}
+	if req.Price.Currency == "" {
+		req.Price.Currency = h.currency
+	}
+	if msg := priceError(req.Price); msg != "" {
+		h.sendError(c, http.StatusBadRequest, msg)
+		return
+	}
+	if msg := stockError(req.Stock, req.Variants); msg != "" {
+		h.sendError(c, http.StatusBadRequest, msg)
+		return
+	}
 
 	guiverID, _ := c.Get("userId")
 	product := &models.Product{
@@ -71,12 +115,18 @@ func (h *ProductHandler) createProduct(c *gin.Context) {
 		Price:             req.Price,
 		DonationPercentage: req.DonationPercentage,
 		ImageURLs:         req.ImageURLs,
+		Stock:             req.Stock,
+		Variants:          req.Variants,
 		ContactInfo:       req.ContactInfo,
-		Status:            "active",
+		Status:            models.ProductStatusActive,
+	}
+	if len(product.Variants) > 0 {
+		product.Stock = nil
 	}
+	product.SyncStock()
 
 	if err := h.productRepo.Create(c.Request.Context(), product); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error creating product")
+		h.sendRepositoryError(c, err, "Product", "Error creating product")
 		return
 	}
 
@@ -86,51 +136,191 @@ func (h *ProductHandler) createProduct(c *gin.Context) {
 	})
 }
 
-func (h *ProductHandler) listProducts(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	minPrice, _ := strconv.ParseFloat(c.Query("minPrice"), 64)
-	maxPrice, _ := strconv.ParseFloat(c.Query("maxPrice"), 64)
+// priceError devuelve el motivo por el que el precio no es válido, o "" si lo es
+func priceError(price models.Money) string {
+	if !models.IsCurrency(price.Currency) {
+		return "Price currency must be a supported ISO 4217 code"
+	}
+	if price.Amount <= 0 {
+		return "Price amount must be positive"
+	}
+	return ""
+}
+
+// stockError valida las existencias de un producto y asigna un ID a las
+// variantes que no lo tienen. Devuelve el mensaje de error, o "" si son válidas.
+func stockError(stock *int, variants []models.ProductVariant) string {
+	if stock != nil && *stock < 0 {
+		return "Stock cannot be negative"
+	}
+	seen := make(map[string]bool, len(variants))
+	for i := range variants {
+		variant := &variants[i]
+		switch {
+		case variant.Size == "" && variant.Color == "":
+			return "Variants must have a size or a color"
+		case variant.Stock < 0:
+			return "Variant stock cannot be negative"
+		case seen[variant.ID]:
+			return "Duplicate variant ID " + variant.ID
+		}
+		if variant.ID == "" {
+			variant.ID = uuid.New().String()
+		}
+		seen[variant.ID] = true
+	}
+	return ""
+}
 
+// listProducts lista los productos. Con el parámetro cursor (vacío para la
+// primera página) pagina por cursor; si no, mantiene la paginación por page y limit.
+// minPrice y maxPrice van en unidades menores de currency (por defecto, la
+// configurada) y también incluyen los productos con precio en otras monedas
+// cuyo equivalente cae en el rango.
+func (h *ProductHandler) listProducts(c *gin.Context) {
 	filter := repository.ProductFilter{
 		CauseID:  c.Query("causeId"),
 		GuiverID: c.Query("guiverId"),
 		Search:   c.Query("search"),
-		MinPrice: minPrice,
-		MaxPrice: maxPrice,
-		Limit:    limit,
-		Offset:   (page - 1) * limit,
 	}
+	if c.Query("minPrice") != "" || c.Query("maxPrice") != "" {
+		ranges, status, msg := h.priceRanges(c)
+		if msg != "" {
+			h.sendError(c, status, msg)
+			return
+		}
+		filter.PriceRanges = ranges
+	}
+
+	after, cursorMode, err := h.cursorParam(c)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if cursorMode {
+		h.listProductsByCursor(c, filter, after)
+		return
+	}
+
+	page, limit := h.pageParams(c)
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
 
 	products, err := h.productRepo.List(c.Request.Context(), filter)
 	if err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error listing products")
+		h.sendRepositoryError(c, err, "Product", "Error listing products")
+		return
+	}
+
+	total, err := h.productRepo.Count(c.Request.Context(), filter)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Product", "Error counting products")
 		return
 	}
 
-	// TODO: Implementar el conteo total para la paginación
-	h.sendPaginated(c, products, int64(len(products)), page, limit)
+	h.sendPaginated(c, products, total, page, limit)
+}
+
+// priceRanges convierte el rango de precios pedido a cada moneda cotizada. Si
+// falla, devuelve el código y el mensaje del error.
+func (h *ProductHandler) priceRanges(c *gin.Context) ([]repository.PriceRange, int, string) {
+	currency := c.DefaultQuery("currency", h.currency)
+	if !models.IsCurrency(currency) {
+		return nil, http.StatusBadRequest, "Invalid currency"
+	}
+	var bounds [2]int64
+	for i, param := range []string{"minPrice", "maxPrice"} {
+		if value := c.Query(param); value != "" {
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || amount < 0 {
+				return nil, http.StatusBadRequest, "Invalid " + param
+			}
+			bounds[i] = amount
+		}
+	}
+
+	rates, err := h.rates.Rates(c.Request.Context())
+	if err != nil {
+		return nil, http.StatusServiceUnavailable, "Exchange rates unavailable"
+	}
+	currencies := rates.Currencies()
+	if _, err := rates.Convert(models.NewMoney(0, currency), rates.Base); err != nil {
+		// Sin cotización solo se comparan los precios en la misma moneda
+		currencies = []string{currency}
+	}
+
+	ranges := make([]repository.PriceRange, 0, len(currencies))
+	for _, target := range currencies {
+		priceRange := repository.PriceRange{Currency: target}
+		// Un extremo en 0 queda abierto, por lo que no se convierte
+		if bounds[0] > 0 {
+			min, err := rates.Convert(models.NewMoney(bounds[0], currency), target)
+			if err != nil {
+				continue
+			}
+			priceRange.Min = min.Amount
+		}
+		if bounds[1] > 0 {
+			max, err := rates.Convert(models.NewMoney(bounds[1], currency), target)
+			if err != nil {
+				continue
+			}
+			// Un máximo convertido a 0 dejaría el extremo abierto
+			if max.Amount == 0 {
+				continue
+			}
+			priceRange.Max = max.Amount
+		}
+		ranges = append(ranges, priceRange)
+	}
+	return ranges, 0, ""
+}
+
+func (h *ProductHandler) listProductsByCursor(c *gin.Context, filter repository.ProductFilter, after *repository.Cursor) {
+	limit := h.limitParam(c)
+	// Se pide un elemento de más para saber si hay otra página
+	filter.Limit = limit + 1
+	filter.After = after
+
+	products, err := h.productRepo.List(c.Request.Context(), filter)
+	if err != nil {
+		h.sendRepositoryError(c, err, "Product", "Error listing products")
+		return
+	}
+
+	nextCursor := ""
+	if len(products) > limit {
+		products = products[:limit]
+		last := products[len(products)-1]
+		nextCursor = h.encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	h.sendCursorPage(c, products, nextCursor, limit)
 }
 
 func (h *ProductHandler) getProduct(c *gin.Context) {
 	id := c.Param("id")
 	product, err := h.productRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Product not found")
+		h.sendRepositoryError(c, err, "Product", "Error getting product")
 		return
 	}
 
 	h.sendSuccess(c, product)
 }
 
-// UpdateProductRequest es la estructura para actualizar un producto
+// UpdateProductRequest es la estructura para actualizar un producto. Variants
+// reemplaza todas las variantes; una lista vacía las elimina. El estado
+// agotado no se elige: el producto pasa a él y vuelve a activo según su stock.
 type UpdateProductRequest struct {
 	Title             string           `json:"title"`
 	Description       string           `json:"description"`
-	Price             float64          `json:"price"`
+	Price             *models.Money    `json:"price"`
 	DonationPercentage int             `json:"donationPercentage" binding:"min=1,max=100"`
 	ImageURLs         []string         `json:"imageUrls"`
-	Status            string           `json:"status"`
+	Status            models.ProductStatus `json:"status"`
+	Stock             *int             `json:"stock"`
+	Variants          *[]models.ProductVariant `json:"variants"`
 	ContactInfo       models.ContactInfo `json:"contactInfo"`
 }
 
@@ -144,14 +334,12 @@ func (h *ProductHandler) updateProduct(c *gin.Context) {
 
 	product, err := h.productRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Product not found")
+		h.sendRepositoryError(c, err, "Product", "Error getting product")
 		return
 	}
 
-	// Verificar que el usuario actual es el dueño del producto
-	guiverID, _ := c.Get("userId")
-	if product.GuiverID != guiverID.(string) {
-		h.sendError(c, http.StatusForbidden, "Not authorized to update this product")
+	// Verificar que el usuario actual puede modificar el producto
+	if !h.authorize(c, authz.ActionUpdateProduct, authz.Owned(product.GuiverID), "Not authorized to update this product") {
 		return
 	}
 
@@ -162,8 +350,15 @@ func (h *ProductHandler) updateProduct(c *gin.Context) {
 	if req.Description != "" {
 		product.Description = req.Description
 	}
-	if req.Price > 0 {
-		product.Price = req.Price
+	if req.Price != nil {
+		if req.Price.Currency == "" {
+			req.Price.Currency = product.Price.Currency
+		}
+		if msg := priceError(*req.Price); msg != "" {
+			h.sendError(c, http.StatusBadRequest, msg)
+			return
+		}
+		product.Price = *req.Price
 	}
 	if req.DonationPercentage > 0 {
 		product.DonationPercentage = req.DonationPercentage
@@ -172,14 +367,37 @@ func (h *ProductHandler) updateProduct(c *gin.Context) {
 		product.ImageURLs = req.ImageURLs
 	}
 	if req.Status != "" {
+		if req.Status != models.ProductStatusActive && req.Status != models.ProductStatusPaused {
+			h.sendError(c, http.StatusBadRequest, "Invalid status")
+			return
+		}
 		product.Status = req.Status
 	}
+	// Las existencias solo se reemplazan si la petición las cambia; si no, se
+	// conservan las guardadas, que los pedidos pueden haber descontado
+	replaceStock := req.Stock != nil || req.Variants != nil
+	if replaceStock {
+		var variants []models.ProductVariant
+		if req.Variants != nil {
+			variants = *req.Variants
+		}
+		if msg := stockError(req.Stock, variants); msg != "" {
+			h.sendError(c, http.StatusBadRequest, msg)
+			return
+		}
+		if req.Variants != nil {
+			product.Variants = variants
+		}
+		if req.Stock != nil && len(product.Variants) == 0 {
+			product.Stock = req.Stock
+		}
+	}
 	if req.ContactInfo != (models.ContactInfo{}) {
 		product.ContactInfo = req.ContactInfo
 	}
 
-	if err := h.productRepo.Update(c.Request.Context(), product); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error updating product")
+	if err := h.productRepo.Update(c.Request.Context(), product, replaceStock); err != nil {
+		h.sendRepositoryError(c, err, "Product", "Error updating product")
 		return
 	}
 
@@ -189,21 +407,19 @@ func (h *ProductHandler) updateProduct(c *gin.Context) {
 func (h *ProductHandler) deleteProduct(c *gin.Context) {
 	id := c.Param("id")
 	
-	// Verificar que el usuario actual es el dueño del producto
+	// Verificar que el usuario actual puede eliminar el producto
 	product, err := h.productRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		h.sendError(c, http.StatusNotFound, "Product not found")
+		h.sendRepositoryError(c, err, "Product", "Error getting product")
 		return
 	}
 
-	guiverID, _ := c.Get("userId")
-	if product.GuiverID != guiverID.(string) {
-		h.sendError(c, http.StatusForbidden, "Not authorized to delete this product")
+	if !h.authorize(c, authz.ActionDeleteProduct, authz.Owned(product.GuiverID), "Not authorized to delete this product") {
 		return
 	}
 
 	if err := h.productRepo.Delete(c.Request.Context(), id); err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error deleting product")
+		h.sendRepositoryError(c, err, "Product", "Error deleting product")
 		return
 	}
 
@@ -214,7 +430,7 @@ func (h *ProductHandler) getProductsByCause(c *gin.Context) {
 	causeID := c.Param("causeId")
 	products, err := h.productRepo.GetByCauseID(c.Request.Context(), causeID)
 	if err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Error getting products")
+		h.sendRepositoryError(c, err, "Product", "Error getting products")
 		return
 	}
 
@@ -0,0 +1,77 @@
+// Package pagination codifica los cursores opacos que se entregan a los
+// clientes para paginar listados.
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/guiver/internal/domain/repository"
+)
+
+// ErrInvalidCursor indica que el cursor está mal formado o su firma no es válida
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorCodec firma y verifica cursores con HMAC-SHA256 para que los clientes
+// no puedan fabricarlos ni alterarlos
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec crea un CursorCodec con la clave indicada
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+type cursorPayload struct {
+	CreatedAt int64  `json:"t"`
+	ID        string `json:"i"`
+}
+
+// Encode convierte el cursor en un token opaco
+func (c *CursorCodec) Encode(cursor repository.Cursor) string {
+	payload, _ := json.Marshal(cursorPayload{
+		CreatedAt: cursor.CreatedAt.UnixNano(),
+		ID:        cursor.ID,
+	})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(encoded))
+}
+
+// Decode verifica la firma del token y devuelve el cursor que contiene
+func (c *CursorCodec) Decode(token string) (*repository.Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, c.sign(encoded)) {
+		return nil, ErrInvalidCursor
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &repository.Cursor{
+		CreatedAt: time.Unix(0, payload.CreatedAt).UTC(),
+		ID:        payload.ID,
+	}, nil
+}
+
+func (c *CursorCodec) sign(data string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
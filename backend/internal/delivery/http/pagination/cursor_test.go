@@ -0,0 +1,60 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/repository"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	tests := []repository.Cursor{
+		{CreatedAt: time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC), ID: "c1"},
+		{CreatedAt: time.Unix(0, 0).UTC(), ID: "with.dots:and/slashes"},
+		{CreatedAt: time.Date(1999, 12, 31, 23, 59, 59, 0, time.FixedZone("ART", -3*3600)), ID: "ñandú"},
+	}
+	for _, cursor := range tests {
+		got, err := codec.Decode(codec.Encode(cursor))
+		if err != nil {
+			t.Fatalf("Decode(Encode(%+v)): %v", cursor, err)
+		}
+		if !got.CreatedAt.Equal(cursor.CreatedAt) || got.CreatedAt.Location() != time.UTC || got.ID != cursor.ID {
+			t.Errorf("Decode(Encode(%+v)) = %+v", cursor, got)
+		}
+	}
+}
+
+func TestCursorDecodeInvalid(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	valid := codec.Encode(repository.Cursor{CreatedAt: time.Now(), ID: "c1"})
+	payload, signature, _ := strings.Cut(valid, ".")
+	sign := func(raw string) string {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(raw))
+		return encoded + "." + base64.RawURLEncoding.EncodeToString(codec.sign(encoded))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Empty", ""},
+		{"NoSignature", payload},
+		{"OtherSecret", NewCursorCodec([]byte("other")).Encode(repository.Cursor{CreatedAt: time.Now(), ID: "c1"})},
+		{"TamperedPayload", base64.RawURLEncoding.EncodeToString([]byte(`{"t":0,"i":"c2"}`)) + "." + signature},
+		{"SignatureNotBase64", payload + ".***"},
+		{"PayloadNotBase64", "***." + base64.RawURLEncoding.EncodeToString(codec.sign("***"))},
+		{"PayloadNotJSON", sign("cursor")},
+		{"MissingID", sign(`{"t":1}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Decode(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode(%q) error = %v, want ErrInvalidCursor", tt.token, err)
+			}
+		})
+	}
+}
@@ -7,6 +7,24 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Códigos de error que se envían en ErrorResponse.Code
+const (
+	CodeNotFound    = "NOT_FOUND"
+	CodeConflict    = "CONFLICT"
+	CodeForbidden   = "FORBIDDEN"
+	CodeUnavailable = "UNAVAILABLE"
+	CodeInternal    = "INTERNAL"
+
+	CodePaymentNotApproved = "PAYMENT_NOT_APPROVED"
+	CodePaymentProvider    = "PAYMENT_PROVIDER_ERROR"
+
+	CodeEmailExists        = "EMAIL_EXISTS"
+	CodeWeakPassword       = "WEAK_PASSWORD"
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeEmailNotVerified   = "EMAIL_NOT_VERIFIED"
+	CodeAuthProvider       = "AUTH_PROVIDER_ERROR"
+)
+
 // ErrorResponse es la estructura para respuestas de error
 type ErrorResponse struct {
 	Status  string `json:"status"`
@@ -23,3 +41,12 @@ type PaginatedResponse struct {
 	PageSize   int         `json:"pageSize"`
 	TotalPages int         `json:"totalPages"`
 }
+
+// CursorPaginatedResponse es la estructura para respuestas paginadas por cursor
+type CursorPaginatedResponse struct {
+	Status     string      `json:"status"`
+	Data       interface{} `json:"data"`
+	PageSize   int         `json:"pageSize"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+	HasMore    bool        `json:"hasMore"`
+}
@@ -1,9 +1,15 @@
 package router
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/guiver/config"
+	"github.com/guiver/internal/authz"
 	"github.com/guiver/internal/delivery/http/handlers"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/identity"
 	"github.com/guiver/internal/middleware"
 )
 
@@ -14,6 +20,14 @@ type Router struct {
 	guiverHandler *handlers.GuiverHandler
 	causeHandler  *handlers.CauseHandler
 	productHandler *handlers.ProductHandler
+	pledgeHandler  *handlers.PledgeHandler
+	orderHandler   *handlers.OrderHandler
+	ledgerHandler  *handlers.LedgerHandler
+	paymentHandler *handlers.PaymentHandler
+	authHandler    *handlers.AuthHandler
+	adminHandler   *handlers.AdminHandler
+	verifier       identity.TokenVerifier
+	policy         *authz.Policy
 }
 
 // NewRouter crea una nueva instancia del router
@@ -22,6 +36,14 @@ func NewRouter(
 	guiverHandler *handlers.GuiverHandler,
 	causeHandler *handlers.CauseHandler,
 	productHandler *handlers.ProductHandler,
+	pledgeHandler *handlers.PledgeHandler,
+	orderHandler *handlers.OrderHandler,
+	ledgerHandler *handlers.LedgerHandler,
+	paymentHandler *handlers.PaymentHandler,
+	authHandler *handlers.AuthHandler,
+	adminHandler *handlers.AdminHandler,
+	verifier identity.TokenVerifier,
+	policy *authz.Policy,
 ) *Router {
 	gin.SetMode(cfg.Server.Mode)
 	engine := gin.New()
@@ -32,6 +54,14 @@ func NewRouter(
 		guiverHandler: guiverHandler,
 		causeHandler:  causeHandler,
 		productHandler: productHandler,
+		pledgeHandler:  pledgeHandler,
+		orderHandler:   orderHandler,
+		ledgerHandler:  ledgerHandler,
+		paymentHandler: paymentHandler,
+		authHandler:    authHandler,
+		adminHandler:   adminHandler,
+		verifier:       verifier,
+		policy:         policy,
 	}
 }
 
@@ -56,17 +86,16 @@ func (r *Router) Setup() {
 				})
 			})
 
-			// Auth routes (se implementarán más tarde)
-			auth := public.Group("/auth")
-			{
-				auth.POST("/login", handlers.Login)
-				auth.POST("/register", handlers.Register)
-			}
+			// Registro e inicio de sesión
+			r.authHandler.Register(public)
+
+			// Notificaciones de la pasarela de pagos
+			r.paymentHandler.Register(public)
 		}
 
 		// Rutas protegidas
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(r.verifier))
 		{
 			// Guiver routes
 			r.guiverHandler.Register(protected)
@@ -76,6 +105,24 @@ func (r *Router) Setup() {
 
 			// Product routes
 			r.productHandler.Register(protected)
+
+			// Pledge routes
+			r.pledgeHandler.Register(protected)
+
+			// Order and donation routes
+			r.orderHandler.Register(protected)
+
+			// Ledger routes
+			r.ledgerHandler.Register(protected)
+
+			// Rutas de administración, solo para administradores
+			admin := protected.Group("/admin", middleware.RequireRole(r.policy, models.RoleAdmin), middleware.RequireVerifiedEmail())
+			{
+				r.adminHandler.Register(admin)
+				r.guiverHandler.RegisterAdmin(admin)
+				r.causeHandler.RegisterAdmin(admin)
+				r.productHandler.RegisterAdmin(admin)
+			}
 		}
 	}
 }
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// ExpenseCategory clasifica los gastos que se informan en las actualizaciones
+type ExpenseCategory string
+
+const (
+	ExpenseCategoryFood           ExpenseCategory = "food"
+	ExpenseCategoryHealth         ExpenseCategory = "health"
+	ExpenseCategorySupplies       ExpenseCategory = "supplies"
+	ExpenseCategoryTransport      ExpenseCategory = "transport"
+	ExpenseCategoryServices       ExpenseCategory = "services"
+	ExpenseCategoryInfrastructure ExpenseCategory = "infrastructure"
+	ExpenseCategoryOther          ExpenseCategory = "other"
+)
+
+// Valid indica si la categoría es una de las conocidas
+func (c ExpenseCategory) Valid() bool {
+	switch c {
+	case ExpenseCategoryFood, ExpenseCategoryHealth, ExpenseCategorySupplies, ExpenseCategoryTransport,
+		ExpenseCategoryServices, ExpenseCategoryInfrastructure, ExpenseCategoryOther:
+		return true
+	}
+	return false
+}
+
+// Expense es un gasto que el organizador informa en una actualización para
+// rendir cuentas de lo recaudado
+type Expense struct {
+	Concept    string          `json:"concept" firestore:"concept"`
+	Category   ExpenseCategory `json:"category" firestore:"category"`
+	Amount     Money           `json:"amount" firestore:"amount"`
+	ReceiptURL string          `json:"receiptUrl,omitempty" firestore:"receiptUrl"`
+	Date       time.Time       `json:"date" firestore:"date"`
+}
+
+// ExpenseTotal es lo gastado en una categoría y moneda, sumando los gastos de
+// todas las actualizaciones de una causa
+type ExpenseTotal struct {
+	Currency string          `json:"currency"`
+	Category ExpenseCategory `json:"category"`
+	Amount   int64           `json:"amount"`
+	Count    int             `json:"count"`
+}
+
+// ExpenseTotals acumula gastos por moneda y categoría
+type ExpenseTotals map[string]map[ExpenseCategory]*ExpenseTotal
+
+// Add suma los gastos a los totales
+func (t ExpenseTotals) Add(expenses ...Expense) {
+	for _, expense := range expenses {
+		currency := expense.Amount.Currency
+		if t[currency] == nil {
+			t[currency] = make(map[ExpenseCategory]*ExpenseTotal)
+		}
+		total := t[currency][expense.Category]
+		if total == nil {
+			total = &ExpenseTotal{Currency: currency, Category: expense.Category}
+			t[currency][expense.Category] = total
+		}
+		total.Amount += expense.Amount.Amount
+		total.Count++
+	}
+}
+
+// List devuelve los totales ordenados por moneda y categoría
+func (t ExpenseTotals) List() []ExpenseTotal {
+	totals := []ExpenseTotal{}
+	for _, categories := range t {
+		for _, total := range categories {
+			totals = append(totals, *total)
+		}
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Currency != totals[j].Currency {
+			return totals[i].Currency < totals[j].Currency
+		}
+		return totals[i].Category < totals[j].Category
+	})
+	return totals
+}
+
+// ExpenseReconciliation compara, en una moneda, lo recaudado por la causa con
+// los gastos informados en sus actualizaciones. Unreported es lo recaudado que
+// todavía no se rindió; si es negativo, Overspent indica que se informaron más
+// gastos que lo recaudado.
+type ExpenseReconciliation struct {
+	Currency   string `json:"currency"`
+	Collected  int64  `json:"collected"`
+	Reported   int64  `json:"reported"`
+	Unreported int64  `json:"unreported"`
+	Overspent  bool   `json:"overspent"`
+}
+
+// ReconcileExpenses concilia los gastos informados con lo recaudado según el
+// estado financiero del libro, por moneda y ordenado por moneda
+func ReconcileExpenses(totals []ExpenseTotal, statements []FinancialStatement) []ExpenseReconciliation {
+	byCurrency := make(map[string]*ExpenseReconciliation)
+	get := func(currency string) *ExpenseReconciliation {
+		if byCurrency[currency] == nil {
+			byCurrency[currency] = &ExpenseReconciliation{Currency: currency}
+		}
+		return byCurrency[currency]
+	}
+	for _, statement := range statements {
+		get(statement.Currency).Collected += statement.Raised
+	}
+	for _, total := range totals {
+		get(total.Currency).Reported += total.Amount
+	}
+
+	reconciliations := make([]ExpenseReconciliation, 0, len(byCurrency))
+	for _, r := range byCurrency {
+		r.Unreported = r.Collected - r.Reported
+		r.Overspent = r.Unreported < 0
+		reconciliations = append(reconciliations, *r)
+	}
+	sort.Slice(reconciliations, func(i, j int) bool {
+		return reconciliations[i].Currency < reconciliations[j].Currency
+	})
+	return reconciliations
+}
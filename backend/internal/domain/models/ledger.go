@@ -0,0 +1,212 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// LedgerEntryType representa el tipo de un movimiento del libro de una causa
+type LedgerEntryType string
+
+const (
+	LedgerEntryDonation        LedgerEntryType = "donation"         // aporte cobrado
+	LedgerEntryProductDonation LedgerEntryType = "product_donation" // parte donada de un pedido pagado
+	LedgerEntryRefund          LedgerEntryType = "refund"           // aporte reembolsado
+	LedgerEntryProductRefund   LedgerEntryType = "product_refund"   // parte donada de un pedido reembolsado
+	LedgerEntryDisbursement    LedgerEntryType = "disbursement"     // fondos entregados al organizador
+	LedgerEntryExpense         LedgerEntryType = "expense"          // gasto rendido por el organizador
+)
+
+// LedgerAccount es una cuenta del libro de una causa. Las de ingresos tienen
+// saldo acreedor (negativo); las demás, deudor (positivo).
+type LedgerAccount string
+
+const (
+	// AccountHeld son los fondos recaudados que aún no se entregaron
+	AccountHeld LedgerAccount = "held"
+	// AccountDisbursed son los fondos entregados al organizador que aún no
+	// rindió con gastos
+	AccountDisbursed        LedgerAccount = "disbursed"
+	AccountDonations        LedgerAccount = "income:donations"
+	AccountProductDonations LedgerAccount = "income:products"
+	// AccountRefunds resta de los ingresos lo reembolsado
+	AccountRefunds  LedgerAccount = "refunds"
+	AccountExpenses LedgerAccount = "expenses"
+)
+
+// ledgerAccounts indica, por tipo de movimiento, la cuenta que se debita y la
+// que se acredita
+var ledgerAccounts = map[LedgerEntryType][2]LedgerAccount{
+	LedgerEntryDonation:        {AccountHeld, AccountDonations},
+	LedgerEntryProductDonation: {AccountHeld, AccountProductDonations},
+	LedgerEntryRefund:          {AccountRefunds, AccountHeld},
+	LedgerEntryProductRefund:   {AccountRefunds, AccountHeld},
+	LedgerEntryDisbursement:    {AccountDisbursed, AccountHeld},
+	LedgerEntryExpense:         {AccountExpenses, AccountDisbursed},
+}
+
+// fundingAccounts indica, para los movimientos que usan fondos de la causa, la
+// cuenta de la que salen: las entregas salen de lo retenido y los gastos de lo
+// entregado. Esas cuentas no pueden quedar en negativo.
+var fundingAccounts = map[LedgerEntryType]LedgerAccount{
+	LedgerEntryDisbursement: AccountHeld,
+	LedgerEntryExpense:      AccountDisbursed,
+}
+
+// productDonationSigns indica, para los movimientos de las donaciones de los
+// pedidos, si suman o restan a lo donado
+var productDonationSigns = map[LedgerEntryType]int64{
+	LedgerEntryProductDonation: 1,
+	LedgerEntryProductRefund:   -1,
+}
+
+// ProductDonationTypes son los tipos de los movimientos de las donaciones de
+// los pedidos
+var ProductDonationTypes = []LedgerEntryType{LedgerEntryProductDonation, LedgerEntryProductRefund}
+
+// ErrUnbalancedEntry indica que las imputaciones de un movimiento no suman cero
+var ErrUnbalancedEntry = errors.New("ledger entry postings do not balance")
+
+// Posting es la imputación de un movimiento a una cuenta: Amount positivo
+// debita y negativo acredita
+type Posting struct {
+	Account LedgerAccount `json:"account" firestore:"account"`
+	Amount  int64         `json:"amount" firestore:"amount"`
+}
+
+// LedgerEntry es un movimiento del libro de partida doble de una causa. Los
+// movimientos no se modifican ni se eliminan: las correcciones se registran
+// con otro movimiento. Amount es el importe, siempre positivo, y las
+// imputaciones están en su moneda; Reference identifica la operación que lo originó
+// ("pledge:aporte", "order:pedido") cuando es automático. GuiverID es el
+// emprendedor que dona en los movimientos de un pedido.
+type LedgerEntry struct {
+	ID          string          `json:"id" firestore:"id"`
+	CauseID     string          `json:"causeId" firestore:"causeId"`
+	Type        LedgerEntryType `json:"type" firestore:"type"`
+	Amount      Money           `json:"amount" firestore:"amount"`
+	Description string          `json:"description,omitempty" firestore:"description"`
+	Reference   string          `json:"reference,omitempty" firestore:"reference"`
+	CreatedBy   string          `json:"createdBy,omitempty" firestore:"createdBy"`
+	GuiverID    string          `json:"guiverId,omitempty" firestore:"guiverId"`
+	Postings    []Posting       `json:"postings" firestore:"postings"`
+	CreatedAt   time.Time       `json:"createdAt" firestore:"createdAt"`
+}
+
+// NewLedgerEntry crea un movimiento del tipo indicado con sus imputaciones
+func NewLedgerEntry(causeID string, entryType LedgerEntryType, amount Money) *LedgerEntry {
+	entry := &LedgerEntry{
+		CauseID: causeID,
+		Type:    entryType,
+		Amount:  amount,
+	}
+	if accounts, ok := ledgerAccounts[entryType]; ok {
+		entry.Postings = []Posting{
+			{Account: accounts[0], Amount: amount.Amount},
+			{Account: accounts[1], Amount: -amount.Amount},
+		}
+	}
+	return entry
+}
+
+// FundingAccount devuelve la cuenta de la que salen los fondos del movimiento,
+// si necesita saldo suficiente en ella
+func (e *LedgerEntry) FundingAccount() (LedgerAccount, bool) {
+	account, ok := fundingAccounts[e.Type]
+	return account, ok
+}
+
+// DonatedAmount devuelve lo que el movimiento suma a lo donado por los
+// pedidos: Amount en las donaciones, -Amount en sus reembolsos y 0 en el resto
+func (e *LedgerEntry) DonatedAmount() int64 {
+	return productDonationSigns[e.Type] * e.Amount.Amount
+}
+
+// Validate verifica que el movimiento tenga un tipo conocido, un importe
+// positivo en una moneda soportada y al menos dos imputaciones que sumen cero
+func (e *LedgerEntry) Validate() error {
+	if _, ok := ledgerAccounts[e.Type]; !ok {
+		return errors.New("unknown ledger entry type")
+	}
+	if err := e.Amount.Validate(); err != nil {
+		return err
+	}
+	if e.Amount.IsZero() {
+		return errors.New("ledger entry amount must be positive")
+	}
+	if len(e.Postings) < 2 {
+		return ErrUnbalancedEntry
+	}
+	var sum int64
+	for _, posting := range e.Postings {
+		sum += posting.Amount
+	}
+	if sum != 0 {
+		return ErrUnbalancedEntry
+	}
+	return nil
+}
+
+// LedgerBalances son los saldos de las cuentas de una causa por moneda
+type LedgerBalances map[string]map[LedgerAccount]int64
+
+// Add suma las imputaciones del movimiento a los saldos
+func (b LedgerBalances) Add(entry *LedgerEntry) {
+	accounts, ok := b[entry.Amount.Currency]
+	if !ok {
+		accounts = make(map[LedgerAccount]int64)
+		b[entry.Amount.Currency] = accounts
+	}
+	for _, posting := range entry.Postings {
+		accounts[posting.Account] += posting.Amount
+	}
+}
+
+// Covers indica si el saldo de la cuenta de la que salen los fondos del
+// movimiento alcanza para registrarlo. Los movimientos que no usan fondos
+// siempre caben.
+func (b LedgerBalances) Covers(entry *LedgerEntry) bool {
+	account, ok := entry.FundingAccount()
+	return !ok || b[entry.Amount.Currency][account] >= entry.Amount.Amount
+}
+
+// FinancialStatement resume el estado financiero de una causa en una moneda,
+// con todos los importes en positivo
+type FinancialStatement struct {
+	Currency         string `json:"currency"`
+	Donations        int64  `json:"donations"`
+	ProductDonations int64  `json:"productDonations"`
+	Refunds          int64  `json:"refunds"`
+	// Raised es lo recaudado neto de reembolsos
+	Raised int64 `json:"raised"`
+	// Held es lo recaudado que aún no se entregó al organizador
+	Held int64 `json:"held"`
+	// Disbursed es todo lo entregado al organizador, y Unspent la parte que
+	// todavía no rindió con gastos
+	Disbursed int64 `json:"disbursed"`
+	Expenses  int64 `json:"expenses"`
+	Unspent   int64 `json:"unspent"`
+}
+
+// Statements arma el estado financiero de cada moneda a partir de los saldos,
+// ordenados por moneda
+func (b LedgerBalances) Statements() []FinancialStatement {
+	statements := make([]FinancialStatement, 0, len(b))
+	for currency, accounts := range b {
+		s := FinancialStatement{
+			Currency:         currency,
+			Donations:        -accounts[AccountDonations],
+			ProductDonations: -accounts[AccountProductDonations],
+			Refunds:          accounts[AccountRefunds],
+			Held:             accounts[AccountHeld],
+			Expenses:         accounts[AccountExpenses],
+			Unspent:          accounts[AccountDisbursed],
+		}
+		s.Raised = s.Donations + s.ProductDonations - s.Refunds
+		s.Disbursed = s.Unspent + s.Expenses
+		statements = append(statements, s)
+	}
+	sort.Slice(statements, func(i, j int) bool { return statements[i].Currency < statements[j].Currency })
+	return statements
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewLedgerEntryPostings(t *testing.T) {
+	tests := []struct {
+		entryType     LedgerEntryType
+		debit, credit LedgerAccount
+		donated       int64
+		funding       LedgerAccount
+		needsFunding  bool
+	}{
+		{LedgerEntryDonation, AccountHeld, AccountDonations, 0, "", false},
+		{LedgerEntryProductDonation, AccountHeld, AccountProductDonations, 500, "", false},
+		{LedgerEntryRefund, AccountRefunds, AccountHeld, 0, "", false},
+		{LedgerEntryProductRefund, AccountRefunds, AccountHeld, -500, "", false},
+		{LedgerEntryDisbursement, AccountDisbursed, AccountHeld, 0, AccountHeld, true},
+		{LedgerEntryExpense, AccountExpenses, AccountDisbursed, 0, AccountDisbursed, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.entryType), func(t *testing.T) {
+			entry := NewLedgerEntry("c1", tt.entryType, NewMoney(500, "ARS"))
+			want := []Posting{{Account: tt.debit, Amount: 500}, {Account: tt.credit, Amount: -500}}
+			if !reflect.DeepEqual(entry.Postings, want) {
+				t.Errorf("Postings = %+v, want %+v", entry.Postings, want)
+			}
+			if err := entry.Validate(); err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if got := entry.DonatedAmount(); got != tt.donated {
+				t.Errorf("DonatedAmount = %d, want %d", got, tt.donated)
+			}
+			account, ok := entry.FundingAccount()
+			if account != tt.funding || ok != tt.needsFunding {
+				t.Errorf("FundingAccount = %q, %v, want %q, %v", account, ok, tt.funding, tt.needsFunding)
+			}
+		})
+	}
+}
+
+func TestLedgerEntryValidate(t *testing.T) {
+	unbalanced := NewLedgerEntry("c1", LedgerEntryDonation, NewMoney(500, "ARS"))
+	unbalanced.Postings[1].Amount = -400
+	single := NewLedgerEntry("c1", LedgerEntryDonation, NewMoney(500, "ARS"))
+	single.Postings = single.Postings[:1]
+
+	tests := []struct {
+		name    string
+		entry   *LedgerEntry
+		wantErr error
+	}{
+		{"UnknownType", NewLedgerEntry("c1", "gift", NewMoney(500, "ARS")), nil},
+		{"InvalidCurrency", NewLedgerEntry("c1", LedgerEntryDonation, NewMoney(500, "XXX")), ErrInvalidCurrency},
+		{"Negative", NewLedgerEntry("c1", LedgerEntryDonation, NewMoney(-500, "ARS")), ErrNegativeAmount},
+		{"Zero", NewLedgerEntry("c1", LedgerEntryDonation, NewMoney(0, "ARS")), nil},
+		{"Unbalanced", unbalanced, ErrUnbalancedEntry},
+		{"SinglePosting", single, ErrUnbalancedEntry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.entry.Validate()
+			if err == nil {
+				t.Fatal("Validate succeeded, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLedgerBalances(t *testing.T) {
+	balances := LedgerBalances{}
+	for _, entry := range []*LedgerEntry{
+		NewLedgerEntry("c1", LedgerEntryDonation, NewMoney(10000, "ARS")),
+		NewLedgerEntry("c1", LedgerEntryProductDonation, NewMoney(2000, "ARS")),
+		NewLedgerEntry("c1", LedgerEntryRefund, NewMoney(1000, "ARS")),
+		NewLedgerEntry("c1", LedgerEntryDisbursement, NewMoney(6000, "ARS")),
+		NewLedgerEntry("c1", LedgerEntryExpense, NewMoney(2500, "ARS")),
+		NewLedgerEntry("c1", LedgerEntryDonation, NewMoney(50, "USD")),
+	} {
+		balances.Add(entry)
+	}
+
+	coverTests := []struct {
+		entry *LedgerEntry
+		want  bool
+	}{
+		{NewLedgerEntry("c1", LedgerEntryDisbursement, NewMoney(5000, "ARS")), true},
+		{NewLedgerEntry("c1", LedgerEntryDisbursement, NewMoney(5001, "ARS")), false},
+		{NewLedgerEntry("c1", LedgerEntryExpense, NewMoney(3500, "ARS")), true},
+		{NewLedgerEntry("c1", LedgerEntryExpense, NewMoney(3501, "ARS")), false},
+		{NewLedgerEntry("c1", LedgerEntryExpense, NewMoney(1, "USD")), false},
+		{NewLedgerEntry("c1", LedgerEntryDisbursement, NewMoney(1, "EUR")), false},
+		{NewLedgerEntry("c1", LedgerEntryRefund, NewMoney(1000000, "ARS")), true},
+	}
+	for _, tt := range coverTests {
+		if got := balances.Covers(tt.entry); got != tt.want {
+			t.Errorf("Covers(%s %+v) = %v, want %v", tt.entry.Type, tt.entry.Amount, got, tt.want)
+		}
+	}
+
+	want := []FinancialStatement{
+		{Currency: "ARS", Donations: 10000, ProductDonations: 2000, Refunds: 1000, Raised: 11000,
+			Held: 5000, Disbursed: 6000, Expenses: 2500, Unspent: 3500},
+		{Currency: "USD", Donations: 50, Raised: 50, Held: 50},
+	}
+	if got := balances.Statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrInvalidCurrency indica una moneda que no es un código ISO 4217 soportado
+	ErrInvalidCurrency = errors.New("invalid currency")
+	// ErrNegativeAmount indica un importe negativo
+	ErrNegativeAmount = errors.New("amount cannot be negative")
+)
+
+// currencyDigits son los decimales de las unidades menores de las monedas ISO
+// 4217 soportadas: las de los países latinoamericanos donde se usa Guiver y
+// las de referencia internacional
+var currencyDigits = map[string]int{
+	"ARS": 2, "BOB": 2, "BRL": 2, "CLP": 0, "COP": 2, "CRC": 2, "CUP": 2, "DOP": 2,
+	"GTQ": 2, "HNL": 2, "MXN": 2, "NIO": 2, "PAB": 2, "PEN": 2, "PYG": 0, "UYU": 2,
+	"VES": 2, "USD": 2, "EUR": 2,
+}
+
+// IsCurrency indica si code es una moneda ISO 4217 soportada ("ARS")
+func IsCurrency(code string) bool {
+	_, ok := currencyDigits[code]
+	return ok
+}
+
+// CurrencyDigits devuelve los decimales de la unidad menor de la moneda: 2 para
+// ARS (centavos), 0 para CLP
+func CurrencyDigits(code string) int {
+	return currencyDigits[code]
+}
+
+// Money es un importe en unidades menores de una moneda ISO 4217, por ejemplo
+// {150050, "ARS"} son 1500,50 pesos. Se usa un entero para no acumular errores
+// de redondeo.
+type Money struct {
+	Amount   int64  `json:"amount" firestore:"amount"`
+	Currency string `json:"currency" firestore:"currency"`
+}
+
+// NewMoney crea un importe en unidades menores de currency
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Validate verifica que la moneda esté soportada y el importe no sea negativo
+func (m Money) Validate() error {
+	if !IsCurrency(m.Currency) {
+		return ErrInvalidCurrency
+	}
+	if m.Amount < 0 {
+		return ErrNegativeAmount
+	}
+	return nil
+}
+
+// IsZero indica si el importe es cero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Major devuelve el importe en unidades mayores de su moneda (1500,50 pesos).
+// Es aproximado y solo sirve para convertir entre monedas.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(CurrencyDigits(m.Currency))
+}
+
+// MoneyFromMajor crea un importe a partir de unidades mayores de currency,
+// redondeando a la unidad menor más cercana
+func MoneyFromMajor(major float64, currency string) Money {
+	return Money{Amount: int64(math.Round(major * math.Pow10(CurrencyDigits(currency)))), Currency: currency}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoneyValidate(t *testing.T) {
+	tests := []struct {
+		money   Money
+		wantErr error
+	}{
+		{NewMoney(150050, "ARS"), nil},
+		{NewMoney(0, "CLP"), nil},
+		{NewMoney(-1, "ARS"), ErrNegativeAmount},
+		{NewMoney(100, ""), ErrInvalidCurrency},
+		{NewMoney(100, "ars"), ErrInvalidCurrency},
+		{NewMoney(100, "XXX"), ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		if err := tt.money.Validate(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Validate(%+v) = %v, want %v", tt.money, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMoneyMajor(t *testing.T) {
+	tests := []struct {
+		major float64
+		money Money
+	}{
+		{1500.50, NewMoney(150050, "ARS")},
+		{0.01, NewMoney(1, "USD")},
+		{1500, NewMoney(1500, "CLP")},
+		{0, NewMoney(0, "PYG")},
+	}
+	for _, tt := range tests {
+		if got := MoneyFromMajor(tt.major, tt.money.Currency); got != tt.money {
+			t.Errorf("MoneyFromMajor(%v, %s) = %+v, want %+v", tt.major, tt.money.Currency, got, tt.money)
+		}
+		if got := tt.money.Major(); got != tt.major {
+			t.Errorf("%+v.Major() = %v, want %v", tt.money, got, tt.major)
+		}
+	}
+}
+
+func TestMoneyFromMajorRounds(t *testing.T) {
+	tests := []struct {
+		major    float64
+		currency string
+		want     int64
+	}{
+		{0.125, "USD", 13},
+		{0.124, "USD", 12},
+		{19.999, "ARS", 2000},
+		{1.1, "ARS", 110},
+		{1500.5, "CLP", 1501},
+		{1500.4, "CLP", 1500},
+		{-0.125, "USD", -13},
+	}
+	for _, tt := range tests {
+		if got := MoneyFromMajor(tt.major, tt.currency); got != NewMoney(tt.want, tt.currency) {
+			t.Errorf("MoneyFromMajor(%v, %s) = %+v, want %d", tt.major, tt.currency, got, tt.want)
+		}
+	}
+}
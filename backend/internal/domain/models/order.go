@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// OrderStatus representa el estado de un pedido
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+// CanBecome indica si un pedido puede pasar del estado s al estado to: los
+// pendientes se pagan, los pagados se envían y los enviados se entregan. Se
+// pueden cancelar mientras no se hayan enviado; después solo se reembolsan.
+func (s OrderStatus) CanBecome(to OrderStatus) bool {
+	switch s {
+	case OrderStatusPending:
+		return to == OrderStatusPaid || to == OrderStatusCancelled
+	case OrderStatusPaid:
+		return to == OrderStatusShipped || to == OrderStatusCancelled
+	case OrderStatusShipped:
+		return to == OrderStatusDelivered || to == OrderStatusRefunded
+	case OrderStatusDelivered:
+		return to == OrderStatusRefunded
+	}
+	return false
+}
+
+// Order representa la compra de un producto o, si VariantID no está vacío,
+// de una de sus variantes. SellerID es el Guiver emprendedor dueño del
+// producto. Todos los importes están en la moneda de UnitPrice; UnitPrice y
+// DonationPercentage se copian del producto al comprarlo.
+type Order struct {
+	ID                 string      `json:"id" firestore:"id"`
+	ProductID          string      `json:"productId" firestore:"productId"`
+	VariantID          string      `json:"variantId,omitempty" firestore:"variantId,omitempty"`
+	CauseID            string      `json:"causeId" firestore:"causeId"`
+	SellerID           string      `json:"sellerId" firestore:"sellerId"`
+	BuyerID            string      `json:"buyerId" firestore:"buyerId"`
+	Quantity           int         `json:"quantity" firestore:"quantity"`
+	UnitPrice          Money       `json:"unitPrice" firestore:"unitPrice"`
+	Total              Money       `json:"total" firestore:"total"`
+	DonationPercentage int         `json:"donationPercentage" firestore:"donationPercentage"`
+	DonationAmount     Money       `json:"donationAmount" firestore:"donationAmount"`
+	Status             OrderStatus `json:"status" firestore:"status"`
+	PaymentID          string      `json:"paymentId,omitempty" firestore:"paymentId"`
+	CheckoutURL        string      `json:"checkoutUrl,omitempty" firestore:"-"`
+	CreatedAt          time.Time   `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt          time.Time   `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// CalculateTotals calcula Total y DonationAmount a partir del precio, la
+// cantidad y el porcentaje de donación. La donación se redondea hacia abajo a
+// la unidad menor.
+func (o *Order) CalculateTotals() {
+	total := o.UnitPrice.Amount * int64(o.Quantity)
+	o.Total = NewMoney(total, o.UnitPrice.Currency)
+	o.DonationAmount = NewMoney(total*int64(o.DonationPercentage)/100, o.UnitPrice.Currency)
+}
@@ -6,9 +6,9 @@ import "time"
 type CauseType string
 
 const (
-	CauseTypeSocial       CauseType = "social"
-	CauseTypeAnimal       CauseType = "animal"
-	CauseTypeEnvironment  CauseType = "environment"
+	CauseTypeSocial      CauseType = "social"
+	CauseTypeAnimal      CauseType = "animal"
+	CauseTypeEnvironment CauseType = "environment"
 )
 
 // CauseStatus representa el estado de una causa
@@ -21,55 +21,173 @@ const (
 	CauseStatusCancelled CauseStatus = "cancelled"
 )
 
-// Cause representa una causa social, animal o ambiental
+// Cause representa una causa social, animal o ambiental. Además del dueño,
+// los Guivers en Collaborators pueden publicar actualizaciones. Los listados
+// devuelven un resumen sin Updates; UpdateCount indica cuántas tiene.
+//
+// La moneda de GoalAmount es la de la causa y la de todos sus aportes, aunque
+// la meta sea cero. Una Causa con moneda recauda aportes hasta Deadline, si la
+// tiene; RaisedAmount y BackerCount solo cambian al cobrarse o reembolsarse
+// aportes.
 type Cause struct {
-	ID          string      `json:"id" firestore:"id"`
-	GuiverID    string      `json:"guiverId" firestore:"guiverId"`
-	Title       string      `json:"title" firestore:"title"`
-	Description string      `json:"description" firestore:"description"`
-	Type        CauseType   `json:"type" firestore:"type"`
-	ImageURLs   []string    `json:"imageUrls" firestore:"imageUrls"`
-	Status      CauseStatus `json:"status" firestore:"status"`
-	Location    string      `json:"location" firestore:"location"`
-	ContactInfo ContactInfo `json:"contactInfo" firestore:"contactInfo"`
-	Updates     []Update    `json:"updates" firestore:"updates"`
-	Likes       int         `json:"likes" firestore:"likes"`
-	CreatedAt   time.Time   `json:"createdAt" firestore:"createdAt"`
-	UpdatedAt   time.Time   `json:"updatedAt" firestore:"updatedAt"`
+	ID             string      `json:"id" firestore:"id"`
+	GuiverID       string      `json:"guiverId" firestore:"guiverId"`
+	Title          string      `json:"title" firestore:"title"`
+	Description    string      `json:"description" firestore:"description"`
+	Type           CauseType   `json:"type" firestore:"type"`
+	ImageURLs      []string    `json:"imageUrls" firestore:"imageUrls"`
+	Status         CauseStatus `json:"status" firestore:"status"`
+	Location       string      `json:"location" firestore:"location"`
+	ContactInfo    ContactInfo `json:"contactInfo" firestore:"contactInfo"`
+	Collaborators  []string    `json:"collaborators" firestore:"collaborators"`
+	GoalAmount     Money       `json:"goalAmount" firestore:"goalAmount"`
+	Deadline       *time.Time  `json:"deadline,omitempty" firestore:"deadline,omitempty"`
+	RaisedAmount   Money       `json:"raisedAmount" firestore:"raisedAmount"`
+	BackerCount    int         `json:"backerCount" firestore:"backerCount"`
+	Updates        []Update    `json:"updates,omitempty" firestore:"-"`
+	UpdateCount    int         `json:"updateCount" firestore:"updateCount"`
+	PinnedUpdateID string      `json:"pinnedUpdateId,omitempty" firestore:"pinnedUpdateId,omitempty"`
+	Likes          int         `json:"likes" firestore:"likes"`
+	LikedByMe      bool        `json:"likedByMe" firestore:"-"`
+	CommentCount   int         `json:"commentCount" firestore:"commentCount"`
+	CreatedAt      time.Time   `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt      time.Time   `json:"updatedAt" firestore:"updatedAt"`
 }
 
-// Product representa un producto que apoya una causa
+// ProductStatus representa el estado de un producto
+type ProductStatus string
+
+const (
+	ProductStatusActive  ProductStatus = "active"
+	ProductStatusPaused  ProductStatus = "paused"
+	ProductStatusSoldOut ProductStatus = "sold_out"
+)
+
+// Valid indica si s es un estado de producto conocido
+func (s ProductStatus) Valid() bool {
+	switch s {
+	case ProductStatusActive, ProductStatusPaused, ProductStatusSoldOut:
+		return true
+	}
+	return false
+}
+
+// Product representa un producto que apoya una causa. Price está en
+// unidades menores de su moneda, que puede no ser la de la causa.
+//
+// Los productos sin Stock ni Variants no controlan existencias. Con Variants,
+// cada variante tiene su propio stock y Stock es la suma de todas.
 type Product struct {
-	ID          string    `json:"id" firestore:"id"`
-	GuiverID    string    `json:"guiverId" firestore:"guiverId"`
-	CauseID     string    `json:"causeId" firestore:"causeId"`
-	Title       string    `json:"title" firestore:"title"`
-	Description string    `json:"description" firestore:"description"`
-	ImageURLs   []string  `json:"imageUrls" firestore:"imageUrls"`
-	Price       float64   `json:"price" firestore:"price"`
-	DonationPercentage int `json:"donationPercentage" firestore:"donationPercentage"`
-	Status      string    `json:"status" firestore:"status"`
-	ContactInfo ContactInfo `json:"contactInfo" firestore:"contactInfo"`
-	CreatedAt   time.Time  `json:"createdAt" firestore:"createdAt"`
-	UpdatedAt   time.Time  `json:"updatedAt" firestore:"updatedAt"`
+	ID                 string           `json:"id" firestore:"id"`
+	GuiverID           string           `json:"guiverId" firestore:"guiverId"`
+	CauseID            string           `json:"causeId" firestore:"causeId"`
+	Title              string           `json:"title" firestore:"title"`
+	Description        string           `json:"description" firestore:"description"`
+	ImageURLs          []string         `json:"imageUrls" firestore:"imageUrls"`
+	Price              Money            `json:"price" firestore:"price"`
+	DonationPercentage int              `json:"donationPercentage" firestore:"donationPercentage"`
+	Status             ProductStatus    `json:"status" firestore:"status"`
+	Stock              *int             `json:"stock,omitempty" firestore:"stock,omitempty"`
+	Variants           []ProductVariant `json:"variants,omitempty" firestore:"variants,omitempty"`
+	ContactInfo        ContactInfo      `json:"contactInfo" firestore:"contactInfo"`
+	CreatedAt          time.Time        `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt          time.Time        `json:"updatedAt" firestore:"updatedAt"`
 }
 
-// Update representa una actualización de una causa
+// Update representa una actualización de una causa. Expenses son los gastos
+// que se rinden en ella, en el orden en que se informaron.
 type Update struct {
-	ID        string    `json:"id" firestore:"id"`
-	Content   string    `json:"content" firestore:"content"`
-	ImageURLs []string  `json:"imageUrls" firestore:"imageUrls"`
-	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+	ID        string     `json:"id" firestore:"id"`
+	GuiverID  string     `json:"guiverId" firestore:"guiverId"`
+	Content   string     `json:"content" firestore:"content"`
+	ImageURLs []string   `json:"imageUrls" firestore:"imageUrls"`
+	Expenses  []Expense  `json:"expenses,omitempty" firestore:"expenses,omitempty"`
+	CreatedAt time.Time  `json:"createdAt" firestore:"createdAt"`
+	EditedAt  *time.Time `json:"editedAt,omitempty" firestore:"editedAt,omitempty"`
+	Pinned    bool       `json:"pinned" firestore:"-"`
 }
 
-// Comment representa un comentario en una causa
-type Comment struct {
-	ID        string    `json:"id" firestore:"id"`
+// Like registra que a un Guiver le gusta una causa
+type Like struct {
 	GuiverID  string    `json:"guiverId" firestore:"guiverId"`
-	Content   string    `json:"content" firestore:"content"`
 	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
 }
 
+// PledgeStatus representa el estado del cobro de un aporte
+type PledgeStatus string
+
+const (
+	PledgeStatusPending  PledgeStatus = "pending"
+	PledgeStatusPaid     PledgeStatus = "paid"
+	PledgeStatusFailed   PledgeStatus = "failed"
+	PledgeStatusRefunded PledgeStatus = "refunded"
+)
+
+// CanBecome indica si un aporte puede pasar del estado s al estado to: los
+// pendientes se pagan o fallan y los pagados se reembolsan
+func (s PledgeStatus) CanBecome(to PledgeStatus) bool {
+	switch s {
+	case PledgeStatusPending:
+		return to == PledgeStatusPaid || to == PledgeStatusFailed
+	case PledgeStatusPaid:
+		return to == PledgeStatusRefunded
+	}
+	return false
+}
+
+// OpenPledgeStatuses son los estados de los aportes que todavía se cobran o
+// ya se cobraron sin reembolsarse
+var OpenPledgeStatuses = []PledgeStatus{PledgeStatusPending, PledgeStatusPaid}
+
+// Pledge representa el aporte de un Guiver a la recaudación de una causa.
+// Amount está en la moneda de la causa. Solo los aportes pagados cuentan en los
+// totales de la causa. Los aportes anónimos no muestran el Guiver a los demás.
+type Pledge struct {
+	ID          string       `json:"id" firestore:"id"`
+	CauseID     string       `json:"causeId" firestore:"causeId"`
+	GuiverID    string       `json:"guiverId,omitempty" firestore:"guiverId"`
+	Amount      Money        `json:"amount" firestore:"amount"`
+	Message     string       `json:"message,omitempty" firestore:"message"`
+	Anonymous   bool         `json:"anonymous" firestore:"anonymous"`
+	Status      PledgeStatus `json:"status" firestore:"status"`
+	PaymentID   string       `json:"paymentId,omitempty" firestore:"paymentId"`
+	CheckoutURL string       `json:"checkoutUrl,omitempty" firestore:"-"`
+	CreatedAt   time.Time    `json:"createdAt" firestore:"createdAt"`
+}
+
+// AcceptsPledges indica si la causa admite aportes en el momento indicado: debe
+// estar activa, tener moneda y no haber pasado su fecha límite
+func (c *Cause) AcceptsPledges(now time.Time) bool {
+	if c.Status != CauseStatusActive || c.GoalAmount.Currency == "" {
+		return false
+	}
+	return c.Deadline == nil || now.Before(*c.Deadline)
+}
+
+// Comment representa un comentario en una causa. Las respuestas indican el
+// comentario al que responden en ParentID y la raíz de su hilo en ThreadID;
+// ambos están vacíos en los comentarios principales.
+type Comment struct {
+	ID        string     `json:"id" firestore:"id"`
+	GuiverID  string     `json:"guiverId" firestore:"guiverId"`
+	Content   string     `json:"content" firestore:"content"`
+	ParentID  string     `json:"parentId,omitempty" firestore:"parentId"`
+	ThreadID  string     `json:"threadId,omitempty" firestore:"threadId,omitempty"`
+	Mentions  []string   `json:"mentions,omitempty" firestore:"mentions,omitempty"`
+	CreatedAt time.Time  `json:"createdAt" firestore:"createdAt"`
+	EditedAt  *time.Time `json:"editedAt,omitempty" firestore:"editedAt,omitempty"`
+	Author    *Author    `json:"author,omitempty" firestore:"-"`
+	Mentioned []*Author  `json:"mentioned,omitempty" firestore:"-"`
+	Replies   []*Comment `json:"replies,omitempty" firestore:"-"`
+}
+
+// Author resume los datos públicos de quien escribió un contenido
+type Author struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	PhotoURL    string `json:"photoURL"`
+}
+
 // ContactInfo representa la información de contacto
 type ContactInfo struct {
 	WhatsApp  string `json:"whatsApp,omitempty" firestore:"whatsApp,omitempty"`
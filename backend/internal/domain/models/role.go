@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrInvalidRole indica un rol desconocido
+var ErrInvalidRole = errors.New("invalid role")
+
+// Role es un rol de un Guiver. Todos los Guivers autenticados tienen
+// RoleGuiver; los demás se asignan.
+type Role string
+
+const (
+	RoleGuiver    Role = "guiver"
+	RoleOrganizer Role = "organizer" // organizador verificado
+	RoleModerator Role = "moderator" // modera el contenido de causas y productos
+	RoleAdmin     Role = "admin"     // administra cualquier causa, producto o Guiver
+)
+
+// Valid indica si el rol es uno de los definidos
+func (r Role) Valid() bool {
+	switch r {
+	case RoleGuiver, RoleOrganizer, RoleModerator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// NormalizeRoles valida los roles asignados a un Guiver y los devuelve
+// ordenados y sin repetidos ni RoleGuiver, que no hace falta asignar
+func NormalizeRoles(roles []Role) ([]Role, error) {
+	seen := make(map[Role]bool)
+	normalized := []Role{}
+	for _, role := range roles {
+		if !role.Valid() {
+			return nil, ErrInvalidRole
+		}
+		if role == RoleGuiver || seen[role] {
+			continue
+		}
+		seen[role] = true
+		normalized = append(normalized, role)
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] < normalized[j] })
+	return normalized, nil
+}
@@ -0,0 +1,95 @@
+package models
+
+import "errors"
+
+var (
+	// ErrOutOfStock indica que no hay existencias suficientes
+	ErrOutOfStock = errors.New("out of stock")
+	// ErrVariantRequired indica que el producto tiene variantes y no se eligió ninguna
+	ErrVariantRequired = errors.New("product variant is required")
+	// ErrUnknownVariant indica una variante que el producto no tiene
+	ErrUnknownVariant = errors.New("unknown product variant")
+)
+
+// ProductVariant es una variante de un producto, por ejemplo un talle y un
+// color, con sus propias existencias
+type ProductVariant struct {
+	ID    string `json:"id" firestore:"id"`
+	Size  string `json:"size,omitempty" firestore:"size,omitempty"`
+	Color string `json:"color,omitempty" firestore:"color,omitempty"`
+	Stock int    `json:"stock" firestore:"stock"`
+}
+
+// TracksStock indica si el producto controla existencias
+func (p *Product) TracksStock() bool {
+	return p.Stock != nil || len(p.Variants) > 0
+}
+
+// Variant devuelve la variante del producto con el ID indicado
+func (p *Product) Variant(id string) (*ProductVariant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// AdjustStock suma delta a las existencias de la variante indicada, o a las del
+// producto si no tiene variantes, y actualiza el estado con SyncStock. Un
+// delta negativo descuenta unidades vendidas; uno positivo las repone. Falla
+// con ErrOutOfStock si no alcanzan, sin modificar nada. Los productos que no
+// controlan existencias solo verifican la variante.
+func (p *Product) AdjustStock(variantID string, delta int) error {
+	if len(p.Variants) == 0 {
+		if variantID != "" {
+			return ErrUnknownVariant
+		}
+		if p.Stock == nil {
+			return nil
+		}
+		if *p.Stock+delta < 0 {
+			return ErrOutOfStock
+		}
+		stock := *p.Stock + delta
+		p.Stock = &stock
+		p.SyncStock()
+		return nil
+	}
+
+	if variantID == "" {
+		return ErrVariantRequired
+	}
+	variant, ok := p.Variant(variantID)
+	if !ok {
+		return ErrUnknownVariant
+	}
+	if variant.Stock+delta < 0 {
+		return ErrOutOfStock
+	}
+	variant.Stock += delta
+	p.SyncStock()
+	return nil
+}
+
+// SyncStock recalcula Stock a partir de las variantes y pasa el producto a
+// agotado cuando se queda sin existencias, o de nuevo a activo cuando se
+// reponen. Los productos pausados no cambian de estado.
+func (p *Product) SyncStock() {
+	if len(p.Variants) > 0 {
+		total := 0
+		for _, variant := range p.Variants {
+			total += variant.Stock
+		}
+		p.Stock = &total
+	}
+	if p.Stock == nil {
+		return
+	}
+	switch {
+	case *p.Stock == 0 && p.Status == ProductStatusActive:
+		p.Status = ProductStatusSoldOut
+	case *p.Stock > 0 && p.Status == ProductStatusSoldOut:
+		p.Status = ProductStatusActive
+	}
+}
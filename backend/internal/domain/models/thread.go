@@ -0,0 +1,48 @@
+package models
+
+// NestReplies arma los hilos de comentarios: agrega cada respuesta a Replies
+// de su comentario padre, a cualquier profundidad. Las respuestas deben venir
+// de la más antigua a la más nueva; las que no cuelgan de roots se ignoran.
+func NestReplies(roots []*Comment, replies []*Comment) {
+	children := make(map[string][]*Comment)
+	for _, reply := range replies {
+		children[reply.ParentID] = append(children[reply.ParentID], reply)
+	}
+
+	pending := append([]*Comment(nil), roots...)
+	for len(pending) > 0 {
+		comment := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		comment.Replies = children[comment.ID]
+		pending = append(pending, comment.Replies...)
+	}
+}
+
+// ThreadRoot devuelve el ID del comentario principal del hilo del comentario
+func ThreadRoot(comment *Comment) string {
+	if comment.ThreadID != "" {
+		return comment.ThreadID
+	}
+	return comment.ID
+}
+
+// Descendants devuelve los IDs de las respuestas que cuelgan, directa o
+// indirectamente, del comentario id entre los comentarios dados
+func Descendants(id string, comments []*Comment) []string {
+	children := make(map[string][]string)
+	for _, comment := range comments {
+		if comment.ParentID != "" {
+			children[comment.ParentID] = append(children[comment.ParentID], comment.ID)
+		}
+	}
+
+	var ids []string
+	pending := []string{id}
+	for len(pending) > 0 {
+		next := children[pending[len(pending)-1]]
+		pending = pending[:len(pending)-1]
+		ids = append(ids, next...)
+		pending = append(pending, next...)
+	}
+	return ids
+}
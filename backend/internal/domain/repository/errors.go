@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errores que devuelven las implementaciones de los repositorios. Pueden venir
+// envueltos con más contexto, por lo que deben compararse con errors.Is.
+var (
+	// ErrNotFound indica que la entidad solicitada no existe
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indica que la entidad ya existe o fue modificada concurrentemente
+	ErrConflict = errors.New("conflict")
+	// ErrPermission indica que el backend rechazó la operación por permisos
+	ErrPermission = errors.New("permission denied")
+	// ErrUnavailable indica un fallo transitorio del backend; la operación puede reintentarse
+	ErrUnavailable = errors.New("storage unavailable")
+	// ErrInsufficientFunds es el ErrConflict de un movimiento del libro cuya
+	// cuenta de origen no tiene saldo suficiente
+	ErrInsufficientFunds = fmt.Errorf("%w: insufficient funds", ErrConflict)
+)
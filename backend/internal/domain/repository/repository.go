@@ -2,40 +2,191 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/guiver/internal/domain/models"
 )
 
-// GuiverRepository define las operaciones para Guivers
+// GuiverRepository define las operaciones para Guivers.
+// Las implementaciones deben superar repositorytest.TestGuiverRepository.
 type GuiverRepository interface {
+	// Create guarda el Guiver con su ID, que debe ser el UID de su cuenta; si
+	// está vacío se genera uno. Falla con ErrConflict si ya existe un Guiver
+	// con ese ID.
 	Create(ctx context.Context, guiver *models.Guiver) error
 	GetByID(ctx context.Context, id string) (*models.Guiver, error)
 	Update(ctx context.Context, guiver *models.Guiver) error
 	Delete(ctx context.Context, id string) error
 }
 
-// CauseRepository define las operaciones para causas
+// CauseRepository define las operaciones para causas.
+// Las implementaciones deben superar repositorytest.TestCauseRepository.
 type CauseRepository interface {
 	Create(ctx context.Context, cause *models.Cause) error
+	// GetByID devuelve la Causa con todas sus actualizaciones, de la más antigua
+	// a la más nueva
 	GetByID(ctx context.Context, id string) (*models.Cause, error)
+	// GetSummary, GetByGuiverID y List devuelven resúmenes: Causas sin Updates
+	GetSummary(ctx context.Context, id string) (*models.Cause, error)
 	GetByGuiverID(ctx context.Context, guiverID string) ([]*models.Cause, error)
+	// Update no modifica Likes ni CommentCount, que solo cambian con Like,
+	// Unlike, AddComment y DeleteComment, ni Updates, UpdateCount y
+	// PinnedUpdateID, que se gestionan con sus propios métodos, ni
+	// RaisedAmount y BackerCount, que solo cambian con PledgeRepository. La
+	// moneda de GoalAmount solo cambia mientras RaisedAmount es cero y no hay
+	// aportes pendientes; si no, falla con ErrConflict.
 	Update(ctx context.Context, cause *models.Cause) error
+	// Delete elimina la Causa con todo lo que depende de ella, aportes incluidos.
+	// Falla con ErrConflict si tiene aportes en models.OpenPledgeStatuses.
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, filter CauseFilter) ([]*models.Cause, error)
+	Count(ctx context.Context, filter CauseFilter) (int64, error)
+	// AddUpdate y DeleteUpdate actualizan UpdateCount atómicamente
 	AddUpdate(ctx context.Context, causeID string, update *models.Update) error
+	GetUpdate(ctx context.Context, causeID, updateID string) (*models.Update, error)
+	// ListUpdates lista las actualizaciones de la más nueva a la más antigua
+	ListUpdates(ctx context.Context, causeID string, filter UpdateFilter) ([]*models.Update, error)
+	// EditUpdate cambia solo el contenido, las imágenes y los gastos de la
+	// actualización y marca EditedAt
+	EditUpdate(ctx context.Context, causeID string, update *models.Update) error
+	// DeleteUpdate también desfija la actualización si estaba fijada
+	DeleteUpdate(ctx context.Context, causeID, updateID string) error
+	// PinUpdate fija la actualización al principio del historial de la Causa,
+	// reemplazando a la anterior. Con updateID vacío desfija la actual.
+	PinUpdate(ctx context.Context, causeID, updateID string) error
+	// ExpenseTotals suma los gastos de todas las actualizaciones de la Causa por
+	// moneda y categoría, ordenados por moneda y categoría
+	ExpenseTotals(ctx context.Context, causeID string) ([]models.ExpenseTotal, error)
+	// AddComment y DeleteComment actualizan CommentCount atómicamente. Si el
+	// comentario tiene ParentID, AddComment verifica que el padre exista en la
+	// misma Causa (si no, ErrNotFound) y completa ThreadID.
 	AddComment(ctx context.Context, causeID string, comment *models.Comment) error
-	UpdateLikes(ctx context.Context, causeID string, increment bool) error
+	GetComment(ctx context.Context, causeID, commentID string) (*models.Comment, error)
+	// ListComments lista solo los comentarios principales, sin respuestas
+	ListComments(ctx context.Context, causeID string, filter CommentFilter) ([]*models.Comment, error)
+	// ListReplies lista todas las respuestas de los hilos indicados, de la más
+	// antigua a la más nueva
+	ListReplies(ctx context.Context, causeID string, threadIDs []string) ([]*models.Comment, error)
+	// UpdateComment cambia solo el contenido y las menciones del comentario y marca EditedAt
+	UpdateComment(ctx context.Context, causeID string, comment *models.Comment) error
+	// DeleteComment elimina el comentario junto con todas sus respuestas
+	DeleteComment(ctx context.Context, causeID, commentID string) error
+	// Like registra el like del Guiver y Unlike lo quita. Son idempotentes y
+	// actualizan el contador atómicamente; devuelven la cantidad de likes resultante.
+	Like(ctx context.Context, causeID, guiverID string) (int, error)
+	Unlike(ctx context.Context, causeID, guiverID string) (int, error)
+	// LikedBy indica cuáles de las Causas indicadas le gustan al Guiver
+	LikedBy(ctx context.Context, guiverID string, causeIDs []string) (map[string]bool, error)
 }
 
-// ProductRepository define las operaciones para productos
+// PledgeRepository define las operaciones para los aportes a las causas. Debe
+// compartir almacenamiento con el CauseRepository para mantener los totales de
+// las Causas. Las implementaciones deben superar repositorytest.TestPledgeRepository.
+type PledgeRepository interface {
+	// Create registra el aporte, con el ID indicado o uno nuevo si está vacío
+	// (ErrConflict si ya existe) y como pendiente si no tiene Status. Si ya está
+	// pagado lo cuenta en los totales como UpdateStatus. Falla con ErrNotFound
+	// si la Causa no existe.
+	Create(ctx context.Context, pledge *models.Pledge) error
+	GetByID(ctx context.Context, causeID, id string) (*models.Pledge, error)
+	// UpdateStatus cambia el estado del aporte y ajusta atómicamente los
+	// totales de la Causa: al pagarse suma Amount a RaisedAmount y, si es el
+	// primer aporte pagado del Guiver, uno a BackerCount; al reembolsarse los
+	// resta. Repetir el estado actual no cambia nada; las transiciones que no
+	// admite models.PledgeStatus.CanBecome fallan con ErrConflict. Devuelve el
+	// aporte resultante.
+	UpdateStatus(ctx context.Context, causeID, id string, status models.PledgeStatus) (*models.Pledge, error)
+	// ListByCause lista los aportes de una Causa, del más nuevo al más antiguo
+	ListByCause(ctx context.Context, causeID string, filter PledgeFilter) ([]*models.Pledge, error)
+}
+
+// OrderRepository define las operaciones para los pedidos de productos. Lo
+// que donan se registra en el libro de la Causa (LedgerRepository).
+// Las implementaciones deben superar repositorytest.TestOrderRepository.
+type OrderRepository interface {
+	// Create registra el pedido como pendiente, con el ID indicado o uno nuevo
+	// si está vacío (ErrConflict si ya existe), y calcula Total y DonationAmount
+	Create(ctx context.Context, order *models.Order) error
+	GetByID(ctx context.Context, id string) (*models.Order, error)
+	// UpdateStatus cambia el estado del pedido. Repetir el estado actual no
+	// cambia nada; las transiciones que no admite models.OrderStatus.CanBecome
+	// fallan con ErrConflict. Devuelve el pedido resultante.
+	UpdateStatus(ctx context.Context, id string, status models.OrderStatus) (*models.Order, error)
+	// List lista los pedidos del más nuevo al más antiguo
+	List(ctx context.Context, filter OrderFilter) ([]*models.Order, error)
+}
+
+// LedgerRepository define las operaciones del libro de partida doble de las
+// causas. Es de solo agregado: los movimientos no se modifican ni se eliminan,
+// tampoco al eliminarse la Causa.
+// Las implementaciones deben superar repositorytest.TestLedgerRepository.
+type LedgerRepository interface {
+	// Append registra el movimiento, con el ID indicado o uno nuevo si está
+	// vacío. Falla con ErrConflict si el ID ya existe, de modo que los
+	// movimientos automáticos se registran una sola vez; con
+	// ErrInsufficientFunds, que también es ErrConflict, si el saldo de la
+	// cuenta de la que salen sus fondos no alcanza
+	// (models.LedgerBalances.Covers), verificado en la misma transacción que
+	// lo registra; y con el error de models.LedgerEntry.Validate si el
+	// movimiento no es válido.
+	Append(ctx context.Context, entry *models.LedgerEntry) error
+	// ListByCause lista los movimientos de una Causa, del más nuevo al más antiguo
+	ListByCause(ctx context.Context, causeID string, filter LedgerFilter) ([]*models.LedgerEntry, error)
+	// Balances calcula los saldos de las cuentas de una Causa a partir de
+	// todos sus movimientos
+	Balances(ctx context.Context, causeID string) (models.LedgerBalances, error)
+	// ListDonations lista los movimientos de las donaciones de los pedidos
+	// (models.ProductDonationTypes), del más nuevo al más antiguo
+	ListDonations(ctx context.Context, filter DonationFilter) ([]*models.LedgerEntry, error)
+	// DonationTotals suma por moneda lo donado por los pedidos que cumplen el
+	// filtro, neto de reembolsos (models.LedgerEntry.DonatedAmount), ignorando
+	// Limit y After
+	DonationTotals(ctx context.Context, filter DonationFilter) (map[string]int64, error)
+}
+
+// RoleRepository guarda los roles asignados a los Guivers. RoleGuiver no se
+// guarda: lo tienen todos.
+// Las implementaciones deben superar repositorytest.TestRoleRepository.
+type RoleRepository interface {
+	// GetRoles devuelve los roles asignados a un Guiver, ordenados; ninguno
+	// si no tiene
+	GetRoles(ctx context.Context, guiverID string) ([]models.Role, error)
+	// SetRoles reemplaza los roles asignados a un Guiver como
+	// models.NormalizeRoles; sin roles se los quita todos. Falla con
+	// models.ErrInvalidRole si alguno no es válido.
+	SetRoles(ctx context.Context, guiverID string, roles []models.Role) error
+	// ListByRole lista los IDs de los Guivers que tienen el rol asignado, ordenados
+	ListByRole(ctx context.Context, role models.Role) ([]string, error)
+}
+
+// ProductRepository define las operaciones para productos.
+// Las implementaciones deben superar repositorytest.TestProductRepository.
 type ProductRepository interface {
 	Create(ctx context.Context, product *models.Product) error
 	GetByID(ctx context.Context, id string) (*models.Product, error)
 	GetByCauseID(ctx context.Context, causeID string) ([]*models.Product, error)
 	GetByGuiverID(ctx context.Context, guiverID string) ([]*models.Product, error)
-	Update(ctx context.Context, product *models.Product) error
+	// Update actualiza un Producto. Salvo con replaceStock, conserva las
+	// existencias y las variantes guardadas, para no pisar lo que descontaron
+	// los pedidos desde que se leyó. En ambos casos recalcula el estado con
+	// models.Product.SyncStock sobre las existencias resultantes.
+	Update(ctx context.Context, product *models.Product, replaceStock bool) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, filter ProductFilter) ([]*models.Product, error)
+	Count(ctx context.Context, filter ProductFilter) (int64, error)
+	// AdjustStock aplica atómicamente models.Product.AdjustStock: suma delta a
+	// las existencias de la variante, o del producto si no tiene variantes, y
+	// lo pasa a agotado o de nuevo a activo según corresponda. Falla con
+	// models.ErrOutOfStock si no alcanzan, sin modificar nada. Devuelve el
+	// producto resultante.
+	AdjustStock(ctx context.Context, id, variantID string, delta int) (*models.Product, error)
+}
+
+// Cursor identifica la posición de un elemento en un listado ordenado por
+// fecha de creación descendente y, a igual fecha, por ID descendente
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
 }
 
 // CauseFilter define los filtros para buscar causas
@@ -43,18 +194,84 @@ type CauseFilter struct {
 	Type     models.CauseType
 	Status   models.CauseStatus
 	Location string
-	Search   string
+	Search   string // palabras clave del título o la descripción; sin cursor ordena por relevancia
 	Limit    int
 	Offset   int
+	After    *Cursor // si se indica, se listan las causas posteriores al cursor y se ignora Offset
+}
+
+// CommentFilter define la paginación de los comentarios principales de una
+// causa, del más nuevo al más antiguo
+type CommentFilter struct {
+	Limit int
+	After *Cursor // si se indica, se listan los comentarios posteriores al cursor
+}
+
+// UpdateFilter define la paginación de las actualizaciones de una causa, de la
+// más nueva a la más antigua
+type UpdateFilter struct {
+	Limit int
+	After *Cursor // si se indica, se listan las actualizaciones posteriores al cursor
+}
+
+// PledgeFilter define la paginación de los aportes de una causa, del más nuevo
+// al más antiguo
+type PledgeFilter struct {
+	Limit int
+	After *Cursor // si se indica, se listan los aportes posteriores al cursor
+}
+
+// OrderFilter define los filtros de los pedidos, del más nuevo al más antiguo.
+// Los filtros vacíos no se aplican.
+type OrderFilter struct {
+	BuyerID  string
+	SellerID string
+	CauseID  string
+	Status   models.OrderStatus
+	Limit    int
+	After    *Cursor // si se indica, se listan los pedidos posteriores al cursor
+}
+
+// DonationFilter define los filtros de los movimientos de las donaciones de
+// los pedidos, del más nuevo al más antiguo. Los filtros vacíos no se aplican.
+type DonationFilter struct {
+	CauseID  string
+	GuiverID string // emprendedor que dona
+	Limit    int
+	After    *Cursor // si se indica, se listan los movimientos posteriores al cursor
+}
+
+// LedgerFilter define los filtros de los movimientos del libro de una causa,
+// del más nuevo al más antiguo
+type LedgerFilter struct {
+	Type  models.LedgerEntryType // si se indica, solo los movimientos de ese tipo
+	Limit int
+	After *Cursor // si se indica, se listan los movimientos posteriores al cursor
+}
+
+// PriceRange acota el precio de los productos en una moneda, en unidades
+// menores. Min o Max en 0 dejan ese extremo abierto.
+type PriceRange struct {
+	Currency string
+	Min      int64
+	Max      int64
+}
+
+// Contains indica si el importe está en el rango
+func (r PriceRange) Contains(price models.Money) bool {
+	return price.Currency == r.Currency && (r.Min <= 0 || price.Amount >= r.Min) && (r.Max <= 0 || price.Amount <= r.Max)
 }
 
 // ProductFilter define los filtros para buscar productos
 type ProductFilter struct {
 	CauseID  string
 	GuiverID string
-	Search   string
-	MinPrice float64
-	MaxPrice float64
-	Limit    int
-	Offset   int
+	Search   string // palabras clave del título o la descripción; sin cursor ordena por relevancia
+	// PriceRanges acota el precio: si se indican, solo los productos cuyo
+	// precio cae en el rango de su moneda. Los productos en monedas sin rango
+	// quedan fuera.
+	PriceRanges []PriceRange
+	Limit       int
+	Offset      int
+	After       *Cursor // si se indica, se listan los productos posteriores al cursor y se ignora Offset
 }
@@ -0,0 +1,603 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// TestCauseRepository verifica el contrato de repository.CauseRepository.
+// newRepo debe devolver un repositorio vacío en cada llamada.
+func TestCauseRepository(t *testing.T, newRepo func(t *testing.T) repository.CauseRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAssignsIDAndTimestamps", func(t *testing.T) {
+		repo := newRepo(t)
+		before := time.Now()
+		cause := newCause("g1", "Rescate de perros")
+		cause.Likes = 42
+		if err := repo.Create(ctx, cause); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if cause.ID == "" {
+			t.Fatal("Create did not assign an ID")
+		}
+		if cause.Likes != 0 {
+			t.Errorf("Likes = %d, want 0", cause.Likes)
+		}
+		assertRecent(t, "CreatedAt", cause.CreatedAt, before)
+
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Title != cause.Title || got.Description != cause.Description || got.Type != cause.Type ||
+			got.Status != cause.Status || got.Location != cause.Location || got.GuiverID != cause.GuiverID ||
+			got.ContactInfo != cause.ContactInfo || got.Likes != 0 {
+			t.Errorf("GetByID = %+v, want %+v", got, cause)
+		}
+		if len(got.ImageURLs) != 1 || got.ImageURLs[0] != cause.ImageURLs[0] {
+			t.Errorf("ImageURLs = %v, want %v", got.ImageURLs, cause.ImageURLs)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, cause.CreatedAt)
+	})
+
+	t.Run("CreateDuplicateID", func(t *testing.T) {
+		repo := newRepo(t)
+		first := newCause("g1", "Original")
+		first.ID = "cause-1"
+		createCause(t, repo, first)
+
+		second := newCause("g2", "Copia")
+		second.ID = "cause-1"
+		err := repo.Create(ctx, second)
+		assertErrorIs(t, "Create", err, repository.ErrConflict)
+	})
+
+	t.Run("GetByIDMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetByID(ctx, "missing")
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+	})
+
+	t.Run("GetByGuiverIDNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		first := createCause(t, repo, newCause("g1", "Primera"))
+		createCause(t, repo, newCause("g2", "Otra"))
+		second := createCause(t, repo, newCause("g1", "Segunda"))
+
+		causes, err := repo.GetByGuiverID(ctx, "g1")
+		if err != nil {
+			t.Fatalf("GetByGuiverID: %v", err)
+		}
+		assertIDs(t, "GetByGuiverID", causeIDs(causes), second.ID, first.ID)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		created := cause.CreatedAt
+
+		tick()
+		cause.Title = "Rescate de gatos"
+		cause.Status = models.CauseStatusCompleted
+		if err := repo.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if !cause.UpdatedAt.After(created) {
+			t.Errorf("UpdatedAt = %v, want after %v", cause.UpdatedAt, created)
+		}
+
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Title != "Rescate de gatos" || got.Status != models.CauseStatusCompleted {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := newCause("g1", "Fantasma")
+		cause.ID = "missing"
+		err := repo.Update(ctx, cause)
+		assertErrorIs(t, "Update", err, repository.ErrNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		if err := repo.Delete(ctx, cause.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		_, err := repo.GetByID(ctx, cause.ID)
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+		err = repo.Delete(ctx, cause.ID)
+		assertErrorIs(t, "Delete", err, repository.ErrNotFound)
+	})
+
+	t.Run("ListNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		a := createCause(t, repo, newCause("g1", "A"))
+		b := createCause(t, repo, newCause("g1", "B"))
+		c := createCause(t, repo, newCause("g1", "C"))
+
+		causes, err := repo.List(ctx, repository.CauseFilter{Limit: 10})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "List", causeIDs(causes), c.ID, b.ID, a.ID)
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		repo := newRepo(t)
+		animal := newCause("g1", "Refugio")
+		animal.Type = models.CauseTypeAnimal
+		animal.Location = "Córdoba"
+		animal = createCause(t, repo, animal)
+
+		environment := newCause("g1", "Reforestación")
+		environment.Type = models.CauseTypeEnvironment
+		environment.Location = "Córdoba"
+		environment = createCause(t, repo, environment)
+
+		draft := newCause("g1", "Comedor")
+		draft.Type = models.CauseTypeSocial
+		draft.Status = models.CauseStatusDraft
+		draft.Location = "Rosario"
+		draft = createCause(t, repo, draft)
+
+		tests := []struct {
+			name   string
+			filter repository.CauseFilter
+			want   []string
+		}{
+			{"Type", repository.CauseFilter{Type: models.CauseTypeAnimal}, []string{animal.ID}},
+			{"Status", repository.CauseFilter{Status: models.CauseStatusDraft}, []string{draft.ID}},
+			{"Location", repository.CauseFilter{Location: "Córdoba"}, []string{environment.ID, animal.ID}},
+			{"Combined", repository.CauseFilter{Type: models.CauseTypeEnvironment, Location: "Rosario"}, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.filter.Limit = 10
+				causes, err := repo.List(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("List: %v", err)
+				}
+				assertIDs(t, "List", causeIDs(causes), tt.want...)
+			})
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo := newRepo(t)
+		for i := 0; i < 3; i++ {
+			createCause(t, repo, newCause("g1", "Activa"))
+		}
+		draft := newCause("g1", "Borrador")
+		draft.Status = models.CauseStatusDraft
+		createCause(t, repo, draft)
+
+		tests := []struct {
+			name   string
+			filter repository.CauseFilter
+			want   int64
+		}{
+			{"All", repository.CauseFilter{}, 4},
+			{"Status", repository.CauseFilter{Status: models.CauseStatusActive}, 3},
+			{"IgnoresPagination", repository.CauseFilter{Status: models.CauseStatusActive, Limit: 1, Offset: 1}, 3},
+			{"NoMatches", repository.CauseFilter{Type: models.CauseTypeSocial}, 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				total, err := repo.Count(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("Count: %v", err)
+				}
+				if total != tt.want {
+					t.Errorf("Count = %d, want %d", total, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("ListSearch", func(t *testing.T) {
+		repo := newRepo(t)
+		dogs := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		garden := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		school := newCause("g2", "Útiles para la escuela")
+		school.Description = "Cuadernos y lápices para los niños del barrio"
+		createCause(t, repo, school)
+		shelter := newCause("g2", "Refugio del barrio")
+		shelter.Description = "Alimento para un perro rescatado"
+		createCause(t, repo, shelter)
+
+		tests := []struct {
+			name   string
+			search string
+			want   []string
+		}{
+			// La coincidencia en el título pesa más que la fecha de creación
+			{"TitleBeforeDescription", "perros", []string{dogs.ID, shelter.ID}},
+			{"SingularMatchesPlural", "perro", []string{dogs.ID, shelter.ID}},
+			{"PluralInZ", "lápiz", []string{school.ID}},
+			{"AccentInsensitive", "utiles NIÑO", []string{school.ID}},
+			{"IgnoresStopwords", "la huerta", []string{garden.ID}},
+			{"AllTermsRequired", "huerta perros", nil},
+			{"NoMatch", "gatos", nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				filter := repository.CauseFilter{Search: tt.search, Limit: 10}
+				causes, err := repo.List(ctx, filter)
+				if err != nil {
+					t.Fatalf("List: %v", err)
+				}
+				assertIDs(t, "List", causeIDs(causes), tt.want...)
+
+				total, err := repo.Count(ctx, filter)
+				if err != nil {
+					t.Fatalf("Count: %v", err)
+				}
+				if total != int64(len(tt.want)) {
+					t.Errorf("Count = %d, want %d", total, len(tt.want))
+				}
+			})
+		}
+
+		t.Run("AfterCursorKeepsNewestFirst", func(t *testing.T) {
+			after := &repository.Cursor{CreatedAt: time.Now().Add(time.Hour)}
+			causes, err := repo.List(ctx, repository.CauseFilter{Search: "perros", After: after, Limit: 10})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			assertIDs(t, "List", causeIDs(causes), shelter.ID, dogs.ID)
+		})
+	})
+
+	t.Run("SearchFollowsUpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+
+		cause.Title = "Rescate de gatos"
+		cause.Description = "Castraciones"
+		if err := repo.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		causes, err := repo.List(ctx, repository.CauseFilter{Search: "perros", Limit: 10})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "List old title", causeIDs(causes))
+		causes, err = repo.List(ctx, repository.CauseFilter{Search: "gato", Limit: 10})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "List new title", causeIDs(causes), cause.ID)
+
+		if err := repo.Delete(ctx, cause.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		causes, err = repo.List(ctx, repository.CauseFilter{Search: "gato", Limit: 10})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "List after delete", causeIDs(causes))
+	})
+
+	t.Run("ListPagination", func(t *testing.T) {
+		repo := newRepo(t)
+		var ids []string
+		for _, title := range []string{"A", "B", "C", "D", "E"} {
+			ids = append([]string{createCause(t, repo, newCause("g1", title)).ID}, ids...)
+		}
+
+		tests := []struct {
+			name          string
+			limit, offset int
+			want          []string
+		}{
+			{"FirstPage", 2, 0, ids[0:2]},
+			{"SecondPage", 2, 2, ids[2:4]},
+			{"LastPage", 2, 4, ids[4:5]},
+			{"PastTheEnd", 2, 10, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				causes, err := repo.List(ctx, repository.CauseFilter{Limit: tt.limit, Offset: tt.offset})
+				if err != nil {
+					t.Fatalf("List: %v", err)
+				}
+				assertIDs(t, "List", causeIDs(causes), tt.want...)
+			})
+		}
+	})
+
+	t.Run("ListAfterCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		var ids []string
+		for _, title := range []string{"A", "B", "C", "D", "E"} {
+			ids = append([]string{createCause(t, repo, newCause("g1", title)).ID}, ids...)
+		}
+
+		var seen []string
+		var after *repository.Cursor
+		for page := 0; page < 3; page++ {
+			filter := repository.CauseFilter{Limit: 2, After: after}
+			if after != nil {
+				filter.Offset = 99 // se ignora cuando hay cursor
+			}
+			causes, err := repo.List(ctx, filter)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(causes) == 0 {
+				t.Fatalf("page %d is empty", page)
+			}
+			seen = append(seen, causeIDs(causes)...)
+			last := causes[len(causes)-1]
+			after = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+
+			// Una causa nueva entre páginas no debe producir duplicados ni saltos
+			if page == 0 {
+				createCause(t, repo, newCause("g1", "Nueva"))
+			}
+		}
+		assertIDs(t, "pages", seen, ids...)
+
+		causes, err := repo.List(ctx, repository.CauseFilter{Limit: 2, After: after})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "List after the last cause", causeIDs(causes))
+	})
+
+	t.Run("AddUpdate", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+
+		before := time.Now()
+		update := &models.Update{ID: "ignored", Content: "Conseguimos alimento", ImageURLs: []string{"https://img/1.jpg"}}
+		if err := repo.AddUpdate(ctx, cause.ID, update); err != nil {
+			t.Fatalf("AddUpdate: %v", err)
+		}
+		if update.ID == "" || update.ID == "ignored" {
+			t.Errorf("AddUpdate did not assign a new ID, got %q", update.ID)
+		}
+		assertRecent(t, "CreatedAt", update.CreatedAt, before)
+
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if len(got.Updates) != 1 || got.Updates[0].ID != update.ID || got.Updates[0].Content != update.Content {
+			t.Errorf("Updates = %+v, want [%+v]", got.Updates, update)
+		}
+	})
+
+	t.Run("AddUpdateMissingCause", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.AddUpdate(ctx, "missing", &models.Update{Content: "x"})
+		assertErrorIs(t, "AddUpdate", err, repository.ErrNotFound)
+	})
+
+	t.Run("AddComment", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+
+		before := time.Now()
+		comment := &models.Comment{GuiverID: "g2", Content: "¡Cuenten conmigo!"}
+		if err := repo.AddComment(ctx, cause.ID, comment); err != nil {
+			t.Fatalf("AddComment: %v", err)
+		}
+		if comment.ID == "" {
+			t.Error("AddComment did not assign an ID")
+		}
+		assertRecent(t, "CreatedAt", comment.CreatedAt, before)
+	})
+
+	t.Run("AddCommentMissingCause", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.AddComment(ctx, "missing", &models.Comment{GuiverID: "g2", Content: "x"})
+		assertErrorIs(t, "AddComment", err, repository.ErrNotFound)
+	})
+
+	t.Run("LikeOncePerGuiver", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+
+		steps := []struct {
+			like     bool
+			guiverID string
+			want     int
+		}{
+			{true, "g2", 1},
+			{true, "g2", 1}, // repetir el like no suma
+			{true, "g3", 2},
+			{false, "g2", 1},
+			{false, "g2", 1}, // quitar un like inexistente no resta
+			{false, "g4", 1},
+			{false, "g3", 0},
+		}
+		for _, step := range steps {
+			var likes int
+			var err error
+			if step.like {
+				likes, err = repo.Like(ctx, cause.ID, step.guiverID)
+			} else {
+				likes, err = repo.Unlike(ctx, cause.ID, step.guiverID)
+			}
+			if err != nil {
+				t.Fatalf("Like(%v, %s): %v", step.like, step.guiverID, err)
+			}
+			if likes != step.want {
+				t.Fatalf("Like(%v, %s) = %d, want %d", step.like, step.guiverID, likes, step.want)
+			}
+			got, err := repo.GetByID(ctx, cause.ID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if got.Likes != step.want {
+				t.Fatalf("Likes after Like(%v, %s) = %d, want %d", step.like, step.guiverID, got.Likes, step.want)
+			}
+		}
+	})
+
+	t.Run("LikeConcurrent", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+
+		const guivers = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, 2*guivers)
+		for i := 0; i < guivers; i++ {
+			guiverID := fmt.Sprintf("fan-%d", i)
+			// Cada Guiver da like dos veces a la vez: solo debe contar una
+			for j := 0; j < 2; j++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := repo.Like(ctx, cause.ID, guiverID); err != nil {
+						errs <- err
+					}
+				}()
+			}
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("Like: %v", err)
+		}
+
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Likes != guivers {
+			t.Errorf("Likes = %d, want %d", got.Likes, guivers)
+		}
+	})
+
+	t.Run("LikedBy", func(t *testing.T) {
+		repo := newRepo(t)
+		liked := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		other := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		if _, err := repo.Like(ctx, liked.ID, "g2"); err != nil {
+			t.Fatalf("Like: %v", err)
+		}
+		if _, err := repo.Like(ctx, other.ID, "g3"); err != nil {
+			t.Fatalf("Like: %v", err)
+		}
+
+		got, err := repo.LikedBy(ctx, "g2", []string{liked.ID, other.ID, "missing"})
+		if err != nil {
+			t.Fatalf("LikedBy: %v", err)
+		}
+		if !got[liked.ID] || got[other.ID] || got["missing"] {
+			t.Errorf("LikedBy = %v, want only %s", got, liked.ID)
+		}
+
+		got, err = repo.LikedBy(ctx, "g2", nil)
+		if err != nil {
+			t.Fatalf("LikedBy(nil): %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("LikedBy(nil) = %v, want empty", got)
+		}
+	})
+
+	t.Run("UpdateKeepsLikes", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		if _, err := repo.Like(ctx, cause.ID, "g2"); err != nil {
+			t.Fatalf("Like: %v", err)
+		}
+
+		// cause tiene Likes desactualizado: Update no debe pisar el contador
+		cause.Title = "Rescate de gatos"
+		if err := repo.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if cause.Likes != 1 {
+			t.Errorf("Likes after Update = %d, want 1", cause.Likes)
+		}
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Likes != 1 {
+			t.Errorf("stored Likes = %d, want 1", got.Likes)
+		}
+	})
+
+	t.Run("DeleteRemovesLikes", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		if _, err := repo.Like(ctx, cause.ID, "g2"); err != nil {
+			t.Fatalf("Like: %v", err)
+		}
+		if err := repo.Delete(ctx, cause.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		// Una Causa nueva con el mismo ID no hereda los likes
+		recreated := newCause("g1", "Rescate de perros")
+		recreated.ID = cause.ID
+		createCause(t, repo, recreated)
+		got, err := repo.LikedBy(ctx, "g2", []string{cause.ID})
+		if err != nil {
+			t.Fatalf("LikedBy: %v", err)
+		}
+		if got[cause.ID] {
+			t.Error("LikedBy after Delete = true, want false")
+		}
+	})
+
+	t.Run("LikeMissingCause", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.Like(ctx, "missing", "g1")
+		assertErrorIs(t, "Like", err, repository.ErrNotFound)
+		_, err = repo.Unlike(ctx, "missing", "g1")
+		assertErrorIs(t, "Unlike", err, repository.ErrNotFound)
+	})
+
+	testCauseComments(t, newRepo)
+	testCauseUpdates(t, newRepo)
+}
+
+func newCause(guiverID, title string) *models.Cause {
+	return &models.Cause{
+		GuiverID:    guiverID,
+		Title:       title,
+		Description: "Necesitamos ayuda para " + title,
+		Type:        models.CauseTypeAnimal,
+		Status:      models.CauseStatusActive,
+		Location:    "Buenos Aires",
+		ImageURLs:   []string{"https://img/cause.jpg"},
+		ContactInfo: models.ContactInfo{Email: "causa@example.com"},
+	}
+}
+
+// createCause crea la Causa y espera para que la siguiente tenga otra fecha
+func createCause(t *testing.T, repo repository.CauseRepository, cause *models.Cause) *models.Cause {
+	t.Helper()
+	if err := repo.Create(context.Background(), cause); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	tick()
+	return cause
+}
+
+func causeIDs(causes []*models.Cause) []string {
+	ids := make([]string, len(causes))
+	for i, cause := range causes {
+		ids[i] = cause.ID
+	}
+	return ids
+}
@@ -0,0 +1,292 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// testCauseComments verifica los comentarios de repository.CauseRepository
+func testCauseComments(t *testing.T, newRepo func(t *testing.T) repository.CauseRepository) {
+	ctx := context.Background()
+
+	t.Run("CommentCount", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		first := addComment(t, repo, cause.ID, "g2", "Primero")
+		addComment(t, repo, cause.ID, "g3", "Segundo")
+		assertCommentCount(t, repo, cause.ID, 2)
+
+		// Update con un contador desactualizado no lo pisa
+		cause.Title = "Rescate de gatos"
+		if err := repo.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if cause.CommentCount != 2 {
+			t.Errorf("CommentCount after Update = %d, want 2", cause.CommentCount)
+		}
+
+		if err := repo.DeleteComment(ctx, cause.ID, first.ID); err != nil {
+			t.Fatalf("DeleteComment: %v", err)
+		}
+		assertCommentCount(t, repo, cause.ID, 1)
+	})
+
+	t.Run("GetComment", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		comment := addComment(t, repo, cause.ID, "g2", "¡Cuenten conmigo!")
+
+		got, err := repo.GetComment(ctx, cause.ID, comment.ID)
+		if err != nil {
+			t.Fatalf("GetComment: %v", err)
+		}
+		if got.GuiverID != "g2" || got.Content != "¡Cuenten conmigo!" || got.EditedAt != nil {
+			t.Errorf("GetComment = %+v", got)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, comment.CreatedAt)
+
+		_, err = repo.GetComment(ctx, cause.ID, "missing")
+		assertErrorIs(t, "GetComment", err, repository.ErrNotFound)
+		other := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		_, err = repo.GetComment(ctx, other.ID, comment.ID)
+		assertErrorIs(t, "GetComment from another cause", err, repository.ErrNotFound)
+	})
+
+	t.Run("ListCommentsNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		other := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		var ids []string
+		for _, content := range []string{"A", "B", "C", "D", "E"} {
+			ids = append([]string{addComment(t, repo, cause.ID, "g2", content).ID}, ids...)
+		}
+		addComment(t, repo, other.ID, "g2", "Otra causa")
+
+		var got []string
+		var after *repository.Cursor
+		for page := 0; page < 5; page++ {
+			comments, err := repo.ListComments(ctx, cause.ID, repository.CommentFilter{Limit: 2, After: after})
+			if err != nil {
+				t.Fatalf("ListComments: %v", err)
+			}
+			if len(comments) == 0 {
+				break
+			}
+			for _, comment := range comments {
+				got = append(got, comment.ID)
+			}
+			last := comments[len(comments)-1]
+			after = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+		assertIDs(t, "ListComments", got, ids...)
+	})
+
+	t.Run("ListCommentsMissingCause", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.ListComments(ctx, "missing", repository.CommentFilter{Limit: 10})
+		assertErrorIs(t, "ListComments", err, repository.ErrNotFound)
+	})
+
+	t.Run("UpdateComment", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		comment := addComment(t, repo, cause.ID, "g2", "Cuenten conmigo")
+
+		before := time.Now()
+		edit := &models.Comment{ID: comment.ID, GuiverID: "intruso", Content: "Cuenten conmigo el sábado"}
+		if err := repo.UpdateComment(ctx, cause.ID, edit); err != nil {
+			t.Fatalf("UpdateComment: %v", err)
+		}
+		// Solo cambia el contenido; el resto se devuelve como está guardado
+		if edit.GuiverID != "g2" || edit.Content != "Cuenten conmigo el sábado" {
+			t.Errorf("UpdateComment result = %+v", edit)
+		}
+		if edit.EditedAt == nil {
+			t.Fatal("EditedAt not set")
+		}
+		assertRecent(t, "EditedAt", *edit.EditedAt, before)
+
+		got, err := repo.GetComment(ctx, cause.ID, comment.ID)
+		if err != nil {
+			t.Fatalf("GetComment: %v", err)
+		}
+		if got.GuiverID != "g2" || got.Content != "Cuenten conmigo el sábado" || got.EditedAt == nil {
+			t.Errorf("GetComment after UpdateComment = %+v", got)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, comment.CreatedAt)
+
+		missing := &models.Comment{ID: "missing", Content: "x"}
+		err = repo.UpdateComment(ctx, cause.ID, missing)
+		assertErrorIs(t, "UpdateComment", err, repository.ErrNotFound)
+	})
+
+	t.Run("DeleteComment", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		comment := addComment(t, repo, cause.ID, "g2", "Cuenten conmigo")
+
+		if err := repo.DeleteComment(ctx, cause.ID, comment.ID); err != nil {
+			t.Fatalf("DeleteComment: %v", err)
+		}
+		_, err := repo.GetComment(ctx, cause.ID, comment.ID)
+		assertErrorIs(t, "GetComment", err, repository.ErrNotFound)
+
+		// Borrar dos veces no descuenta dos veces
+		err = repo.DeleteComment(ctx, cause.ID, comment.ID)
+		assertErrorIs(t, "DeleteComment", err, repository.ErrNotFound)
+		assertCommentCount(t, repo, cause.ID, 0)
+	})
+
+	t.Run("Replies", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		question := addComment(t, repo, cause.ID, "g2", "¿Dónde llevo las mantas?")
+		answer := addReply(t, repo, cause.ID, question.ID, "g1", "Al refugio de la calle 5")
+		thanks := addReply(t, repo, cause.ID, answer.ID, "g2", "¡Gracias!")
+		other := addComment(t, repo, cause.ID, "g3", "Yo llevo comida")
+		otherReply := addReply(t, repo, cause.ID, other.ID, "g1", "¡Genial!")
+
+		if answer.ThreadID != question.ID || thanks.ThreadID != question.ID {
+			t.Errorf("ThreadID = %q, %q, want %q", answer.ThreadID, thanks.ThreadID, question.ID)
+		}
+		got, err := repo.GetComment(ctx, cause.ID, thanks.ID)
+		if err != nil {
+			t.Fatalf("GetComment: %v", err)
+		}
+		if got.ParentID != answer.ID || got.ThreadID != question.ID {
+			t.Errorf("GetComment = %+v", got)
+		}
+		assertCommentCount(t, repo, cause.ID, 5)
+
+		roots, err := repo.ListComments(ctx, cause.ID, repository.CommentFilter{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListComments: %v", err)
+		}
+		assertIDs(t, "ListComments", commentIDs(roots), other.ID, question.ID)
+
+		replies, err := repo.ListReplies(ctx, cause.ID, []string{question.ID})
+		if err != nil {
+			t.Fatalf("ListReplies: %v", err)
+		}
+		assertIDs(t, "ListReplies", commentIDs(replies), answer.ID, thanks.ID)
+
+		replies, err = repo.ListReplies(ctx, cause.ID, []string{question.ID, other.ID})
+		if err != nil {
+			t.Fatalf("ListReplies: %v", err)
+		}
+		assertIDs(t, "ListReplies of two threads", commentIDs(replies), answer.ID, thanks.ID, otherReply.ID)
+	})
+
+	t.Run("ReplyMissingParent", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		other := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		foreign := addComment(t, repo, other.ID, "g2", "Otra causa")
+
+		for _, parentID := range []string{"missing", foreign.ID} {
+			reply := &models.Comment{GuiverID: "g2", Content: "Hola", ParentID: parentID}
+			err := repo.AddComment(ctx, cause.ID, reply)
+			assertErrorIs(t, "AddComment", err, repository.ErrNotFound)
+		}
+		assertCommentCount(t, repo, cause.ID, 0)
+	})
+
+	t.Run("DeleteCommentWithReplies", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		question := addComment(t, repo, cause.ID, "g2", "¿Dónde llevo las mantas?")
+		answer := addReply(t, repo, cause.ID, question.ID, "g1", "Al refugio")
+		thanks := addReply(t, repo, cause.ID, answer.ID, "g2", "¡Gracias!")
+		sibling := addReply(t, repo, cause.ID, question.ID, "g3", "Yo también llevo")
+
+		// Borrar una respuesta borra las que cuelgan de ella, no sus hermanas
+		if err := repo.DeleteComment(ctx, cause.ID, answer.ID); err != nil {
+			t.Fatalf("DeleteComment: %v", err)
+		}
+		_, err := repo.GetComment(ctx, cause.ID, thanks.ID)
+		assertErrorIs(t, "GetComment of nested reply", err, repository.ErrNotFound)
+		replies, err := repo.ListReplies(ctx, cause.ID, []string{question.ID})
+		if err != nil {
+			t.Fatalf("ListReplies: %v", err)
+		}
+		assertIDs(t, "ListReplies", commentIDs(replies), sibling.ID)
+		assertCommentCount(t, repo, cause.ID, 2)
+
+		if err := repo.DeleteComment(ctx, cause.ID, question.ID); err != nil {
+			t.Fatalf("DeleteComment: %v", err)
+		}
+		_, err = repo.GetComment(ctx, cause.ID, sibling.ID)
+		assertErrorIs(t, "GetComment of reply", err, repository.ErrNotFound)
+		assertCommentCount(t, repo, cause.ID, 0)
+	})
+
+	t.Run("Mentions", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		comment := &models.Comment{GuiverID: "g2", Content: "@Ana @Luis ¿se suman?", Mentions: []string{"g3", "g4"}}
+		if err := repo.AddComment(ctx, cause.ID, comment); err != nil {
+			t.Fatalf("AddComment: %v", err)
+		}
+
+		got, err := repo.GetComment(ctx, cause.ID, comment.ID)
+		if err != nil {
+			t.Fatalf("GetComment: %v", err)
+		}
+		assertIDs(t, "Mentions", got.Mentions, "g3", "g4")
+
+		edit := &models.Comment{ID: comment.ID, Content: "@Ana ¿te sumás?", Mentions: []string{"g3"}}
+		if err := repo.UpdateComment(ctx, cause.ID, edit); err != nil {
+			t.Fatalf("UpdateComment: %v", err)
+		}
+		got, err = repo.GetComment(ctx, cause.ID, comment.ID)
+		if err != nil {
+			t.Fatalf("GetComment: %v", err)
+		}
+		assertIDs(t, "Mentions after UpdateComment", got.Mentions, "g3")
+	})
+}
+
+// addReply responde al comentario parentID y espera para que el siguiente tenga otra fecha
+func addReply(t *testing.T, repo repository.CauseRepository, causeID, parentID, guiverID, content string) *models.Comment {
+	t.Helper()
+	comment := &models.Comment{GuiverID: guiverID, Content: content, ParentID: parentID}
+	if err := repo.AddComment(context.Background(), causeID, comment); err != nil {
+		t.Fatalf("AddComment reply: %v", err)
+	}
+	tick()
+	return comment
+}
+
+func commentIDs(comments []*models.Comment) []string {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	return ids
+}
+
+// addComment agrega el comentario y espera para que el siguiente tenga otra fecha
+func addComment(t *testing.T, repo repository.CauseRepository, causeID, guiverID, content string) *models.Comment {
+	t.Helper()
+	comment := &models.Comment{GuiverID: guiverID, Content: content}
+	if err := repo.AddComment(context.Background(), causeID, comment); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	tick()
+	return comment
+}
+
+func assertCommentCount(t *testing.T, repo repository.CauseRepository, causeID string, want int) {
+	t.Helper()
+	cause, err := repo.GetByID(context.Background(), causeID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if cause.CommentCount != want {
+		t.Errorf("CommentCount = %d, want %d", cause.CommentCount, want)
+	}
+}
@@ -0,0 +1,145 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// TestGuiverRepository verifica el contrato de repository.GuiverRepository.
+// newRepo debe devolver un repositorio vacío en cada llamada.
+func TestGuiverRepository(t *testing.T, newRepo func(t *testing.T) repository.GuiverRepository) {
+	ctx := context.Background()
+
+	newGuiver := func() *models.Guiver {
+		return &models.Guiver{
+			Email:       "ana@example.com",
+			DisplayName: "Ana",
+			Type:        models.GuiverTypeHelper,
+			Bio:         "Voluntaria",
+			WhatsApp:    "+5491100000000",
+		}
+	}
+
+	t.Run("CreateAssignsIDAndTimestamps", func(t *testing.T) {
+		repo := newRepo(t)
+		before := time.Now()
+		guiver := newGuiver()
+		if err := repo.Create(ctx, guiver); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if guiver.ID == "" {
+			t.Fatal("Create did not assign an ID")
+		}
+		assertRecent(t, "CreatedAt", guiver.CreatedAt, before)
+		assertTime(t, "UpdatedAt", guiver.UpdatedAt, guiver.CreatedAt)
+
+		got, err := repo.GetByID(ctx, guiver.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Email != guiver.Email || got.DisplayName != guiver.DisplayName || got.Type != guiver.Type ||
+			got.Bio != guiver.Bio || got.WhatsApp != guiver.WhatsApp {
+			t.Errorf("GetByID = %+v, want %+v", got, guiver)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, guiver.CreatedAt)
+	})
+
+	t.Run("CreateKeepsGivenID", func(t *testing.T) {
+		repo := newRepo(t)
+		guiver := newGuiver()
+		guiver.ID = "firebase-uid"
+		if err := repo.Create(ctx, guiver); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if guiver.ID != "firebase-uid" {
+			t.Errorf("ID = %q, want %q", guiver.ID, "firebase-uid")
+		}
+		if _, err := repo.GetByID(ctx, "firebase-uid"); err != nil {
+			t.Errorf("GetByID: %v", err)
+		}
+	})
+
+	t.Run("CreateDuplicateID", func(t *testing.T) {
+		repo := newRepo(t)
+		first := newGuiver()
+		first.ID = "firebase-uid"
+		if err := repo.Create(ctx, first); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		second := newGuiver()
+		second.ID = "firebase-uid"
+		second.DisplayName = "Impostor"
+		err := repo.Create(ctx, second)
+		assertErrorIs(t, "Create", err, repository.ErrConflict)
+
+		got, err := repo.GetByID(ctx, "firebase-uid")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.DisplayName != first.DisplayName {
+			t.Errorf("DisplayName = %q, want the original %q", got.DisplayName, first.DisplayName)
+		}
+	})
+
+	t.Run("GetByIDMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetByID(ctx, "missing")
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		guiver := newGuiver()
+		if err := repo.Create(ctx, guiver); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		created := guiver.CreatedAt
+
+		tick()
+		guiver.DisplayName = "Ana María"
+		guiver.Bio = "Rescatista"
+		if err := repo.Update(ctx, guiver); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if !guiver.UpdatedAt.After(created) {
+			t.Errorf("UpdatedAt = %v, want after %v", guiver.UpdatedAt, created)
+		}
+
+		got, err := repo.GetByID(ctx, guiver.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.DisplayName != "Ana María" || got.Bio != "Rescatista" {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, created)
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		guiver := newGuiver()
+		guiver.ID = "missing"
+		err := repo.Update(ctx, guiver)
+		assertErrorIs(t, "Update", err, repository.ErrNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		guiver := newGuiver()
+		if err := repo.Create(ctx, guiver); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Delete(ctx, guiver.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		_, err := repo.GetByID(ctx, guiver.ID)
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+		err = repo.Delete(ctx, guiver.ID)
+		assertErrorIs(t, "Delete", err, repository.ErrNotFound)
+	})
+}
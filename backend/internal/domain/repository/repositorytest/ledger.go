@@ -0,0 +1,412 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// TestLedgerRepository verifica el contrato de repository.LedgerRepository.
+// newRepo debe devolver un repositorio vacío en cada llamada.
+func TestLedgerRepository(t *testing.T, newRepo func(t *testing.T) repository.LedgerRepository) {
+	ctx := context.Background()
+
+	t.Run("AppendAndList", func(t *testing.T) {
+		repo := newRepo(t)
+		before := time.Now()
+		entry := models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(10000, "ARS"))
+		entry.Description = "Aporte"
+		entry.Reference = "pledge:p1"
+		entry.CreatedBy = "g1"
+		entry.GuiverID = "g2"
+		if err := repo.Append(ctx, entry); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		if entry.ID == "" {
+			t.Fatal("Append did not assign an ID")
+		}
+		assertRecent(t, "CreatedAt", entry.CreatedAt, before)
+		appendEntry(t, repo, models.NewLedgerEntry("c2", models.LedgerEntryDonation, models.NewMoney(500, "ARS")))
+
+		list, err := repo.ListByCause(ctx, "c1", repository.LedgerFilter{})
+		if err != nil {
+			t.Fatalf("ListByCause: %v", err)
+		}
+		if len(list) != 1 {
+			t.Fatalf("ListByCause returned %d entries, want 1", len(list))
+		}
+		got := list[0]
+		if got.ID != entry.ID || got.CauseID != "c1" || got.Type != models.LedgerEntryDonation ||
+			got.Amount != models.NewMoney(10000, "ARS") || got.Description != "Aporte" || got.Reference != "pledge:p1" ||
+			got.CreatedBy != "g1" || got.GuiverID != "g2" {
+			t.Errorf("ListByCause = %+v", got)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, entry.CreatedAt)
+		if len(got.Postings) != 2 ||
+			got.Postings[0] != (models.Posting{Account: models.AccountHeld, Amount: 10000}) ||
+			got.Postings[1] != (models.Posting{Account: models.AccountDonations, Amount: -10000}) {
+			t.Errorf("Postings = %+v", got.Postings)
+		}
+
+		list, err = repo.ListByCause(ctx, "missing", repository.LedgerFilter{})
+		if err != nil {
+			t.Fatalf("ListByCause: %v", err)
+		}
+		if len(list) != 0 {
+			t.Errorf("ListByCause of a cause without entries = %+v", list)
+		}
+	})
+
+	t.Run("AppendWithIDOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		entry := models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(10000, "ARS"))
+		entry.ID = "pledge:p1"
+		if err := repo.Append(ctx, entry); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		again := models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(10000, "ARS"))
+		again.ID = "pledge:p1"
+		err := repo.Append(ctx, again)
+		assertErrorIs(t, "Append with a duplicate ID", err, repository.ErrConflict)
+		assertBalance(t, repo, "c1", "ARS", models.AccountHeld, 10000)
+	})
+
+	t.Run("AppendRejectsInvalid", func(t *testing.T) {
+		repo := newRepo(t)
+		unbalanced := models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(10000, "ARS"))
+		unbalanced.Postings[1].Amount = -9000
+		assertErrorIs(t, "Append unbalanced", repo.Append(ctx, unbalanced), models.ErrUnbalancedEntry)
+
+		if err := repo.Append(ctx, models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(0, "ARS"))); err == nil {
+			t.Error("Append with a zero amount succeeded")
+		}
+		if err := repo.Append(ctx, models.NewLedgerEntry("c1", "gift", models.NewMoney(100, "ARS"))); err == nil {
+			t.Error("Append with an unknown type succeeded")
+		}
+
+		list, err := repo.ListByCause(ctx, "c1", repository.LedgerFilter{})
+		if err != nil {
+			t.Fatalf("ListByCause: %v", err)
+		}
+		if len(list) != 0 {
+			t.Errorf("ListByCause after invalid appends = %+v", list)
+		}
+	})
+
+	t.Run("Balances", func(t *testing.T) {
+		repo := newRepo(t)
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(10000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(5000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryProductDonation, models.NewMoney(750, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryRefund, models.NewMoney(5000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDisbursement, models.NewMoney(8000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryExpense, models.NewMoney(3000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(20, "USD")))
+		appendEntry(t, repo, models.NewLedgerEntry("c2", models.LedgerEntryDonation, models.NewMoney(999, "ARS")))
+
+		balances, err := repo.Balances(ctx, "c1")
+		if err != nil {
+			t.Fatalf("Balances: %v", err)
+		}
+		want := map[models.LedgerAccount]int64{
+			models.AccountHeld:             2750,
+			models.AccountDisbursed:        5000,
+			models.AccountDonations:        -15000,
+			models.AccountProductDonations: -750,
+			models.AccountRefunds:          5000,
+			models.AccountExpenses:         3000,
+		}
+		for account, amount := range want {
+			if balances["ARS"][account] != amount {
+				t.Errorf("ARS %s balance = %d, want %d", account, balances["ARS"][account], amount)
+			}
+		}
+		if balances["USD"][models.AccountHeld] != 20 || len(balances) != 2 {
+			t.Errorf("Balances = %v", balances)
+		}
+
+		// Partida doble: los saldos de cada moneda suman cero
+		for currency, accounts := range balances {
+			var sum int64
+			for _, amount := range accounts {
+				sum += amount
+			}
+			if sum != 0 {
+				t.Errorf("%s balances add up to %d, want 0", currency, sum)
+			}
+		}
+
+		balances, err = repo.Balances(ctx, "missing")
+		if err != nil {
+			t.Fatalf("Balances: %v", err)
+		}
+		if len(balances) != 0 {
+			t.Errorf("Balances of a cause without entries = %v", balances)
+		}
+	})
+
+	t.Run("ListNewestFirstByType", func(t *testing.T) {
+		repo := newRepo(t)
+		var donations, all []string
+		for i := 1; i <= 5; i++ {
+			entryType := models.LedgerEntryDonation
+			if i%2 == 0 {
+				entryType = models.LedgerEntryRefund
+			}
+			entry := appendEntry(t, repo, models.NewLedgerEntry("c1", entryType, models.NewMoney(int64(i)*100, "ARS")))
+			all = append([]string{entry.ID}, all...)
+			if entryType == models.LedgerEntryDonation {
+				donations = append([]string{entry.ID}, donations...)
+			}
+		}
+
+		var got []string
+		var after *repository.Cursor
+		for page := 0; page < 5; page++ {
+			list, err := repo.ListByCause(ctx, "c1", repository.LedgerFilter{Limit: 2, After: after})
+			if err != nil {
+				t.Fatalf("ListByCause: %v", err)
+			}
+			if len(list) == 0 {
+				break
+			}
+			for _, entry := range list {
+				got = append(got, entry.ID)
+			}
+			last := list[len(list)-1]
+			after = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+		assertIDs(t, "ListByCause", got, all...)
+
+		list, err := repo.ListByCause(ctx, "c1", repository.LedgerFilter{Type: models.LedgerEntryDonation})
+		if err != nil {
+			t.Fatalf("ListByCause: %v", err)
+		}
+		got = nil
+		for _, entry := range list {
+			got = append(got, entry.ID)
+		}
+		assertIDs(t, "ListByCause by type", got, donations...)
+	})
+
+	t.Run("AppendConcurrent", func(t *testing.T) {
+		repo := newRepo(t)
+
+		// Las notificaciones repetidas intentan registrar el mismo movimiento a
+		// la vez: queda uno solo
+		const attempts = 5
+		var wg sync.WaitGroup
+		errs := make(chan error, attempts)
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				entry := models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(1000, "ARS"))
+				entry.ID = "pledge:p1"
+				if err := repo.Append(ctx, entry); err != nil && !errors.Is(err, repository.ErrConflict) {
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("Append: %v", err)
+		}
+		assertBalance(t, repo, "c1", "ARS", models.AccountHeld, 1000)
+	})
+
+	t.Run("AppendRequiresFunds", func(t *testing.T) {
+		repo := newRepo(t)
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(1000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c2", models.LedgerEntryDonation, models.NewMoney(5000, "ARS")))
+
+		// Las entregas salen de lo retenido de la causa en esa moneda
+		for _, entry := range []*models.LedgerEntry{
+			models.NewLedgerEntry("c1", models.LedgerEntryDisbursement, models.NewMoney(1001, "ARS")),
+			models.NewLedgerEntry("c1", models.LedgerEntryDisbursement, models.NewMoney(10, "USD")),
+			models.NewLedgerEntry("c1", models.LedgerEntryExpense, models.NewMoney(1, "ARS")),
+		} {
+			err := repo.Append(ctx, entry)
+			assertErrorIs(t, "Append "+string(entry.Type), err, repository.ErrInsufficientFunds)
+			assertErrorIs(t, "Append "+string(entry.Type), err, repository.ErrConflict)
+		}
+
+		// Y los gastos, de lo entregado
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDisbursement, models.NewMoney(1000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryExpense, models.NewMoney(400, "ARS")))
+		err := repo.Append(ctx, models.NewLedgerEntry("c1", models.LedgerEntryExpense, models.NewMoney(601, "ARS")))
+		assertErrorIs(t, "Append expense", err, repository.ErrInsufficientFunds)
+
+		assertBalance(t, repo, "c1", "ARS", models.AccountHeld, 0)
+		assertBalance(t, repo, "c1", "ARS", models.AccountDisbursed, 600)
+		assertBalance(t, repo, "c1", "ARS", models.AccountExpenses, 400)
+	})
+
+	t.Run("AppendFundsConcurrent", func(t *testing.T) {
+		repo := newRepo(t)
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(1000, "ARS")))
+
+		// Entregas simultáneas que juntas superan lo retenido: solo entran las
+		// que alcanzan
+		const attempts = 5
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		recorded := 0
+		errs := make(chan error, attempts)
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Los conflictos por concurrencia se reintentan como lo haría el cliente
+				for {
+					err := repo.Append(ctx, models.NewLedgerEntry("c1", models.LedgerEntryDisbursement, models.NewMoney(400, "ARS")))
+					switch {
+					case err == nil:
+						mu.Lock()
+						recorded++
+						mu.Unlock()
+					case errors.Is(err, repository.ErrInsufficientFunds):
+					case errors.Is(err, repository.ErrConflict):
+						continue
+					default:
+						errs <- err
+					}
+					return
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("Append: %v", err)
+		}
+		if recorded != 2 {
+			t.Errorf("recorded %d disbursements, want 2", recorded)
+		}
+		assertBalance(t, repo, "c1", "ARS", models.AccountHeld, 200)
+		assertBalance(t, repo, "c1", "ARS", models.AccountDisbursed, 800)
+	})
+
+	t.Run("DonationsByCauseAndSeller", func(t *testing.T) {
+		repo := newRepo(t)
+		appendEntry(t, repo, productDonation("c1", "s1", models.LedgerEntryProductDonation, 375, "ARS"))
+		appendEntry(t, repo, productDonation("c2", "s1", models.LedgerEntryProductDonation, 750, "ARS"))
+		appendEntry(t, repo, productDonation("c1", "s2", models.LedgerEntryProductDonation, 1500, "ARS"))
+		appendEntry(t, repo, productDonation("c1", "s2", models.LedgerEntryProductDonation, 375, "USD"))
+		appendEntry(t, repo, productDonation("c1", "s1", models.LedgerEntryProductRefund, 375, "ARS"))
+		// Los aportes y sus reembolsos no son donaciones de pedidos
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryDonation, models.NewMoney(10000, "ARS")))
+		appendEntry(t, repo, models.NewLedgerEntry("c1", models.LedgerEntryRefund, models.NewMoney(10000, "ARS")))
+
+		assertDonations(t, repo, repository.DonationFilter{CauseID: "c1"}, -375, 375, 1500, 375)
+		assertDonations(t, repo, repository.DonationFilter{GuiverID: "s1"}, -375, 750, 375)
+		assertDonations(t, repo, repository.DonationFilter{CauseID: "c1", GuiverID: "s2"}, 375, 1500)
+		assertDonationTotals(t, repo, repository.DonationFilter{CauseID: "c1"}, map[string]int64{"ARS": 1500, "USD": 375})
+		assertDonationTotals(t, repo, repository.DonationFilter{GuiverID: "s1"}, map[string]int64{"ARS": 750})
+		assertDonationTotals(t, repo, repository.DonationFilter{GuiverID: "nobody"}, map[string]int64{})
+
+		entries := assertDonations(t, repo, repository.DonationFilter{CauseID: "c2"}, 750)
+		if entry := entries[0]; entry.GuiverID != "s1" || entry.Type != models.LedgerEntryProductDonation || len(entry.Postings) != 2 {
+			t.Errorf("ListDonations = %+v", entry)
+		}
+	})
+
+	t.Run("ListDonationsNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		var ids []string
+		for i := 1; i <= 5; i++ {
+			entry := appendEntry(t, repo, productDonation("c1", "seller", models.LedgerEntryProductDonation, int64(i)*100, "ARS"))
+			ids = append([]string{entry.ID}, ids...)
+		}
+
+		var got []string
+		var after *repository.Cursor
+		for page := 0; page < 5; page++ {
+			list, err := repo.ListDonations(ctx, repository.DonationFilter{CauseID: "c1", Limit: 2, After: after})
+			if err != nil {
+				t.Fatalf("ListDonations: %v", err)
+			}
+			if len(list) == 0 {
+				break
+			}
+			for _, entry := range list {
+				got = append(got, entry.ID)
+			}
+			last := list[len(list)-1]
+			after = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+		assertIDs(t, "ListDonations", got, ids...)
+	})
+}
+
+// productDonation crea un movimiento de la donación de un pedido del
+// emprendedor guiverID
+func productDonation(causeID, guiverID string, entryType models.LedgerEntryType, amount int64, currency string) *models.LedgerEntry {
+	entry := models.NewLedgerEntry(causeID, entryType, models.NewMoney(amount, currency))
+	entry.GuiverID = guiverID
+	return entry
+}
+
+// appendEntry registra el movimiento y espera para que el siguiente tenga otra fecha
+func appendEntry(t *testing.T, repo repository.LedgerRepository, entry *models.LedgerEntry) *models.LedgerEntry {
+	t.Helper()
+	if err := repo.Append(context.Background(), entry); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	tick()
+	return entry
+}
+
+func assertBalance(t *testing.T, repo repository.LedgerRepository, causeID, currency string, account models.LedgerAccount, want int64) {
+	t.Helper()
+	balances, err := repo.Balances(context.Background(), causeID)
+	if err != nil {
+		t.Fatalf("Balances: %v", err)
+	}
+	if got := balances[currency][account]; got != want {
+		t.Errorf("%s %s balance = %d, want %d", currency, account, got, want)
+	}
+}
+
+// assertDonations verifica lo que suman a lo donado los movimientos que
+// cumplen el filtro, del más nuevo al más antiguo, y los devuelve
+func assertDonations(t *testing.T, repo repository.LedgerRepository, filter repository.DonationFilter, amounts ...int64) []*models.LedgerEntry {
+	t.Helper()
+	entries, err := repo.ListDonations(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("ListDonations: %v", err)
+	}
+	got := make([]string, len(entries))
+	for i, entry := range entries {
+		got[i] = fmt.Sprint(entry.DonatedAmount())
+	}
+	want := make([]string, len(amounts))
+	for i, amount := range amounts {
+		want[i] = fmt.Sprint(amount)
+	}
+	assertIDs(t, "ListDonations amounts", got, want...)
+	return entries
+}
+
+func assertDonationTotals(t *testing.T, repo repository.LedgerRepository, filter repository.DonationFilter, want map[string]int64) {
+	t.Helper()
+	got, err := repo.DonationTotals(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("DonationTotals: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DonationTotals = %v, want %v", got, want)
+	}
+	for currency, amount := range want {
+		if got[currency] != amount {
+			t.Errorf("DonationTotals = %v, want %v", got, want)
+		}
+	}
+}
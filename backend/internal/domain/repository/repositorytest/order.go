@@ -0,0 +1,227 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// TestOrderRepository verifica el contrato de repository.OrderRepository.
+// newRepo debe devolver un repositorio vacío en cada llamada.
+func TestOrderRepository(t *testing.T, newRepo func(t *testing.T) repository.OrderRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		before := time.Now()
+		order := newOrder("buyer", "seller", "c1", 3)
+		order.VariantID = "m-red"
+		order.Status = models.OrderStatusDelivered
+		if err := repo.Create(ctx, order); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if order.ID == "" {
+			t.Fatal("Create did not assign an ID")
+		}
+		assertRecent(t, "CreatedAt", order.CreatedAt, before)
+		if order.Status != models.OrderStatusPending || order.Total != models.NewMoney(7500, "ARS") ||
+			order.DonationAmount != models.NewMoney(1125, "ARS") {
+			t.Errorf("Create = %+v, want pending with Total 7500 and DonationAmount 1125", order)
+		}
+
+		got, err := repo.GetByID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.ProductID != "p1" || got.VariantID != "m-red" || got.CauseID != "c1" || got.SellerID != "seller" || got.BuyerID != "buyer" ||
+			got.Quantity != 3 || got.UnitPrice != models.NewMoney(2500, "ARS") || got.Total != models.NewMoney(7500, "ARS") ||
+			got.DonationPercentage != 15 || got.DonationAmount != models.NewMoney(1125, "ARS") || got.Status != models.OrderStatusPending ||
+			got.PaymentID != "pay-1" {
+			t.Errorf("GetByID = %+v", got)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, order.CreatedAt)
+
+		_, err = repo.GetByID(ctx, "missing")
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+	})
+
+	t.Run("CreateWithID", func(t *testing.T) {
+		repo := newRepo(t)
+		order := newOrder("buyer", "seller", "c1", 1)
+		order.ID = "order-1"
+		if err := repo.Create(ctx, order); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if order.ID != "order-1" {
+			t.Errorf("ID = %q, want order-1", order.ID)
+		}
+		again := newOrder("other", "seller", "c1", 2)
+		again.ID = "order-1"
+		err := repo.Create(ctx, again)
+		assertErrorIs(t, "Create with a duplicate ID", err, repository.ErrConflict)
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		order := createOrder(t, repo, newOrder("buyer", "seller", "c1", 2))
+
+		_, err := repo.UpdateStatus(ctx, order.ID, models.OrderStatusShipped)
+		assertErrorIs(t, "UpdateStatus from pending to shipped", err, repository.ErrConflict)
+
+		got, err := repo.UpdateStatus(ctx, order.ID, models.OrderStatusPaid)
+		if err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		if got.Status != models.OrderStatusPaid || got.DonationAmount != models.NewMoney(750, "ARS") {
+			t.Errorf("UpdateStatus = %+v", got)
+		}
+		// Repetir el estado no cambia nada
+		if again, err := repo.UpdateStatus(ctx, order.ID, models.OrderStatusPaid); err != nil {
+			t.Fatalf("UpdateStatus again: %v", err)
+		} else if !again.UpdatedAt.Equal(got.UpdatedAt) {
+			t.Errorf("UpdatedAt = %v, want %v", again.UpdatedAt, got.UpdatedAt)
+		}
+
+		for _, status := range []models.OrderStatus{models.OrderStatusShipped, models.OrderStatusDelivered} {
+			if _, err := repo.UpdateStatus(ctx, order.ID, status); err != nil {
+				t.Fatalf("UpdateStatus to %s: %v", status, err)
+			}
+		}
+		_, err = repo.UpdateStatus(ctx, order.ID, models.OrderStatusCancelled)
+		assertErrorIs(t, "UpdateStatus from delivered to cancelled", err, repository.ErrConflict)
+
+		got, err = repo.GetByID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != models.OrderStatusDelivered {
+			t.Errorf("Status = %q, want delivered", got.Status)
+		}
+		// Después de entregado solo se puede reembolsar
+		if got, err = repo.UpdateStatus(ctx, order.ID, models.OrderStatusRefunded); err != nil {
+			t.Fatalf("UpdateStatus to refunded: %v", err)
+		} else if got.Status != models.OrderStatusRefunded {
+			t.Errorf("Status = %q, want refunded", got.Status)
+		}
+
+		_, err = repo.UpdateStatus(ctx, "missing", models.OrderStatusPaid)
+		assertErrorIs(t, "UpdateStatus", err, repository.ErrNotFound)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		repo := newRepo(t)
+		paid := createOrder(t, repo, newOrder("buyer", "seller", "c1", 2))
+		pending := createOrder(t, repo, newOrder("buyer", "seller", "c1", 1))
+
+		if _, err := repo.UpdateStatus(ctx, paid.ID, models.OrderStatusPaid); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		tick()
+		if _, err := repo.UpdateStatus(ctx, paid.ID, models.OrderStatusCancelled); err != nil {
+			t.Fatalf("UpdateStatus to cancelled: %v", err)
+		}
+		if _, err := repo.UpdateStatus(ctx, pending.ID, models.OrderStatusCancelled); err != nil {
+			t.Fatalf("UpdateStatus to cancelled: %v", err)
+		}
+
+		_, err := repo.UpdateStatus(ctx, paid.ID, models.OrderStatusPaid)
+		assertErrorIs(t, "UpdateStatus from cancelled to paid", err, repository.ErrConflict)
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		repo := newRepo(t)
+		first := createOrder(t, repo, newOrder("b1", "s1", "c1", 1))
+		second := createOrder(t, repo, newOrder("b2", "s1", "c2", 1))
+		third := createOrder(t, repo, newOrder("b1", "s2", "c1", 1))
+		if _, err := repo.UpdateStatus(ctx, third.ID, models.OrderStatusPaid); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+
+		list := func(filter repository.OrderFilter) []string {
+			t.Helper()
+			orders, err := repo.List(ctx, filter)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			ids := make([]string, len(orders))
+			for i, order := range orders {
+				ids[i] = order.ID
+			}
+			return ids
+		}
+		assertIDs(t, "List", list(repository.OrderFilter{}), third.ID, second.ID, first.ID)
+		assertIDs(t, "List by buyer", list(repository.OrderFilter{BuyerID: "b1"}), third.ID, first.ID)
+		assertIDs(t, "List by seller", list(repository.OrderFilter{SellerID: "s1"}), second.ID, first.ID)
+		assertIDs(t, "List by cause", list(repository.OrderFilter{CauseID: "c2"}), second.ID)
+		assertIDs(t, "List by buyer and status",
+			list(repository.OrderFilter{BuyerID: "b1", Status: models.OrderStatusPaid}), third.ID)
+
+		page := list(repository.OrderFilter{Limit: 2})
+		assertIDs(t, "List first page", page, third.ID, second.ID)
+		after := &repository.Cursor{CreatedAt: second.CreatedAt, ID: second.ID}
+		assertIDs(t, "List next page", list(repository.OrderFilter{Limit: 2, After: after}), first.ID)
+	})
+
+	t.Run("UpdateStatusConcurrent", func(t *testing.T) {
+		repo := newRepo(t)
+		order := createOrder(t, repo, newOrder("buyer", "seller", "c1", 2))
+
+		// Las notificaciones repetidas de la pasarela pueden llegar a la vez:
+		// la transición se aplica una sola vez
+		const attempts = 5
+		var wg sync.WaitGroup
+		errs := make(chan error, attempts)
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.UpdateStatus(ctx, order.ID, models.OrderStatusPaid)
+				if err != nil && !errors.Is(err, repository.ErrConflict) {
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		got, err := repo.GetByID(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != models.OrderStatusPaid {
+			t.Errorf("Status = %q, want paid", got.Status)
+		}
+	})
+}
+
+// newOrder crea un pedido de quantity unidades de un producto de 25,00 ARS
+// que dona el 15%
+func newOrder(buyerID, sellerID, causeID string, quantity int) *models.Order {
+	return &models.Order{
+		ProductID:          "p1",
+		CauseID:            causeID,
+		SellerID:           sellerID,
+		BuyerID:            buyerID,
+		Quantity:           quantity,
+		UnitPrice:          models.NewMoney(2500, "ARS"),
+		DonationPercentage: 15,
+		PaymentID:          "pay-1",
+	}
+}
+
+// createOrder registra el pedido y espera para que el siguiente tenga otra fecha
+func createOrder(t *testing.T, repo repository.OrderRepository, order *models.Order) *models.Order {
+	t.Helper()
+	if err := repo.Create(context.Background(), order); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	tick()
+	return order
+}
@@ -0,0 +1,378 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// TestPledgeRepository verifica el contrato de repository.PledgeRepository.
+// newRepos debe devolver, en cada llamada, un repositorio de Causas vacío y el
+// repositorio de aportes que comparte su almacenamiento.
+func TestPledgeRepository(t *testing.T, newRepos func(t *testing.T) (repository.CauseRepository, repository.PledgeRepository)) {
+	ctx := context.Background()
+
+	t.Run("GoalRoundTrip", func(t *testing.T) {
+		causes, _ := newRepos(t)
+		deadline := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+		cause := newFundraiser("g1", "Rescate de perros")
+		cause.Deadline = &deadline
+		cause.RaisedAmount = models.NewMoney(999, "USD")
+		cause.BackerCount = 9
+		createCause(t, causes, cause)
+		if cause.RaisedAmount != models.NewMoney(0, "ARS") || cause.BackerCount != 0 {
+			t.Errorf("Create kept RaisedAmount = %+v, BackerCount = %d", cause.RaisedAmount, cause.BackerCount)
+		}
+
+		got, err := causes.GetSummary(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetSummary: %v", err)
+		}
+		if got.GoalAmount != models.NewMoney(500000, "ARS") || got.RaisedAmount != models.NewMoney(0, "ARS") ||
+			got.Deadline == nil {
+			t.Fatalf("GetSummary = %+v", got)
+		}
+		assertTime(t, "Deadline", *got.Deadline, deadline)
+
+		// Sin aportes, la moneda puede cambiar y lo recaudado la sigue
+		got.GoalAmount = models.NewMoney(800000, "USD")
+		got.Deadline = nil
+		if err := causes.Update(ctx, got); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err = causes.GetSummary(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetSummary: %v", err)
+		}
+		if got.GoalAmount != models.NewMoney(800000, "USD") || got.RaisedAmount != models.NewMoney(0, "USD") ||
+			got.Deadline != nil {
+			t.Errorf("GetSummary after Update = %+v", got)
+		}
+	})
+
+	t.Run("CreateUpdatesTotals", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+
+		before := time.Now()
+		first := createPledge(t, pledges, cause.ID, "g2", 10000)
+		if first.ID == "" {
+			t.Fatal("Create did not assign an ID")
+		}
+		assertRecent(t, "CreatedAt", first.CreatedAt, before)
+		createPledge(t, pledges, cause.ID, "g3", 5000)
+		createPledge(t, pledges, cause.ID, "g2", 2500)
+		assertTotals(t, causes, cause.ID, 17500, 2)
+
+		got, err := pledges.GetByID(ctx, cause.ID, first.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.CauseID != cause.ID || got.GuiverID != "g2" || got.Amount != models.NewMoney(10000, "ARS") ||
+			got.Message != "Fuerza" || !got.Anonymous || got.Status != models.PledgeStatusPaid {
+			t.Errorf("GetByID = %+v", got)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, first.CreatedAt)
+
+		_, err = pledges.GetByID(ctx, cause.ID, "missing")
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+		other := createCause(t, causes, newFundraiser("g1", "Huerta comunitaria"))
+		_, err = pledges.GetByID(ctx, other.ID, first.ID)
+		assertErrorIs(t, "GetByID from another cause", err, repository.ErrNotFound)
+	})
+
+	t.Run("CreatePendingNotCounted", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+
+		pledge := &models.Pledge{CauseID: cause.ID, GuiverID: "g2", Amount: models.NewMoney(10000, "ARS"), PaymentID: "pay-1"}
+		if err := pledges.Create(ctx, pledge); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if pledge.Status != models.PledgeStatusPending {
+			t.Errorf("Status = %q, want pending", pledge.Status)
+		}
+		assertTotals(t, causes, cause.ID, 0, 0)
+
+		got, err := pledges.GetByID(ctx, cause.ID, pledge.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != models.PledgeStatusPending || got.PaymentID != "pay-1" {
+			t.Errorf("GetByID = %+v", got)
+		}
+	})
+
+	t.Run("CreateWithID", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+
+		pledge := &models.Pledge{ID: "pledge-1", CauseID: cause.ID, GuiverID: "g2", Amount: models.NewMoney(10000, "ARS")}
+		if err := pledges.Create(ctx, pledge); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if pledge.ID != "pledge-1" {
+			t.Errorf("ID = %q, want pledge-1", pledge.ID)
+		}
+		again := &models.Pledge{ID: "pledge-1", CauseID: cause.ID, GuiverID: "g3", Amount: models.NewMoney(500, "ARS"),
+			Status: models.PledgeStatusPaid}
+		err := pledges.Create(ctx, again)
+		assertErrorIs(t, "Create with a duplicate ID", err, repository.ErrConflict)
+		assertTotals(t, causes, cause.ID, 0, 0)
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+		pending := func(guiverID string, amount int64) *models.Pledge {
+			pledge := &models.Pledge{CauseID: cause.ID, GuiverID: guiverID, Amount: models.NewMoney(amount, "ARS")}
+			if err := pledges.Create(ctx, pledge); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			return pledge
+		}
+		first := pending("g2", 10000)
+		second := pending("g2", 2500)
+		failed := pending("g3", 7000)
+
+		got, err := pledges.UpdateStatus(ctx, cause.ID, first.ID, models.PledgeStatusPaid)
+		if err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		if got.Status != models.PledgeStatusPaid || got.Amount != models.NewMoney(10000, "ARS") {
+			t.Errorf("UpdateStatus = %+v", got)
+		}
+		assertTotals(t, causes, cause.ID, 10000, 1)
+
+		// Repetir el estado no vuelve a contar el aporte
+		if _, err := pledges.UpdateStatus(ctx, cause.ID, first.ID, models.PledgeStatusPaid); err != nil {
+			t.Fatalf("UpdateStatus again: %v", err)
+		}
+		assertTotals(t, causes, cause.ID, 10000, 1)
+
+		if _, err := pledges.UpdateStatus(ctx, cause.ID, second.ID, models.PledgeStatusPaid); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		if _, err := pledges.UpdateStatus(ctx, cause.ID, failed.ID, models.PledgeStatusFailed); err != nil {
+			t.Fatalf("UpdateStatus to failed: %v", err)
+		}
+		assertTotals(t, causes, cause.ID, 12500, 1)
+
+		// g2 sigue siendo aportante mientras le quede un aporte pagado
+		if _, err := pledges.UpdateStatus(ctx, cause.ID, first.ID, models.PledgeStatusRefunded); err != nil {
+			t.Fatalf("UpdateStatus to refunded: %v", err)
+		}
+		assertTotals(t, causes, cause.ID, 2500, 1)
+		if _, err := pledges.UpdateStatus(ctx, cause.ID, second.ID, models.PledgeStatusRefunded); err != nil {
+			t.Fatalf("UpdateStatus to refunded: %v", err)
+		}
+		assertTotals(t, causes, cause.ID, 0, 0)
+
+		_, err = pledges.UpdateStatus(ctx, cause.ID, first.ID, models.PledgeStatusPaid)
+		assertErrorIs(t, "UpdateStatus from refunded to paid", err, repository.ErrConflict)
+		_, err = pledges.UpdateStatus(ctx, cause.ID, failed.ID, models.PledgeStatusPaid)
+		assertErrorIs(t, "UpdateStatus from failed to paid", err, repository.ErrConflict)
+		assertTotals(t, causes, cause.ID, 0, 0)
+
+		got, err = pledges.GetByID(ctx, cause.ID, first.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != models.PledgeStatusRefunded {
+			t.Errorf("Status = %q, want refunded", got.Status)
+		}
+
+		_, err = pledges.UpdateStatus(ctx, cause.ID, "missing", models.PledgeStatusPaid)
+		assertErrorIs(t, "UpdateStatus", err, repository.ErrNotFound)
+	})
+
+	t.Run("CreateMissingCause", func(t *testing.T) {
+		_, pledges := newRepos(t)
+		err := pledges.Create(ctx, &models.Pledge{CauseID: "missing", GuiverID: "g2", Amount: models.NewMoney(100, "ARS")})
+		assertErrorIs(t, "Create", err, repository.ErrNotFound)
+	})
+
+	t.Run("UpdateKeepsTotals", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+		createPledge(t, pledges, cause.ID, "g2", 10000)
+
+		// cause tiene los totales desactualizados: Update no debe pisarlos
+		cause.Title = "Rescate de gatos"
+		if err := causes.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if cause.RaisedAmount != models.NewMoney(10000, "ARS") || cause.BackerCount != 1 {
+			t.Errorf("Update returned RaisedAmount = %+v, BackerCount = %d, want 10000 ARS and 1",
+				cause.RaisedAmount, cause.BackerCount)
+		}
+		assertTotals(t, causes, cause.ID, 10000, 1)
+	})
+
+	t.Run("UpdateCurrencyWithPledges", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+		paid := createPledge(t, pledges, cause.ID, "g2", 10000)
+		pending := &models.Pledge{CauseID: cause.ID, GuiverID: "g3", Amount: models.NewMoney(5000, "ARS")}
+		if err := pledges.Create(ctx, pending); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		// Se rechaza mientras haya algo recaudado o por cobrar en la moneda actual
+		for _, step := range []struct {
+			id     string
+			status models.PledgeStatus
+		}{{paid.ID, models.PledgeStatusRefunded}, {pending.ID, models.PledgeStatusFailed}} {
+			changed := *cause
+			changed.GoalAmount = models.NewMoney(800000, "USD")
+			err := causes.Update(ctx, &changed)
+			assertErrorIs(t, "Update currency with pledges", err, repository.ErrConflict)
+			if got, err := causes.GetSummary(ctx, cause.ID); err != nil {
+				t.Fatalf("GetSummary: %v", err)
+			} else if got.GoalAmount != cause.GoalAmount {
+				t.Fatalf("GoalAmount after rejected Update = %+v", got.GoalAmount)
+			}
+			if _, err := pledges.UpdateStatus(ctx, cause.ID, step.id, step.status); err != nil {
+				t.Fatalf("UpdateStatus to %s: %v", step.status, err)
+			}
+		}
+
+		cause.GoalAmount = models.NewMoney(800000, "USD")
+		if err := causes.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if cause.RaisedAmount != models.NewMoney(0, "USD") {
+			t.Errorf("RaisedAmount = %+v, want 0 USD", cause.RaisedAmount)
+		}
+	})
+
+	t.Run("ListByCauseNewestFirst", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+		other := createCause(t, causes, newFundraiser("g1", "Huerta comunitaria"))
+		var ids []string
+		for i := 1; i <= 5; i++ {
+			ids = append([]string{createPledge(t, pledges, cause.ID, fmt.Sprintf("g%d", i), int64(i)*1000).ID}, ids...)
+		}
+		createPledge(t, pledges, other.ID, "g2", 1000)
+
+		var got []string
+		var after *repository.Cursor
+		for page := 0; page < 5; page++ {
+			list, err := pledges.ListByCause(ctx, cause.ID, repository.PledgeFilter{Limit: 2, After: after})
+			if err != nil {
+				t.Fatalf("ListByCause: %v", err)
+			}
+			if len(list) == 0 {
+				break
+			}
+			for _, pledge := range list {
+				got = append(got, pledge.ID)
+			}
+			last := list[len(list)-1]
+			after = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+		assertIDs(t, "ListByCause", got, ids...)
+	})
+
+	t.Run("ListByCauseMissingCause", func(t *testing.T) {
+		_, pledges := newRepos(t)
+		_, err := pledges.ListByCause(ctx, "missing", repository.PledgeFilter{Limit: 10})
+		assertErrorIs(t, "ListByCause", err, repository.ErrNotFound)
+	})
+
+	t.Run("CreateConcurrent", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+
+		const backers = 5
+		var wg sync.WaitGroup
+		errs := make(chan error, 2*backers)
+		for i := 0; i < backers; i++ {
+			guiverID := fmt.Sprintf("backer-%d", i)
+			// Cada Guiver aporta dos veces a la vez: se suman ambos importes
+			// pero cuenta como un solo aportante
+			for j := 0; j < 2; j++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					pledge := &models.Pledge{CauseID: cause.ID, GuiverID: guiverID, Amount: models.NewMoney(1000, "ARS"),
+						Status: models.PledgeStatusPaid}
+					if err := pledges.Create(ctx, pledge); err != nil {
+						errs <- err
+					}
+				}()
+			}
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("Create: %v", err)
+		}
+		assertTotals(t, causes, cause.ID, 2*backers*1000, backers)
+	})
+
+	t.Run("DeleteCauseWithOpenPledges", func(t *testing.T) {
+		causes, pledges := newRepos(t)
+		cause := createCause(t, causes, newFundraiser("g1", "Rescate de perros"))
+		paid := createPledge(t, pledges, cause.ID, "g2", 10000)
+		pending := &models.Pledge{CauseID: cause.ID, GuiverID: "g3", Amount: models.NewMoney(5000, "ARS")}
+		if err := pledges.Create(ctx, pending); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		// Se rechaza mientras quede un aporte pagado o pendiente
+		for _, step := range []struct {
+			id     string
+			status models.PledgeStatus
+		}{{paid.ID, models.PledgeStatusRefunded}, {pending.ID, models.PledgeStatusFailed}} {
+			err := causes.Delete(ctx, cause.ID)
+			assertErrorIs(t, "Delete with open pledges", err, repository.ErrConflict)
+			if _, err := pledges.UpdateStatus(ctx, cause.ID, step.id, step.status); err != nil {
+				t.Fatalf("UpdateStatus to %s: %v", step.status, err)
+			}
+		}
+
+		if err := causes.Delete(ctx, cause.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		_, err := pledges.GetByID(ctx, cause.ID, paid.ID)
+		assertErrorIs(t, "GetByID after Delete", err, repository.ErrNotFound)
+	})
+}
+
+// newFundraiser crea una Causa con una meta de recaudación
+func newFundraiser(guiverID, title string) *models.Cause {
+	cause := newCause(guiverID, title)
+	cause.GoalAmount = models.NewMoney(500000, "ARS")
+	return cause
+}
+
+// createPledge registra el aporte ya pagado y espera para que el siguiente
+// tenga otra fecha
+func createPledge(t *testing.T, repo repository.PledgeRepository, causeID, guiverID string, amount int64) *models.Pledge {
+	t.Helper()
+	pledge := &models.Pledge{CauseID: causeID, GuiverID: guiverID, Amount: models.NewMoney(amount, "ARS"),
+		Message: "Fuerza", Anonymous: true, Status: models.PledgeStatusPaid}
+	if err := repo.Create(context.Background(), pledge); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	tick()
+	return pledge
+}
+
+func assertTotals(t *testing.T, repo repository.CauseRepository, causeID string, raised int64, backers int) {
+	t.Helper()
+	cause, err := repo.GetSummary(context.Background(), causeID)
+	if err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+	if cause.RaisedAmount != models.NewMoney(raised, "ARS") || cause.BackerCount != backers {
+		t.Errorf("RaisedAmount = %+v, BackerCount = %d, want %d ARS and %d", cause.RaisedAmount, cause.BackerCount, raised, backers)
+	}
+}
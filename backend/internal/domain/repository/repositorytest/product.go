@@ -0,0 +1,535 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// TestProductRepository verifica el contrato de repository.ProductRepository.
+// newRepo debe devolver un repositorio vacío en cada llamada.
+func TestProductRepository(t *testing.T, newRepo func(t *testing.T) repository.ProductRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAssignsIDAndTimestamps", func(t *testing.T) {
+		repo := newRepo(t)
+		before := time.Now()
+		product := newProduct("g1", "c1", "Taza de cerámica", 1500)
+		if err := repo.Create(ctx, product); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if product.ID == "" {
+			t.Fatal("Create did not assign an ID")
+		}
+		assertRecent(t, "CreatedAt", product.CreatedAt, before)
+
+		got, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Title != product.Title || got.Price != product.Price || got.CauseID != product.CauseID ||
+			got.GuiverID != product.GuiverID || got.DonationPercentage != product.DonationPercentage ||
+			got.Status != product.Status || got.ContactInfo != product.ContactInfo {
+			t.Errorf("GetByID = %+v, want %+v", got, product)
+		}
+	})
+
+	t.Run("GetByIDMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetByID(ctx, "missing")
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+	})
+
+	t.Run("GetByCauseAndGuiver", func(t *testing.T) {
+		repo := newRepo(t)
+		a := createProduct(t, repo, newProduct("g1", "c1", "A", 100))
+		b := createProduct(t, repo, newProduct("g2", "c1", "B", 100))
+		c := createProduct(t, repo, newProduct("g1", "c2", "C", 100))
+
+		byCause, err := repo.GetByCauseID(ctx, "c1")
+		if err != nil {
+			t.Fatalf("GetByCauseID: %v", err)
+		}
+		assertIDs(t, "GetByCauseID", productIDs(byCause), b.ID, a.ID)
+
+		byGuiver, err := repo.GetByGuiverID(ctx, "g1")
+		if err != nil {
+			t.Fatalf("GetByGuiverID: %v", err)
+		}
+		assertIDs(t, "GetByGuiverID", productIDs(byGuiver), c.ID, a.ID)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		product := createProduct(t, repo, newProduct("g1", "c1", "Taza", 1500))
+		created := product.CreatedAt
+
+		product.Price = models.NewMoney(1800, "USD")
+		product.Status = "paused"
+		if err := repo.Update(ctx, product, false); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if !product.UpdatedAt.After(created) {
+			t.Errorf("UpdatedAt = %v, want after %v", product.UpdatedAt, created)
+		}
+
+		got, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Price != models.NewMoney(1800, "USD") || got.Status != "paused" {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		product := newProduct("g1", "c1", "Fantasma", 1)
+		product.ID = "missing"
+		err := repo.Update(ctx, product, false)
+		assertErrorIs(t, "Update", err, repository.ErrNotFound)
+	})
+
+	t.Run("UpdateKeepsStock", func(t *testing.T) {
+		repo := newRepo(t)
+		product := newProduct("g1", "c1", "Remera", 5000)
+		product.Variants = []models.ProductVariant{
+			{ID: "s-red", Size: "S", Stock: 1},
+			{ID: "m-red", Size: "M", Stock: 2},
+		}
+		product.SyncStock()
+		createProduct(t, repo, product)
+
+		// Se edita una copia leída antes de que un pedido agote la variante
+		stale, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if _, err := repo.AdjustStock(ctx, product.ID, "s-red", -1); err != nil {
+			t.Fatalf("AdjustStock: %v", err)
+		}
+		stale.Title = "Remera estampada"
+		if err := repo.Update(ctx, stale, false); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		assertStock(t, stale, 2, models.ProductStatusActive)
+
+		got, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Title != "Remera estampada" {
+			t.Errorf("Title = %q, want %q", got.Title, "Remera estampada")
+		}
+		assertStock(t, got, 2, models.ProductStatusActive)
+		if variant, _ := got.Variant("s-red"); variant.Stock != 0 {
+			t.Errorf("variant stock = %d, want 0", variant.Stock)
+		}
+
+		// El estado se decide sobre las existencias guardadas
+		if _, err := repo.AdjustStock(ctx, product.ID, "m-red", -2); err != nil {
+			t.Fatalf("AdjustStock: %v", err)
+		}
+		stale.Status = models.ProductStatusActive
+		if err := repo.Update(ctx, stale, false); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err = repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertStock(t, got, 0, models.ProductStatusSoldOut)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		product := createProduct(t, repo, newProduct("g1", "c1", "Taza", 1500))
+		if err := repo.Delete(ctx, product.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		_, err := repo.GetByID(ctx, product.ID)
+		assertErrorIs(t, "GetByID", err, repository.ErrNotFound)
+		err = repo.Delete(ctx, product.ID)
+		assertErrorIs(t, "Delete", err, repository.ErrNotFound)
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		repo := newRepo(t)
+		cheap := createProduct(t, repo, newProduct("g1", "c1", "Llavero", 300))
+		mid := createProduct(t, repo, newProduct("g2", "c1", "Taza", 1500))
+		expensive := createProduct(t, repo, newProduct("g1", "c2", "Buzo", 9000))
+		dollars := newProduct("g3", "c2", "Gorra", 1200)
+		dollars.Price.Currency = "USD"
+		createProduct(t, repo, dollars)
+
+		tests := []struct {
+			name   string
+			filter repository.ProductFilter
+			want   []string
+		}{
+			{"All", repository.ProductFilter{}, []string{dollars.ID, expensive.ID, mid.ID, cheap.ID}},
+			{"CauseID", repository.ProductFilter{CauseID: "c1"}, []string{mid.ID, cheap.ID}},
+			{"GuiverID", repository.ProductFilter{GuiverID: "g1"}, []string{expensive.ID, cheap.ID}},
+			{"MinPrice", priceFilter(repository.PriceRange{Currency: "ARS", Min: 1500}), []string{expensive.ID, mid.ID}},
+			{"MaxPrice", priceFilter(repository.PriceRange{Currency: "ARS", Max: 1500}), []string{mid.ID, cheap.ID}},
+			{"PriceRange", priceFilter(repository.PriceRange{Currency: "ARS", Min: 1000, Max: 2000}), []string{mid.ID}},
+			{"PriceRangeOtherCurrency", priceFilter(repository.PriceRange{Currency: "USD", Min: 1000, Max: 2000}), []string{dollars.ID}},
+			{"PriceRangeAnyCurrency", priceFilter(
+				repository.PriceRange{Currency: "ARS", Min: 1000, Max: 2000},
+				repository.PriceRange{Currency: "USD", Max: 1500},
+			), []string{dollars.ID, mid.ID}},
+			{"PriceRangeWithCause", repository.ProductFilter{
+				CauseID:     "c2",
+				PriceRanges: []repository.PriceRange{{Currency: "ARS", Min: 1000}, {Currency: "USD", Min: 1000}},
+			}, []string{dollars.ID, expensive.ID}},
+			{"Search", repository.ProductFilter{Search: "taza"}, []string{mid.ID}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.filter.Limit = 10
+				products, err := repo.List(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("List: %v", err)
+				}
+				assertIDs(t, "List", productIDs(products), tt.want...)
+			})
+		}
+	})
+
+	t.Run("ListSearch", func(t *testing.T) {
+		repo := newRepo(t)
+		mug := createProduct(t, repo, newProduct("g1", "c1", "Taza de cerámica", 1500))
+		coffee := newProduct("g1", "c1", "Café de la huerta", 800)
+		coffee.Description = "Se sirve mejor en una taza grande"
+		createProduct(t, repo, coffee)
+
+		tests := []struct {
+			name   string
+			search string
+			want   []string
+		}{
+			{"TitleBeforeDescription", "tazas", []string{mug.ID, coffee.ID}},
+			{"AccentInsensitive", "CAFE", []string{coffee.ID}},
+			{"AccentInDocument", "ceramica", []string{mug.ID}},
+			{"NoMatch", "buzo", nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				filter := repository.ProductFilter{Search: tt.search, Limit: 10}
+				products, err := repo.List(ctx, filter)
+				if err != nil {
+					t.Fatalf("List: %v", err)
+				}
+				assertIDs(t, "List", productIDs(products), tt.want...)
+
+				total, err := repo.Count(ctx, filter)
+				if err != nil {
+					t.Fatalf("Count: %v", err)
+				}
+				if total != int64(len(tt.want)) {
+					t.Errorf("Count = %d, want %d", total, len(tt.want))
+				}
+			})
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo := newRepo(t)
+		createProduct(t, repo, newProduct("g1", "c1", "Llavero", 300))
+		createProduct(t, repo, newProduct("g2", "c1", "Taza", 1500))
+		createProduct(t, repo, newProduct("g1", "c2", "Buzo", 9000))
+
+		tests := []struct {
+			name   string
+			filter repository.ProductFilter
+			want   int64
+		}{
+			{"All", repository.ProductFilter{}, 3},
+			{"CauseID", repository.ProductFilter{CauseID: "c1"}, 2},
+			{"PriceRange", priceFilter(repository.PriceRange{Currency: "ARS", Min: 1000, Max: 2000}), 1},
+			{"IgnoresPagination", repository.ProductFilter{GuiverID: "g1", Limit: 1, Offset: 1}, 2},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				total, err := repo.Count(ctx, tt.filter)
+				if err != nil {
+					t.Fatalf("Count: %v", err)
+				}
+				if total != tt.want {
+					t.Errorf("Count = %d, want %d", total, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("ListPagination", func(t *testing.T) {
+		repo := newRepo(t)
+		var ids []string
+		for _, title := range []string{"A", "B", "C"} {
+			ids = append([]string{createProduct(t, repo, newProduct("g1", "c1", title, 100)).ID}, ids...)
+		}
+
+		products, err := repo.List(ctx, repository.ProductFilter{Limit: 2, Offset: 1})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "List", productIDs(products), ids[1:3]...)
+
+		products, err = repo.List(ctx, repository.ProductFilter{Limit: 2, Offset: 3})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "List", productIDs(products))
+	})
+
+	t.Run("ListAfterCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		var ids []string
+		for _, title := range []string{"A", "B", "C"} {
+			ids = append([]string{createProduct(t, repo, newProduct("g1", "c1", title, 100)).ID}, ids...)
+		}
+		createProduct(t, repo, newProduct("g1", "c2", "Otra causa", 100))
+
+		first, err := repo.List(ctx, repository.ProductFilter{CauseID: "c1", Limit: 2})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "first page", productIDs(first), ids[0:2]...)
+
+		last := first[len(first)-1]
+		second, err := repo.List(ctx, repository.ProductFilter{
+			CauseID: "c1",
+			Limit:   2,
+			After:   &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID},
+		})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		assertIDs(t, "second page", productIDs(second), ids[2:3]...)
+	})
+
+	t.Run("Variants", func(t *testing.T) {
+		repo := newRepo(t)
+		product := newProduct("g1", "c1", "Remera", 5000)
+		product.Variants = []models.ProductVariant{
+			{ID: "s-red", Size: "S", Color: "rojo", Stock: 2},
+			{ID: "m-red", Size: "M", Color: "rojo", Stock: 3},
+		}
+		product.SyncStock()
+		createProduct(t, repo, product)
+
+		got, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertStock(t, got, 5, models.ProductStatusActive)
+		if !reflect.DeepEqual(got.Variants, product.Variants) {
+			t.Errorf("Variants = %+v, want %+v", got.Variants, product.Variants)
+		}
+
+		got.Variants = []models.ProductVariant{{ID: "l-blue", Size: "L", Color: "azul", Stock: 1}}
+		got.SyncStock()
+		if err := repo.Update(ctx, got, true); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		updated, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertStock(t, updated, 1, models.ProductStatusActive)
+		if !reflect.DeepEqual(updated.Variants, got.Variants) {
+			t.Errorf("Variants after Update = %+v, want %+v", updated.Variants, got.Variants)
+		}
+	})
+
+	t.Run("AdjustStock", func(t *testing.T) {
+		repo := newRepo(t)
+		product := newProduct("g1", "c1", "Taza", 1500)
+		stock := 2
+		product.Stock = &stock
+		createProduct(t, repo, product)
+
+		for _, want := range []int{1, 0} {
+			got, err := repo.AdjustStock(ctx, product.ID, "", -1)
+			if err != nil {
+				t.Fatalf("AdjustStock: %v", err)
+			}
+			status := models.ProductStatusActive
+			if want == 0 {
+				status = models.ProductStatusSoldOut
+			}
+			assertStock(t, got, want, status)
+		}
+
+		_, err := repo.AdjustStock(ctx, product.ID, "", -1)
+		assertErrorIs(t, "AdjustStock", err, models.ErrOutOfStock)
+		_, err = repo.AdjustStock(ctx, product.ID, "m-red", 1)
+		assertErrorIs(t, "AdjustStock", err, models.ErrUnknownVariant)
+
+		got, err := repo.AdjustStock(ctx, product.ID, "", 3)
+		if err != nil {
+			t.Fatalf("AdjustStock: %v", err)
+		}
+		assertStock(t, got, 3, models.ProductStatusActive)
+		stored, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertStock(t, stored, 3, models.ProductStatusActive)
+
+		_, err = repo.AdjustStock(ctx, "missing", "", -1)
+		assertErrorIs(t, "AdjustStock", err, repository.ErrNotFound)
+	})
+
+	t.Run("AdjustStockVariant", func(t *testing.T) {
+		repo := newRepo(t)
+		product := newProduct("g1", "c1", "Remera", 5000)
+		product.Variants = []models.ProductVariant{
+			{ID: "s-red", Size: "S", Stock: 1},
+			{ID: "m-red", Size: "M", Stock: 1},
+		}
+		product.SyncStock()
+		createProduct(t, repo, product)
+
+		_, err := repo.AdjustStock(ctx, product.ID, "", -1)
+		assertErrorIs(t, "AdjustStock", err, models.ErrVariantRequired)
+		_, err = repo.AdjustStock(ctx, product.ID, "xl-red", -1)
+		assertErrorIs(t, "AdjustStock", err, models.ErrUnknownVariant)
+
+		got, err := repo.AdjustStock(ctx, product.ID, "s-red", -1)
+		if err != nil {
+			t.Fatalf("AdjustStock: %v", err)
+		}
+		assertStock(t, got, 1, models.ProductStatusActive)
+		_, err = repo.AdjustStock(ctx, product.ID, "s-red", -1)
+		assertErrorIs(t, "AdjustStock", err, models.ErrOutOfStock)
+
+		got, err = repo.AdjustStock(ctx, product.ID, "m-red", -1)
+		if err != nil {
+			t.Fatalf("AdjustStock: %v", err)
+		}
+		assertStock(t, got, 0, models.ProductStatusSoldOut)
+		if variant, _ := got.Variant("m-red"); variant.Stock != 0 {
+			t.Errorf("variant stock = %d, want 0", variant.Stock)
+		}
+	})
+
+	t.Run("AdjustStockUntracked", func(t *testing.T) {
+		repo := newRepo(t)
+		product := createProduct(t, repo, newProduct("g1", "c1", "Taza", 1500))
+
+		got, err := repo.AdjustStock(ctx, product.ID, "", -100)
+		if err != nil {
+			t.Fatalf("AdjustStock: %v", err)
+		}
+		if got.TracksStock() || got.Status != models.ProductStatusActive {
+			t.Errorf("AdjustStock on untracked product = %+v", got)
+		}
+	})
+
+	t.Run("AdjustStockConcurrent", func(t *testing.T) {
+		repo := newRepo(t)
+		product := newProduct("g1", "c1", "Taza", 1500)
+		stock := 3
+		product.Stock = &stock
+		createProduct(t, repo, product)
+
+		const buyers = 8
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		sold := 0
+		errs := make(chan error, buyers)
+		for i := 0; i < buyers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Los conflictos por concurrencia se reintentan como lo haría el cliente
+				for {
+					_, err := repo.AdjustStock(ctx, product.ID, "", -1)
+					if errors.Is(err, repository.ErrConflict) {
+						continue
+					}
+					if err == nil {
+						mu.Lock()
+						sold++
+						mu.Unlock()
+					} else if !errors.Is(err, models.ErrOutOfStock) {
+						errs <- err
+					}
+					return
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("AdjustStock: %v", err)
+		}
+
+		if sold != stock {
+			t.Errorf("sold %d units, want %d", sold, stock)
+		}
+		got, err := repo.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertStock(t, got, 0, models.ProductStatusSoldOut)
+	})
+}
+
+// assertStock verifica las existencias y el estado de un Producto
+func assertStock(t *testing.T, product *models.Product, stock int, status models.ProductStatus) {
+	t.Helper()
+	if product.Stock == nil || *product.Stock != stock || product.Status != status {
+		got := "untracked"
+		if product.Stock != nil {
+			got = fmt.Sprint(*product.Stock)
+		}
+		t.Errorf("stock = %s, status = %s; want %d, %s", got, product.Status, stock, status)
+	}
+}
+
+// newProduct crea un Producto con precio en unidades menores de ARS
+func newProduct(guiverID, causeID, title string, price int64) *models.Product {
+	return &models.Product{
+		GuiverID:           guiverID,
+		CauseID:            causeID,
+		Title:              title,
+		Description:        title + " hecho a mano",
+		ImageURLs:          []string{"https://img/product.jpg"},
+		Price:              models.NewMoney(price, "ARS"),
+		DonationPercentage: 20,
+		Status:             models.ProductStatusActive,
+		ContactInfo:        models.ContactInfo{Instagram: "@tienda"},
+	}
+}
+
+// priceFilter filtra los Productos cuyo precio cae en alguno de los rangos
+func priceFilter(ranges ...repository.PriceRange) repository.ProductFilter {
+	return repository.ProductFilter{PriceRanges: ranges}
+}
+
+// createProduct crea el Producto y espera para que el siguiente tenga otra fecha
+func createProduct(t *testing.T, repo repository.ProductRepository, product *models.Product) *models.Product {
+	t.Helper()
+	if err := repo.Create(context.Background(), product); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	tick()
+	return product
+}
+
+func productIDs(products []*models.Product) []string {
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	return ids
+}
@@ -0,0 +1,68 @@
+// Package repositorytest contiene una batería de pruebas de conformidad que
+// cualquier implementación de los repositorios del dominio debe superar.
+//
+// Cada backend la ejecuta desde sus propias pruebas pasando una función que
+// crea un repositorio vacío:
+//
+//	func TestCauseRepository(t *testing.T) {
+//		repositorytest.TestCauseRepository(t, func(t *testing.T) repository.CauseRepository {
+//			return memory.NewCauseRepository()
+//		})
+//	}
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// timeTolerance absorbe la pérdida de precisión de los motores que no
+// guardan nanosegundos
+const timeTolerance = time.Millisecond
+
+// tick espera lo suficiente para que dos escrituras consecutivas tengan
+// marcas de tiempo distintas en cualquier backend
+func tick() {
+	time.Sleep(2 * time.Millisecond)
+}
+
+func assertTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	diff := got.Sub(want)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > timeTolerance {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func assertRecent(t *testing.T, name string, got, before time.Time) {
+	t.Helper()
+	if got.IsZero() {
+		t.Fatalf("%s was not set", name)
+	}
+	if got.Before(before.Add(-timeTolerance)) || got.After(time.Now().Add(timeTolerance)) {
+		t.Errorf("%s = %v, want a time after %v", name, got, before)
+	}
+}
+
+func assertIDs(t *testing.T, name string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func assertErrorIs(t *testing.T, op string, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("%s error = %v, want %v", op, err, target)
+	}
+}
@@ -0,0 +1,106 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// TestRoleRepository verifica el contrato de repository.RoleRepository.
+// newRepo debe devolver un repositorio vacío en cada llamada.
+func TestRoleRepository(t *testing.T, newRepo func(t *testing.T) repository.RoleRepository) {
+	ctx := context.Background()
+
+	assertRoles := func(t *testing.T, repo repository.RoleRepository, guiverID string, want ...models.Role) {
+		t.Helper()
+		got, err := repo.GetRoles(ctx, guiverID)
+		if err != nil {
+			t.Fatalf("GetRoles: %v", err)
+		}
+		if got == nil || len(got) != len(want) {
+			t.Fatalf("GetRoles(%s) = %v, want %v", guiverID, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("GetRoles(%s) = %v, want %v", guiverID, got, want)
+			}
+		}
+	}
+
+	t.Run("GetRolesWithoutAssignments", func(t *testing.T) {
+		repo := newRepo(t)
+		assertRoles(t, repo, "nobody")
+	})
+
+	t.Run("SetRolesNormalizes", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.SetRoles(ctx, "ana", []models.Role{
+			models.RoleModerator, models.RoleGuiver, models.RoleAdmin, models.RoleModerator,
+		})
+		if err != nil {
+			t.Fatalf("SetRoles: %v", err)
+		}
+		assertRoles(t, repo, "ana", models.RoleAdmin, models.RoleModerator)
+	})
+
+	t.Run("SetRolesReplaces", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.SetRoles(ctx, "ana", []models.Role{models.RoleAdmin, models.RoleOrganizer}); err != nil {
+			t.Fatalf("SetRoles: %v", err)
+		}
+		if err := repo.SetRoles(ctx, "ana", []models.Role{models.RoleModerator}); err != nil {
+			t.Fatalf("SetRoles: %v", err)
+		}
+		assertRoles(t, repo, "ana", models.RoleModerator)
+
+		if err := repo.SetRoles(ctx, "ana", nil); err != nil {
+			t.Fatalf("SetRoles: %v", err)
+		}
+		assertRoles(t, repo, "ana")
+		// Quitar los roles de quien no tiene ninguno no falla
+		if err := repo.SetRoles(ctx, "ana", nil); err != nil {
+			t.Fatalf("SetRoles without roles: %v", err)
+		}
+	})
+
+	t.Run("SetRolesInvalid", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.SetRoles(ctx, "ana", []models.Role{models.RoleOrganizer}); err != nil {
+			t.Fatalf("SetRoles: %v", err)
+		}
+		err := repo.SetRoles(ctx, "ana", []models.Role{models.RoleAdmin, "superuser"})
+		assertErrorIs(t, "SetRoles", err, models.ErrInvalidRole)
+		assertRoles(t, repo, "ana", models.RoleOrganizer)
+	})
+
+	t.Run("ListByRole", func(t *testing.T) {
+		repo := newRepo(t)
+		for guiverID, roles := range map[string][]models.Role{
+			"carla": {models.RoleModerator},
+			"ana":   {models.RoleModerator, models.RoleAdmin},
+			"beto":  {models.RoleOrganizer},
+		} {
+			if err := repo.SetRoles(ctx, guiverID, roles); err != nil {
+				t.Fatalf("SetRoles: %v", err)
+			}
+		}
+
+		for role, want := range map[models.Role][]string{
+			models.RoleModerator: {"ana", "carla"},
+			models.RoleAdmin:     {"ana"},
+			models.RoleOrganizer: {"beto"},
+			models.RoleGuiver:    {},
+		} {
+			got, err := repo.ListByRole(ctx, role)
+			if err != nil {
+				t.Fatalf("ListByRole: %v", err)
+			}
+			if got == nil {
+				t.Fatalf("ListByRole(%s) = nil, want an empty list", role)
+			}
+			assertIDs(t, "ListByRole("+string(role)+")", got, want...)
+		}
+	})
+}
@@ -0,0 +1,428 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// testCauseUpdates verifica las actualizaciones y los colaboradores de
+// repository.CauseRepository
+func testCauseUpdates(t *testing.T, newRepo func(t *testing.T) repository.CauseRepository) {
+	ctx := context.Background()
+
+	t.Run("UpdatesOldestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		first := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+		second := addUpdate(t, repo, cause.ID, "g2", "Llegaron las mantas")
+
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertIDs(t, "Updates", updateIDs(got.Updates), first.ID, second.ID)
+		if got.Updates[1].GuiverID != "g2" {
+			t.Errorf("Updates[1].GuiverID = %q, want g2", got.Updates[1].GuiverID)
+		}
+
+		causes, err := repo.List(ctx, repository.CauseFilter{Limit: 10})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(causes) != 1 {
+			t.Fatalf("List returned %d causes, want 1", len(causes))
+		}
+		// Los listados devuelven resúmenes, sin las actualizaciones
+		if len(causes[0].Updates) != 0 || causes[0].UpdateCount != 2 {
+			t.Errorf("List Updates = %d, UpdateCount = %d, want 0 and 2", len(causes[0].Updates), causes[0].UpdateCount)
+		}
+	})
+
+	t.Run("GetSummary", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+
+		got, err := repo.GetSummary(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetSummary: %v", err)
+		}
+		if got.Title != "Rescate de perros" || len(got.Updates) != 0 || got.UpdateCount != 1 {
+			t.Errorf("GetSummary = %+v", got)
+		}
+
+		_, err = repo.GetSummary(ctx, "missing")
+		assertErrorIs(t, "GetSummary", err, repository.ErrNotFound)
+	})
+
+	t.Run("ListUpdatesNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		other := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		var ids []string
+		for _, content := range []string{"A", "B", "C", "D", "E"} {
+			ids = append([]string{addUpdate(t, repo, cause.ID, "g1", content).ID}, ids...)
+		}
+		addUpdate(t, repo, other.ID, "g1", "Otra causa")
+
+		var got []string
+		var after *repository.Cursor
+		for page := 0; page < 5; page++ {
+			updates, err := repo.ListUpdates(ctx, cause.ID, repository.UpdateFilter{Limit: 2, After: after})
+			if err != nil {
+				t.Fatalf("ListUpdates: %v", err)
+			}
+			if len(updates) == 0 {
+				break
+			}
+			for _, update := range updates {
+				got = append(got, update.ID)
+			}
+			last := updates[len(updates)-1]
+			after = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+		assertIDs(t, "ListUpdates", got, ids...)
+	})
+
+	t.Run("ListUpdatesMissingCause", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.ListUpdates(ctx, "missing", repository.UpdateFilter{Limit: 10})
+		assertErrorIs(t, "ListUpdates", err, repository.ErrNotFound)
+	})
+
+	t.Run("UpdateCount", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		first := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+		addUpdate(t, repo, cause.ID, "g1", "Llegaron las mantas")
+		assertUpdateCount(t, repo, cause.ID, 2)
+
+		if err := repo.DeleteUpdate(ctx, cause.ID, first.ID); err != nil {
+			t.Fatalf("DeleteUpdate: %v", err)
+		}
+		assertUpdateCount(t, repo, cause.ID, 1)
+
+		// cause tiene el contador desactualizado: Update no debe pisarlo
+		cause.Title = "Rescate de gatos"
+		if err := repo.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		assertUpdateCount(t, repo, cause.ID, 1)
+	})
+
+	t.Run("PinUpdate", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		first := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+		second := addUpdate(t, repo, cause.ID, "g1", "Llegaron las mantas")
+
+		if err := repo.PinUpdate(ctx, cause.ID, first.ID); err != nil {
+			t.Fatalf("PinUpdate: %v", err)
+		}
+		assertPinnedUpdate(t, repo, cause.ID, first.ID)
+
+		// Update no debe perder la actualización fijada
+		cause.Title = "Rescate de gatos"
+		if err := repo.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		assertPinnedUpdate(t, repo, cause.ID, first.ID)
+
+		err := repo.PinUpdate(ctx, cause.ID, "missing")
+		assertErrorIs(t, "PinUpdate", err, repository.ErrNotFound)
+		assertPinnedUpdate(t, repo, cause.ID, first.ID)
+
+		if err := repo.PinUpdate(ctx, cause.ID, second.ID); err != nil {
+			t.Fatalf("PinUpdate: %v", err)
+		}
+		assertPinnedUpdate(t, repo, cause.ID, second.ID)
+
+		if err := repo.PinUpdate(ctx, cause.ID, ""); err != nil {
+			t.Fatalf("PinUpdate to unpin: %v", err)
+		}
+		assertPinnedUpdate(t, repo, cause.ID, "")
+
+		// Borrar la actualización fijada la desfija
+		if err := repo.PinUpdate(ctx, cause.ID, first.ID); err != nil {
+			t.Fatalf("PinUpdate: %v", err)
+		}
+		if err := repo.DeleteUpdate(ctx, cause.ID, first.ID); err != nil {
+			t.Fatalf("DeleteUpdate: %v", err)
+		}
+		assertPinnedUpdate(t, repo, cause.ID, "")
+
+		err = repo.PinUpdate(ctx, "missing", second.ID)
+		assertErrorIs(t, "PinUpdate on missing cause", err, repository.ErrNotFound)
+	})
+
+	t.Run("UpdateKeepsUpdates", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		update := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+
+		// cause no tiene la actualización: Update no debe borrarla
+		cause.Title = "Rescate de gatos"
+		if err := repo.Update(ctx, cause); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertIDs(t, "Updates after Update", updateIDs(got.Updates), update.ID)
+	})
+
+	t.Run("GetUpdate", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		update := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+
+		got, err := repo.GetUpdate(ctx, cause.ID, update.ID)
+		if err != nil {
+			t.Fatalf("GetUpdate: %v", err)
+		}
+		if got.GuiverID != "g1" || got.Content != "Conseguimos alimento" || got.EditedAt != nil {
+			t.Errorf("GetUpdate = %+v", got)
+		}
+		assertTime(t, "CreatedAt", got.CreatedAt, update.CreatedAt)
+
+		_, err = repo.GetUpdate(ctx, cause.ID, "missing")
+		assertErrorIs(t, "GetUpdate", err, repository.ErrNotFound)
+		other := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		_, err = repo.GetUpdate(ctx, other.ID, update.ID)
+		assertErrorIs(t, "GetUpdate from another cause", err, repository.ErrNotFound)
+	})
+
+	t.Run("EditUpdate", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		update := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+
+		before := time.Now()
+		edit := &models.Update{ID: update.ID, GuiverID: "intruso", Content: "Conseguimos 20 kg de alimento",
+			ImageURLs: []string{"https://img/2.jpg"}}
+		if err := repo.EditUpdate(ctx, cause.ID, edit); err != nil {
+			t.Fatalf("EditUpdate: %v", err)
+		}
+		// Solo cambian el contenido y las imágenes; el resto se devuelve como está guardado
+		if edit.GuiverID != "g1" || edit.Content != "Conseguimos 20 kg de alimento" {
+			t.Errorf("EditUpdate result = %+v", edit)
+		}
+		if edit.EditedAt == nil {
+			t.Fatal("EditedAt not set")
+		}
+		assertRecent(t, "EditedAt", *edit.EditedAt, before)
+
+		got, err := repo.GetUpdate(ctx, cause.ID, update.ID)
+		if err != nil {
+			t.Fatalf("GetUpdate: %v", err)
+		}
+		if got.Content != "Conseguimos 20 kg de alimento" || got.EditedAt == nil {
+			t.Errorf("GetUpdate after EditUpdate = %+v", got)
+		}
+		assertIDs(t, "ImageURLs", got.ImageURLs, "https://img/2.jpg")
+
+		err = repo.EditUpdate(ctx, cause.ID, &models.Update{ID: "missing", Content: "x"})
+		assertErrorIs(t, "EditUpdate", err, repository.ErrNotFound)
+	})
+
+	t.Run("DeleteUpdate", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		first := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+		second := addUpdate(t, repo, cause.ID, "g1", "Llegaron las mantas")
+
+		if err := repo.DeleteUpdate(ctx, cause.ID, first.ID); err != nil {
+			t.Fatalf("DeleteUpdate: %v", err)
+		}
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertIDs(t, "Updates after DeleteUpdate", updateIDs(got.Updates), second.ID)
+
+		err = repo.DeleteUpdate(ctx, cause.ID, first.ID)
+		assertErrorIs(t, "DeleteUpdate", err, repository.ErrNotFound)
+	})
+
+	t.Run("DeleteRemovesUpdates", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		update := addUpdate(t, repo, cause.ID, "g1", "Conseguimos alimento")
+
+		if err := repo.Delete(ctx, cause.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		_, err := repo.GetUpdate(ctx, cause.ID, update.ID)
+		assertErrorIs(t, "GetUpdate after Delete", err, repository.ErrNotFound)
+	})
+
+	t.Run("Expenses", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := createCause(t, repo, newCause("g1", "Rescate de perros"))
+		other := createCause(t, repo, newCause("g1", "Huerta comunitaria"))
+		date := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+		food := models.Expense{Concept: "Alimento", Category: models.ExpenseCategoryFood, Amount: models.NewMoney(30000, "ARS"),
+			ReceiptURL: "https://img/ticket.jpg", Date: date}
+		vet := models.Expense{Concept: "Vacunas", Category: models.ExpenseCategoryHealth, Amount: models.NewMoney(12000, "ARS"),
+			Date: date}
+
+		update := &models.Update{GuiverID: "g1", Content: "Compras del mes", Expenses: []models.Expense{food, vet}}
+		if err := repo.AddUpdate(ctx, cause.ID, update); err != nil {
+			t.Fatalf("AddUpdate: %v", err)
+		}
+		tick()
+		second := &models.Update{GuiverID: "g1", Content: "Más alimento", Expenses: []models.Expense{
+			{Concept: "Alimento", Category: models.ExpenseCategoryFood, Amount: models.NewMoney(5000, "ARS"), Date: date},
+			{Concept: "Collares", Category: models.ExpenseCategorySupplies, Amount: models.NewMoney(15, "USD"), Date: date},
+		}}
+		if err := repo.AddUpdate(ctx, cause.ID, second); err != nil {
+			t.Fatalf("AddUpdate: %v", err)
+		}
+		otherUpdate := &models.Update{GuiverID: "g1", Content: "Semillas", Expenses: []models.Expense{
+			{Concept: "Semillas", Category: models.ExpenseCategorySupplies, Amount: models.NewMoney(999, "ARS"), Date: date},
+		}}
+		if err := repo.AddUpdate(ctx, other.ID, otherUpdate); err != nil {
+			t.Fatalf("AddUpdate: %v", err)
+		}
+
+		got, err := repo.GetUpdate(ctx, cause.ID, update.ID)
+		if err != nil {
+			t.Fatalf("GetUpdate: %v", err)
+		}
+		if len(got.Expenses) != 2 {
+			t.Fatalf("GetUpdate Expenses = %+v, want 2", got.Expenses)
+		}
+		assertTime(t, "Expenses[0].Date", got.Expenses[0].Date, date)
+		got.Expenses[0].Date = date
+		got.Expenses[1].Date = date
+		if got.Expenses[0] != food || got.Expenses[1] != vet {
+			t.Errorf("GetUpdate Expenses = %+v", got.Expenses)
+		}
+
+		cause, err = repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if len(cause.Updates) != 2 || len(cause.Updates[0].Expenses) != 2 || len(cause.Updates[1].Expenses) != 2 {
+			t.Errorf("GetByID Updates = %+v", cause.Updates)
+		}
+		listed, err := repo.ListUpdates(ctx, cause.ID, repository.UpdateFilter{})
+		if err != nil {
+			t.Fatalf("ListUpdates: %v", err)
+		}
+		if len(listed) != 2 || len(listed[0].Expenses) != 2 || listed[0].Expenses[1].Amount.Currency != "USD" {
+			t.Errorf("ListUpdates = %+v", listed)
+		}
+
+		assertExpenseTotals(t, repo, cause.ID,
+			models.ExpenseTotal{Currency: "ARS", Category: models.ExpenseCategoryFood, Amount: 35000, Count: 2},
+			models.ExpenseTotal{Currency: "ARS", Category: models.ExpenseCategoryHealth, Amount: 12000, Count: 1},
+			models.ExpenseTotal{Currency: "USD", Category: models.ExpenseCategorySupplies, Amount: 15, Count: 1})
+
+		// Editar reemplaza los gastos y borrar la actualización los descuenta
+		edit := &models.Update{ID: update.ID, Content: "Compras del mes", Expenses: []models.Expense{vet}}
+		if err := repo.EditUpdate(ctx, cause.ID, edit); err != nil {
+			t.Fatalf("EditUpdate: %v", err)
+		}
+		if len(edit.Expenses) != 1 || edit.Expenses[0].Concept != "Vacunas" {
+			t.Errorf("EditUpdate Expenses = %+v", edit.Expenses)
+		}
+		if err := repo.DeleteUpdate(ctx, cause.ID, second.ID); err != nil {
+			t.Fatalf("DeleteUpdate: %v", err)
+		}
+		assertExpenseTotals(t, repo, cause.ID,
+			models.ExpenseTotal{Currency: "ARS", Category: models.ExpenseCategoryHealth, Amount: 12000, Count: 1})
+
+		if err := repo.Delete(ctx, cause.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		assertExpenseTotals(t, repo, cause.ID)
+		assertExpenseTotals(t, repo, other.ID,
+			models.ExpenseTotal{Currency: "ARS", Category: models.ExpenseCategorySupplies, Amount: 999, Count: 1})
+	})
+
+	t.Run("Collaborators", func(t *testing.T) {
+		repo := newRepo(t)
+		cause := newCause("g1", "Rescate de perros")
+		cause.Collaborators = []string{"g2"}
+		createCause(t, repo, cause)
+
+		got, err := repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertIDs(t, "Collaborators", got.Collaborators, "g2")
+
+		got.Collaborators = append(got.Collaborators, "g3")
+		if err := repo.Update(ctx, got); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err = repo.GetByID(ctx, cause.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		assertIDs(t, "Collaborators after Update", got.Collaborators, "g2", "g3")
+	})
+}
+
+// addUpdate agrega la actualización y espera para que la siguiente tenga otra fecha
+func addUpdate(t *testing.T, repo repository.CauseRepository, causeID, guiverID, content string) *models.Update {
+	t.Helper()
+	update := &models.Update{GuiverID: guiverID, Content: content}
+	if err := repo.AddUpdate(context.Background(), causeID, update); err != nil {
+		t.Fatalf("AddUpdate: %v", err)
+	}
+	tick()
+	return update
+}
+
+func updateIDs(updates []models.Update) []string {
+	ids := make([]string, len(updates))
+	for i, update := range updates {
+		ids[i] = update.ID
+	}
+	return ids
+}
+
+func assertUpdateCount(t *testing.T, repo repository.CauseRepository, causeID string, want int) {
+	t.Helper()
+	cause, err := repo.GetSummary(context.Background(), causeID)
+	if err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+	if cause.UpdateCount != want {
+		t.Errorf("UpdateCount = %d, want %d", cause.UpdateCount, want)
+	}
+}
+
+func assertPinnedUpdate(t *testing.T, repo repository.CauseRepository, causeID, want string) {
+	t.Helper()
+	cause, err := repo.GetSummary(context.Background(), causeID)
+	if err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+	if cause.PinnedUpdateID != want {
+		t.Errorf("PinnedUpdateID = %q, want %q", cause.PinnedUpdateID, want)
+	}
+}
+
+func assertExpenseTotals(t *testing.T, repo repository.CauseRepository, causeID string, want ...models.ExpenseTotal) {
+	t.Helper()
+	got, err := repo.ExpenseTotals(context.Background(), causeID)
+	if err != nil {
+		t.Fatalf("ExpenseTotals: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ExpenseTotals = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ExpenseTotals[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package exchange define el origen de las cotizaciones entre monedas. Los
+// handlers solo dependen de Source, de modo que reemplazar el archivo estático
+// por un servicio de cotizaciones no requiere modificarlos.
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/guiver/internal/domain/models"
+)
+
+// ErrUnsupportedCurrency indica una moneda sin cotización
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Source obtiene las cotizaciones vigentes
+type Source interface {
+	Rates(ctx context.Context) (*Rates, error)
+}
+
+// Rates son las cotizaciones de cada moneda respecto de Base: cuántas unidades
+// mayores de la moneda vale una unidad mayor de Base. Base cotiza 1 aunque no
+// figure en Rates.
+type Rates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Validate verifica que las monedas estén soportadas y las cotizaciones sean positivas
+func (r *Rates) Validate() error {
+	if !models.IsCurrency(r.Base) {
+		return models.ErrInvalidCurrency
+	}
+	for currency, rate := range r.Rates {
+		if !models.IsCurrency(currency) {
+			return models.ErrInvalidCurrency
+		}
+		if rate <= 0 {
+			return errors.New("exchange rate of " + currency + " must be positive")
+		}
+	}
+	return nil
+}
+
+// rate devuelve la cotización de la moneda respecto de Base
+func (r *Rates) rate(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok
+}
+
+// Currencies devuelve las monedas cotizadas, Base incluida, ordenadas
+func (r *Rates) Currencies() []string {
+	currencies := []string{r.Base}
+	for currency := range r.Rates {
+		if currency != r.Base {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Convert convierte el importe a la moneda to, redondeando a la unidad menor
+// más cercana. Falla con ErrUnsupportedCurrency si alguna de las dos monedas
+// no está cotizada.
+func (r *Rates) Convert(m models.Money, to string) (models.Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	from, ok := r.rate(m.Currency)
+	if !ok {
+		return models.Money{}, ErrUnsupportedCurrency
+	}
+	target, ok := r.rate(to)
+	if !ok {
+		return models.Money{}, ErrUnsupportedCurrency
+	}
+	return models.MoneyFromMajor(m.Major()/from*target, to), nil
+}
@@ -0,0 +1,71 @@
+package exchange
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guiver/internal/domain/models"
+)
+
+func TestRatesConvert(t *testing.T) {
+	rates := &Rates{Base: "USD", Rates: map[string]float64{"ARS": 1000, "CLP": 950, "EUR": 0.9}}
+	tests := []struct {
+		name    string
+		money   models.Money
+		to      string
+		want    models.Money
+		wantErr error
+	}{
+		{"SameCurrency", models.NewMoney(12345, "ARS"), "ARS", models.NewMoney(12345, "ARS"), nil},
+		{"SameUnquotedCurrency", models.NewMoney(5, "MXN"), "MXN", models.NewMoney(5, "MXN"), nil},
+		{"FromBase", models.NewMoney(1050, "USD"), "ARS", models.NewMoney(1050000, "ARS"), nil},
+		{"ToBase", models.NewMoney(1050000, "ARS"), "USD", models.NewMoney(1050, "USD"), nil},
+		{"ToZeroDigits", models.NewMoney(1000, "USD"), "CLP", models.NewMoney(9500, "CLP"), nil},
+		{"FromZeroDigits", models.NewMoney(9500, "CLP"), "USD", models.NewMoney(1000, "USD"), nil},
+		{"BetweenQuoted", models.NewMoney(100000, "ARS"), "EUR", models.NewMoney(90, "EUR"), nil},
+		{"RoundsToNearest", models.NewMoney(1, "ARS"), "USD", models.NewMoney(0, "USD"), nil},
+		{"UnquotedSource", models.NewMoney(100, "MXN"), "USD", models.Money{}, ErrUnsupportedCurrency},
+		{"UnquotedTarget", models.NewMoney(100, "USD"), "MXN", models.Money{}, ErrUnsupportedCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.money, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%+v, %s) = %+v, want %+v", tt.money, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRatesValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates Rates
+		valid bool
+	}{
+		{"Valid", Rates{Base: "USD", Rates: map[string]float64{"ARS": 1000}}, true},
+		{"OnlyBase", Rates{Base: "ARS"}, true},
+		{"UnknownBase", Rates{Base: "XXX"}, false},
+		{"UnknownCurrency", Rates{Base: "USD", Rates: map[string]float64{"XXX": 2}}, false},
+		{"ZeroRate", Rates{Base: "USD", Rates: map[string]float64{"ARS": 0}}, false},
+		{"NegativeRate", Rates{Base: "USD", Rates: map[string]float64{"ARS": -1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rates.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestRatesCurrencies(t *testing.T) {
+	rates := &Rates{Base: "USD", Rates: map[string]float64{"EUR": 0.9, "ARS": 1000, "USD": 1}}
+	if got, want := rates.Currencies(), []string{"ARS", "EUR", "USD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Currencies = %v, want %v", got, want)
+	}
+}
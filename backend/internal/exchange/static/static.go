@@ -0,0 +1,45 @@
+// Package static implementa un origen de cotizaciones fijas leídas de un
+// archivo JSON, para desarrollo y para instalaciones sin servicio de
+// cotizaciones. El archivo tiene la forma de exchange.Rates:
+//
+//	{"base": "USD", "rates": {"ARS": 1000, "MXN": 17.5}}
+package static
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/guiver/internal/exchange"
+)
+
+// Source devuelve siempre las cotizaciones leídas al crearlo
+type Source struct {
+	rates exchange.Rates
+}
+
+// New lee y valida las cotizaciones del archivo path
+func New(path string) (*Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading exchange rates: %v", err)
+	}
+	var rates exchange.Rates
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("error parsing exchange rates: %v", err)
+	}
+	if err := rates.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates in %s: %v", path, err)
+	}
+	return &Source{rates: rates}, nil
+}
+
+// Rates devuelve una copia de las cotizaciones
+func (s *Source) Rates(ctx context.Context) (*exchange.Rates, error) {
+	rates := &exchange.Rates{Base: s.rates.Base, Rates: make(map[string]float64, len(s.rates.Rates))}
+	for currency, rate := range s.rates.Rates {
+		rates.Rates[currency] = rate
+	}
+	return rates, nil
+}
@@ -0,0 +1,153 @@
+// Package fake implementa un proveedor de cuentas en memoria para desarrollo y
+// pruebas, sin servicios externos.
+//
+// Es determinista: las cuentas se numeran en orden de creación
+// (fake_uid_000001) y los tokens de cada inicio de sesión también
+// (fake_id_token_000001). Los ID tokens emitidos se aceptan en el verificador
+// que devuelve Verifier. Los correos de verificación no se envían; si se
+// crea con autoVerify las cuentas nacen verificadas y si no hay que
+// verificarlas con VerifyEmail.
+package fake
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"sync"
+
+	"github.com/guiver/internal/identity"
+	"github.com/guiver/internal/identity/static"
+)
+
+// tokenLifetime es la validez en segundos de los tokens emitidos
+const tokenLifetime = 3600
+
+type account struct {
+	user     identity.User
+	password [sha256.Size]byte
+}
+
+// Provider es el proveedor de cuentas falso
+type Provider struct {
+	mu         sync.Mutex
+	autoVerify bool
+	accounts   map[string]*account // por UID
+	byEmail    map[string]string   // email normalizado -> UID
+	nextUser   int
+	nextToken  int
+	tokens     *static.Verifier
+}
+
+// New crea un proveedor falso. Con autoVerify las cuentas se crean con el
+// email ya verificado.
+func New(autoVerify bool) *Provider {
+	return &Provider{
+		autoVerify: autoVerify,
+		accounts:   make(map[string]*account),
+		byEmail:    make(map[string]string),
+		tokens:     static.New(),
+	}
+}
+
+// CreateUser registra la cuenta
+func (p *Provider) CreateUser(ctx context.Context, user identity.NewUser) (*identity.User, error) {
+	if err := identity.ValidatePassword(user.Password); err != nil {
+		return nil, err
+	}
+	email := identity.NormalizeEmail(user.Email)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.byEmail[email]; ok {
+		return nil, identity.ErrEmailExists
+	}
+	p.nextUser++
+	acc := &account{
+		user: identity.User{
+			UID:           fmt.Sprintf("fake_uid_%06d", p.nextUser),
+			Email:         email,
+			DisplayName:   user.DisplayName,
+			EmailVerified: p.autoVerify,
+		},
+	}
+	acc.password = hashPassword(acc.user.UID, user.Password)
+	p.accounts[acc.user.UID] = acc
+	p.byEmail[email] = acc.user.UID
+
+	created := acc.user
+	return &created, nil
+}
+
+// DeleteUser elimina la cuenta
+func (p *Provider) DeleteUser(ctx context.Context, uid string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	acc, ok := p.accounts[uid]
+	if !ok {
+		return identity.ErrUserNotFound
+	}
+	delete(p.byEmail, acc.user.Email)
+	delete(p.accounts, uid)
+	return nil
+}
+
+// SignIn verifica la contraseña y emite tokens nuevos
+func (p *Provider) SignIn(ctx context.Context, email, password string) (*identity.Session, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	uid, ok := p.byEmail[identity.NormalizeEmail(email)]
+	if !ok {
+		return nil, identity.ErrInvalidCredentials
+	}
+	acc := p.accounts[uid]
+	hash := hashPassword(uid, password)
+	if subtle.ConstantTimeCompare(hash[:], acc.password[:]) != 1 {
+		return nil, identity.ErrInvalidCredentials
+	}
+
+	p.nextToken++
+	idToken := fmt.Sprintf("fake_id_token_%06d", p.nextToken)
+	p.tokens.Add(idToken, identity.Token{
+		UID: uid,
+		Claims: map[string]interface{}{
+			"email":          acc.user.Email,
+			"email_verified": acc.user.EmailVerified,
+			"name":           acc.user.DisplayName,
+		},
+	})
+	return &identity.Session{
+		User:         acc.user,
+		IDToken:      idToken,
+		RefreshToken: fmt.Sprintf("fake_refresh_token_%06d", p.nextToken),
+		ExpiresIn:    tokenLifetime,
+	}, nil
+}
+
+// Verifier devuelve el verificador que acepta los ID tokens emitidos
+func (p *Provider) Verifier() *static.Verifier {
+	return p.tokens
+}
+
+// VerifyEmail marca como verificado el email de una cuenta, como si su dueño
+// hubiera abierto el correo de verificación
+func (p *Provider) VerifyEmail(uid string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	acc, ok := p.accounts[uid]
+	if !ok {
+		return identity.ErrUserNotFound
+	}
+	acc.user.EmailVerified = true
+	return nil
+}
+
+// hashPassword usa el UID como sal para que dos cuentas con la misma
+// contraseña no guarden el mismo hash
+func hashPassword(uid, password string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(uid + ":" + password))
+}
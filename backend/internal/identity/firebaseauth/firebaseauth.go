@@ -0,0 +1,195 @@
+// Package firebaseauth implementa el proveedor de cuentas con Firebase Auth.
+//
+// Las cuentas se crean y eliminan con el SDK de administración. Iniciar sesión
+// y enviar el correo de verificación son operaciones del cliente, así que se
+// hacen con la API REST de Identity Toolkit y la clave web del proyecto.
+package firebaseauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/guiver/internal/identity"
+)
+
+// endpoint es la URL base de la API REST de Identity Toolkit
+const endpoint = "https://identitytoolkit.googleapis.com/v1/accounts:"
+
+// Provider es el proveedor de cuentas de Firebase Auth
+type Provider struct {
+	client *auth.Client
+	apiKey string
+	http   *http.Client
+}
+
+// New crea un proveedor que administra las cuentas con client y usa apiKey,
+// la clave web del proyecto, para las operaciones del cliente
+func New(client *auth.Client, apiKey string) (*Provider, error) {
+	if client == nil {
+		return nil, errors.New("firebase auth is not initialized")
+	}
+	if apiKey == "" {
+		return nil, errors.New("firebase web API key is required")
+	}
+	return &Provider{
+		client: client,
+		apiKey: apiKey,
+		http:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// CreateUser crea la cuenta y envía el correo de verificación. Si el correo no
+// se puede enviar la cuenta se elimina, para que pueda volver a registrarse.
+func (p *Provider) CreateUser(ctx context.Context, user identity.NewUser) (*identity.User, error) {
+	if err := identity.ValidatePassword(user.Password); err != nil {
+		return nil, err
+	}
+
+	params := (&auth.UserToCreate{}).
+		Email(identity.NormalizeEmail(user.Email)).
+		Password(user.Password).
+		EmailVerified(false)
+	if user.DisplayName != "" {
+		params = params.DisplayName(user.DisplayName)
+	}
+	record, err := p.client.CreateUser(ctx, params)
+	if err != nil {
+		if auth.IsEmailAlreadyExists(err) {
+			return nil, identity.ErrEmailExists
+		}
+		return nil, fmt.Errorf("error creating user: %w", err)
+	}
+
+	if err := p.sendVerification(ctx, record.Email, user.Password); err != nil {
+		if delErr := p.client.DeleteUser(ctx, record.UID); delErr != nil {
+			log.Printf("Error deleting user %s after failed verification email: %v", record.UID, delErr)
+		}
+		return nil, fmt.Errorf("error sending verification email: %w", err)
+	}
+
+	return toUser(record), nil
+}
+
+// DeleteUser elimina la cuenta
+func (p *Provider) DeleteUser(ctx context.Context, uid string) error {
+	if err := p.client.DeleteUser(ctx, uid); err != nil {
+		if auth.IsUserNotFound(err) {
+			return identity.ErrUserNotFound
+		}
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+	return nil
+}
+
+// SignIn inicia sesión con email y contraseña
+func (p *Provider) SignIn(ctx context.Context, email, password string) (*identity.Session, error) {
+	var resp struct {
+		LocalID      string `json:"localId"`
+		IDToken      string `json:"idToken"`
+		RefreshToken string `json:"refreshToken"`
+		ExpiresIn    string `json:"expiresIn"`
+	}
+	err := p.call(ctx, "signInWithPassword", map[string]interface{}{
+		"email":             identity.NormalizeEmail(email),
+		"password":          password,
+		"returnSecureToken": true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// La respuesta no indica si el email está verificado
+	record, err := p.client.GetUser(ctx, resp.LocalID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+	expiresIn, _ := strconv.Atoi(resp.ExpiresIn)
+	return &identity.Session{
+		User:         *toUser(record),
+		IDToken:      resp.IDToken,
+		RefreshToken: resp.RefreshToken,
+		ExpiresIn:    expiresIn,
+	}, nil
+}
+
+// sendVerification envía el correo de verificación, que requiere un token de
+// la propia cuenta
+func (p *Provider) sendVerification(ctx context.Context, email, password string) error {
+	session, err := p.SignIn(ctx, email, password)
+	if err != nil {
+		return err
+	}
+	return p.call(ctx, "sendOobCode", map[string]interface{}{
+		"requestType": "VERIFY_EMAIL",
+		"idToken":     session.IDToken,
+	}, nil)
+}
+
+// call invoca un método de la API REST y decodifica la respuesta en out
+func (p *Provider) call(ctx context.Context, method string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+method+"?key="+p.apiKey, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling identity toolkit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
+			return fmt.Errorf("identity toolkit %s failed with status %d", method, resp.StatusCode)
+		}
+		return translateError(method, apiErr.Error.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// translateError convierte los códigos de error de la API REST, que pueden
+// venir seguidos de " : detalle", en los errores de identity
+func translateError(method, message string) error {
+	code := strings.TrimSpace(strings.SplitN(message, ":", 2)[0])
+	switch code {
+	case "EMAIL_NOT_FOUND", "INVALID_PASSWORD", "INVALID_LOGIN_CREDENTIALS", "INVALID_EMAIL", "USER_DISABLED":
+		return identity.ErrInvalidCredentials
+	case "EMAIL_EXISTS":
+		return identity.ErrEmailExists
+	case "WEAK_PASSWORD":
+		return identity.ErrWeakPassword
+	default:
+		return fmt.Errorf("identity toolkit %s failed: %s", method, message)
+	}
+}
+
+func toUser(record *auth.UserRecord) *identity.User {
+	return &identity.User{
+		UID:           record.UID,
+		Email:         record.Email,
+		DisplayName:   record.DisplayName,
+		EmailVerified: record.EmailVerified,
+	}
+}
@@ -0,0 +1,35 @@
+package firebaseauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/guiver/internal/identity"
+)
+
+// Verifier verifica los ID tokens emitidos por Firebase Auth
+type Verifier struct {
+	client *auth.Client
+}
+
+// NewVerifier crea un verificador que usa las claves públicas del proyecto de client
+func NewVerifier(client *auth.Client) (*Verifier, error) {
+	if client == nil {
+		return nil, errors.New("firebase auth is not initialized")
+	}
+	return &Verifier{client: client}, nil
+}
+
+// VerifyToken verifica un ID token
+func (v *Verifier) VerifyToken(ctx context.Context, token string) (*identity.Token, error) {
+	verified, err := v.client.VerifyIDToken(ctx, token)
+	if err != nil {
+		if auth.IsIDTokenInvalid(err) {
+			return nil, identity.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("error verifying ID token: %w", err)
+	}
+	return &identity.Token{UID: verified.UID, Claims: verified.Claims}, nil
+}
@@ -0,0 +1,107 @@
+// Package identity define la interfaz de los proveedores de cuentas de
+// usuario. Los handlers solo dependen de Provider, de modo que pueden usar
+// Firebase Auth en producción y un proveedor en memoria en desarrollo y
+// pruebas sin modificarlos.
+package identity
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// MinPasswordLength es el largo mínimo de una contraseña
+const MinPasswordLength = 8
+
+// Errores comunes a todos los proveedores
+var (
+	ErrEmailExists        = errors.New("email already registered")
+	ErrWeakPassword       = errors.New("password is too weak")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailNotVerified   = errors.New("email not verified")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+// NewUser son los datos para crear una cuenta
+type NewUser struct {
+	Email       string
+	Password    string
+	DisplayName string
+}
+
+// User es una cuenta del proveedor. UID es también el ID de su Guiver.
+type User struct {
+	UID           string `json:"uid"`
+	Email         string `json:"email"`
+	DisplayName   string `json:"displayName"`
+	EmailVerified bool   `json:"emailVerified"`
+}
+
+// Session son los tokens que obtiene una cuenta al iniciar sesión. IDToken se
+// envía en la cabecera Authorization de las rutas protegidas.
+type Session struct {
+	User         User   `json:"user"`
+	IDToken      string `json:"idToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"` // segundos de validez de IDToken
+}
+
+// Provider es un proveedor de cuentas de usuario
+type Provider interface {
+	// CreateUser crea una cuenta con el email sin verificar y envía el correo
+	// de verificación. Falla con ErrEmailExists si el email ya está
+	// registrado y con ErrWeakPassword si la contraseña no es válida.
+	CreateUser(ctx context.Context, user NewUser) (*User, error)
+	// DeleteUser elimina una cuenta (ErrUserNotFound si no existe)
+	DeleteUser(ctx context.Context, uid string) error
+	// SignIn verifica las credenciales y devuelve los tokens de la cuenta.
+	// Falla con ErrInvalidCredentials si no son válidas; no verifica que el
+	// email esté verificado, eso lo decide quien lo llama con User.EmailVerified.
+	SignIn(ctx context.Context, email, password string) (*Session, error)
+}
+
+// Token es un token de sesión verificado. Claims son todos los claims del
+// token, incluidos los propios del proveedor.
+type Token struct {
+	UID    string
+	Claims map[string]interface{}
+}
+
+// Email devuelve el email de la cuenta del token, si lo incluye
+func (t *Token) Email() string {
+	email, _ := t.Claims["email"].(string)
+	return email
+}
+
+// EmailVerified indica si el token declara verificado el email de la cuenta
+func (t *Token) EmailVerified() bool {
+	verified, _ := t.Claims["email_verified"].(bool)
+	return verified
+}
+
+// TokenVerifier verifica los tokens que se envían en la cabecera
+// Authorization de las rutas protegidas
+type TokenVerifier interface {
+	// VerifyToken verifica la firma y la vigencia de un token. Falla con
+	// ErrInvalidToken si no es válido.
+	VerifyToken(ctx context.Context, token string) (*Token, error)
+}
+
+// ValidatePassword verifica que una contraseña tenga al menos
+// MinPasswordLength caracteres, alguna letra y algún número
+func ValidatePassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return ErrWeakPassword
+	}
+	if strings.IndexFunc(password, unicode.IsLetter) < 0 || strings.IndexFunc(password, unicode.IsDigit) < 0 {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+// NormalizeEmail pasa un email a minúsculas y sin espacios alrededor
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,47 @@
+package identity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		password string
+		err      error
+	}{
+		{"secreto123", nil},
+		{"contraseña1", nil},
+		{"abcdefg1", nil},
+		{"abcdef1", ErrWeakPassword},
+		{"", ErrWeakPassword},
+		{"abcdefgh", ErrWeakPassword},
+		{"12345678", ErrWeakPassword},
+		{"ñandú1", ErrWeakPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			if err := ValidatePassword(tt.password); !errors.Is(err, tt.err) {
+				t.Errorf("ValidatePassword(%q) = %v, want %v", tt.password, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"alice@example.com", "alice@example.com"},
+		{"  Alice@Example.COM ", "alice@example.com"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := NormalizeEmail(tt.email); got != tt.want {
+				t.Errorf("NormalizeEmail(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/guiver/internal/identity"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+// claims devuelve claims válidos para uid que vencen en una hora
+func claims(uid string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":            uid,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iss":            "guiver",
+		"aud":            "api",
+		"email_verified": true,
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, c jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, c).SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return token
+}
+
+func TestNewHMAC(t *testing.T) {
+	if _, err := NewHMAC([]byte("short"), Options{}); err == nil {
+		t.Error("NewHMAC accepted a secret shorter than 32 bytes")
+	}
+	if _, err := NewHMAC(secret, Options{}); err != nil {
+		t.Errorf("NewHMAC: %v", err)
+	}
+}
+
+func TestVerifyTokenHMAC(t *testing.T) {
+	v, err := NewHMAC(secret, Options{Issuer: "guiver", Audience: "api"})
+	if err != nil {
+		t.Fatalf("NewHMAC: %v", err)
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		c := claims("alice")
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"Valid", sign(t, jwt.SigningMethodHS256, secret, claims("alice")), nil},
+		{"AudienceList", sign(t, jwt.SigningMethodHS256, secret, with("aud", []string{"web", "api"})), nil},
+		{"WrongSecret", sign(t, jwt.SigningMethodHS256, []byte("fedcba9876543210fedcba9876543210"), claims("alice")), identity.ErrInvalidToken},
+		{"OtherAlgorithm", sign(t, jwt.SigningMethodHS512, secret, claims("alice")), identity.ErrInvalidToken},
+		{"Expired", sign(t, jwt.SigningMethodHS256, secret, with("exp", time.Now().Add(-time.Minute).Unix())), identity.ErrInvalidToken},
+		{"NotYetValid", sign(t, jwt.SigningMethodHS256, secret, with("nbf", time.Now().Add(time.Hour).Unix())), identity.ErrInvalidToken},
+		{"MissingExp", sign(t, jwt.SigningMethodHS256, secret, with("exp", nil)), identity.ErrInvalidToken},
+		{"MissingSub", sign(t, jwt.SigningMethodHS256, secret, with("sub", nil)), identity.ErrInvalidToken},
+		{"WrongIssuer", sign(t, jwt.SigningMethodHS256, secret, with("iss", "other")), identity.ErrInvalidToken},
+		{"MissingIssuer", sign(t, jwt.SigningMethodHS256, secret, with("iss", nil)), identity.ErrInvalidToken},
+		{"WrongAudience", sign(t, jwt.SigningMethodHS256, secret, with("aud", "web")), identity.ErrInvalidToken},
+		{"None", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims("alice")), identity.ErrInvalidToken},
+		{"Malformed", "not-a-jwt", identity.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.VerifyToken(context.Background(), tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("VerifyToken error = %v, want %v", err, tt.err)
+			}
+			if err == nil && (got.UID != "alice" || !got.EmailVerified()) {
+				t.Errorf("VerifyToken = %s (verified %v), want alice (verified)", got.UID, got.EmailVerified())
+			}
+		})
+	}
+}
+
+func TestVerifyTokenOptional(t *testing.T) {
+	v, err := NewHMAC(secret, Options{})
+	if err != nil {
+		t.Fatalf("NewHMAC: %v", err)
+	}
+	c := claims("alice")
+	delete(c, "iss")
+	delete(c, "aud")
+	if _, err := v.VerifyToken(context.Background(), sign(t, jwt.SigningMethodHS256, secret, c)); err != nil {
+		t.Errorf("VerifyToken without issuer and audience: %v", err)
+	}
+}
+
+func TestVerifyTokenRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshalling public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	if _, err := NewRSA([]byte("not a key"), Options{}); err == nil {
+		t.Error("NewRSA accepted an invalid key")
+	}
+	v, err := NewRSA(publicPEM, Options{Issuer: "guiver"})
+	if err != nil {
+		t.Fatalf("NewRSA: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"Valid", sign(t, jwt.SigningMethodRS256, key, claims("alice")), nil},
+		{"OtherKey", sign(t, jwt.SigningMethodRS256, other, claims("alice")), identity.ErrInvalidToken},
+		{"OtherAlgorithm", sign(t, jwt.SigningMethodRS512, key, claims("alice")), identity.ErrInvalidToken},
+		// Un token HS256 firmado con la clave pública como secreto no debe
+		// aceptarse (confusión de algoritmos)
+		{"HMACWithPublicKey", sign(t, jwt.SigningMethodHS256, publicPEM, claims("alice")), identity.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.VerifyToken(context.Background(), tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("VerifyToken error = %v, want %v", err, tt.err)
+			}
+			if err == nil && got.UID != "alice" {
+				t.Errorf("VerifyToken UID = %s, want alice", got.UID)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package static
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guiver/internal/identity"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		tokens  map[string]string // token -> uid esperado
+		wantErr bool
+	}{
+		{"Empty", "", nil, false},
+		{"Pairs", "alice-token=alice, bob-token=bob", map[string]string{"alice-token": "alice", "bob-token": "bob"}, false},
+		{"TrailingComma", "alice-token=alice,", map[string]string{"alice-token": "alice"}, false},
+		{"MissingUID", "alice-token=", nil, true},
+		{"MissingToken", "=alice", nil, true},
+		{"NoSeparator", "alice", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			err := v.Load(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			for token, uid := range tt.tokens {
+				got, err := v.VerifyToken(context.Background(), token)
+				if err != nil {
+					t.Fatalf("VerifyToken(%q): %v", token, err)
+				}
+				if got.UID != uid || got.Claims["sub"] != uid {
+					t.Errorf("VerifyToken(%q) = %s (sub %v), want %s", token, got.UID, got.Claims["sub"], uid)
+				}
+				if !got.EmailVerified() {
+					t.Errorf("VerifyToken(%q) email not verified", token)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	v := New()
+	v.Add("alice-token", identity.Token{UID: "alice", Claims: map[string]interface{}{"email": "alice@example.com"}})
+	v.Add("bob-token", identity.Token{UID: "bob"})
+
+	tests := []struct {
+		name     string
+		token    string
+		uid      string
+		email    string
+		verified bool
+		err      error
+	}{
+		{"WithClaims", "alice-token", "alice", "alice@example.com", false, nil},
+		{"WithoutClaims", "bob-token", "bob", "", false, nil},
+		{"Unknown", "carol-token", "", "", false, identity.ErrInvalidToken},
+		{"Empty", "", "", "", false, identity.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.VerifyToken(context.Background(), tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("VerifyToken(%q) error = %v, want %v", tt.token, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got.UID != tt.uid || got.Email() != tt.email || got.EmailVerified() != tt.verified {
+				t.Errorf("VerifyToken(%q) = %s %q %v, want %s %q %v", tt.token,
+					got.UID, got.Email(), got.EmailVerified(), tt.uid, tt.email, tt.verified)
+			}
+		})
+	}
+
+	// Los claims devueltos son una copia
+	got, _ := v.VerifyToken(context.Background(), "alice-token")
+	got.Claims["email_verified"] = true
+	again, _ := v.VerifyToken(context.Background(), "alice-token")
+	if again.EmailVerified() {
+		t.Error("modifying the returned claims changed the stored token")
+	}
+}
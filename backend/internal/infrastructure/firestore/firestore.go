@@ -2,15 +2,26 @@ package firestore
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"time"
 
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	firebase "firebase.google.com/go/v4"
+	"github.com/guiver/internal/domain/repository"
 	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// Direcciones de ordenación disponibles para OrderByQuery
+const (
+	ASC  = firestore.Asc
+	DESC = firestore.Desc
+)
+
 // Client encapsula el cliente de Firestore
 type Client struct {
 	client *firestore.Client
@@ -30,34 +41,297 @@ func (c *Client) Close() error {
 	return c.client.Close()
 }
 
-// Create crea un nuevo documento
+// Create crea un nuevo documento. Falla con repository.ErrConflict si ya existe.
 func (c *Client) Create(ctx context.Context, collection string, id string, data interface{}) error {
-	_, err := c.client.Collection(collection).Doc(id).Set(ctx, data)
-	return err
+	_, err := c.client.Collection(collection).Doc(id).Create(ctx, data)
+	return translateError(err)
 }
 
 // Get obtiene un documento por ID
 func (c *Client) Get(ctx context.Context, collection, id string, dest interface{}) error {
 	doc, err := c.client.Collection(collection).Doc(id).Get(ctx)
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			return fmt.Errorf("document not found")
+		return translateError(err)
+	}
+	return doc.DataTo(dest)
+}
+
+// Update reemplaza un documento existente. Falla con repository.ErrNotFound si no existe.
+func (c *Client) Update(ctx context.Context, collection, id string, data interface{}) error {
+	return c.Transform(ctx, collection, id, func(decode func(dest interface{}) error) (interface{}, error) {
+		return data, nil
+	})
+}
+
+// Transform reemplaza atómicamente un documento existente por el resultado de
+// fn, que puede decodificar la versión guardada para conservar campos que no
+// deben pisarse. Falla con repository.ErrNotFound si no existe.
+func (c *Client) Transform(ctx context.Context, collection, id string, fn func(decode func(dest interface{}) error) (interface{}, error)) error {
+	ref := c.client.Collection(collection).Doc(id)
+	err := c.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		data, err := fn(doc.DataTo)
+		if err != nil {
+			return err
 		}
+		return tx.Set(ref, data)
+	})
+	return translateError(err)
+}
+
+// Tx es una transacción sobre documentos identificados por su ruta completa
+// ("causes/id/pledges/pid"). Como exige Firestore, todas las lecturas deben
+// hacerse antes que las escrituras.
+type Tx struct {
+	client *firestore.Client
+	tx     *firestore.Transaction
+}
+
+// RunTransaction ejecuta fn dentro de una transacción, que Firestore reintenta
+// si compite con otra escritura
+func (c *Client) RunTransaction(ctx context.Context, fn func(tx *Tx) error) error {
+	err := c.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		return fn(&Tx{client: c.client, tx: tx})
+	})
+	return translateError(err)
+}
+
+// Get decodifica el documento en dest. La transacción falla con
+// repository.ErrNotFound si no existe.
+func (t *Tx) Get(path string, dest interface{}) error {
+	doc, err := t.tx.Get(t.client.Doc(path))
+	if err != nil {
 		return err
 	}
 	return doc.DataTo(dest)
 }
 
-// Update actualiza un documento
-func (c *Client) Update(ctx context.Context, collection, id string, data interface{}) error {
-	_, err := c.client.Collection(collection).Doc(id).Set(ctx, data)
-	return err
+// Query ejecuta una consulta dentro de la transacción. Firestore bloquea los
+// resultados, de modo que la transacción falla si otra agrega o cambia
+// documentos que la consulta incluye.
+func (t *Tx) Query(collection string, queries []Query, dest interface{}) error {
+	q := t.client.Collection(collection).Query
+	for _, query := range queries {
+		q = query.Apply(q)
+	}
+
+	documents, err := t.tx.Documents(q).GetAll()
+	if err != nil {
+		return err
+	}
+	return documentsToSlice(documents, dest)
+}
+
+// Exists indica si existe el documento
+func (t *Tx) Exists(path string) (bool, error) {
+	doc, err := t.tx.Get(t.client.Doc(path))
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return doc.Exists(), nil
 }
 
-// Delete elimina un documento
+// Create crea el documento. La transacción falla con repository.ErrConflict
+// si ya existe.
+func (t *Tx) Create(path string, data interface{}) error {
+	return t.tx.Create(t.client.Doc(path), data)
+}
+
+// Set reemplaza el documento, o lo crea si no existe
+func (t *Tx) Set(path string, data interface{}) error {
+	return t.tx.Set(t.client.Doc(path), data)
+}
+
+// Delete elimina el documento, si existe
+func (t *Tx) Delete(path string) error {
+	return t.tx.Delete(t.client.Doc(path))
+}
+
+// Increment suma a los campos numéricos del documento los valores indicados
+func (t *Tx) Increment(path string, deltas map[string]int64) error {
+	updates := make([]firestore.Update, 0, len(deltas))
+	for field, delta := range deltas {
+		updates = append(updates, firestore.Update{Path: field, Value: firestore.Increment(delta)})
+	}
+	return t.tx.Update(t.client.Doc(path), updates)
+}
+
+// Counter identifica un campo contador de un documento que lleva la cuenta de
+// los documentos de una de sus subcolecciones, por ejemplo los likes de una causa
+type Counter struct {
+	Collection    string
+	ID            string
+	Field         string
+	Subcollection string
+	// Touch es, si se indica, un campo de fecha del documento padre que se
+	// actualiza cada vez que se agrega un miembro
+	Touch string
+}
+
+// AddCounted crea el documento memberID en la subcolección e incrementa el
+// contador en la misma transacción. Si el documento ya existe no cambia nada.
+// Devuelve el valor resultante del contador y si se creó el documento.
+func (c *Client) AddCounted(ctx context.Context, counter Counter, memberID string, data interface{}) (int64, bool, error) {
+	return c.updateCounted(ctx, counter, memberID, func(tx *firestore.Transaction, ref *firestore.DocumentRef, exists bool) (int64, error) {
+		if exists {
+			return 0, nil
+		}
+		return 1, tx.Create(ref, data)
+	})
+}
+
+// RemoveCounted elimina el documento memberID de la subcolección y decrementa
+// el contador en la misma transacción. Si el documento no existe no cambia
+// nada. Devuelve el valor resultante del contador y si se eliminó el documento.
+func (c *Client) RemoveCounted(ctx context.Context, counter Counter, memberID string) (int64, bool, error) {
+	return c.updateCounted(ctx, counter, memberID, func(tx *firestore.Transaction, ref *firestore.DocumentRef, exists bool) (int64, error) {
+		if !exists {
+			return 0, nil
+		}
+		return -1, tx.Delete(ref)
+	})
+}
+
+// RemoveCountedAll elimina los documentos memberIDs de la subcolección y
+// descuenta del contador los que existían, en una sola transacción. Devuelve
+// el valor resultante del contador y cuántos documentos se eliminaron.
+func (c *Client) RemoveCountedAll(ctx context.Context, counter Counter, memberIDs []string) (int64, int, error) {
+	parent := c.client.Collection(counter.Collection).Doc(counter.ID)
+	members := make([]*firestore.DocumentRef, len(memberIDs))
+	for i, id := range memberIDs {
+		members[i] = parent.Collection(counter.Subcollection).Doc(id)
+	}
+
+	var value int64
+	var removed int
+	err := c.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		parentDoc, err := tx.Get(parent)
+		if err != nil {
+			return err
+		}
+		memberDocs, err := tx.GetAll(members)
+		if err != nil {
+			return err
+		}
+
+		value, _ = parentDoc.Data()[counter.Field].(int64)
+		removed = 0
+		for _, doc := range memberDocs {
+			if !doc.Exists() {
+				continue
+			}
+			if err := tx.Delete(doc.Ref); err != nil {
+				return err
+			}
+			removed++
+		}
+		if removed == 0 {
+			return nil
+		}
+		value -= int64(removed)
+		return tx.Update(parent, []firestore.Update{{Path: counter.Field, Value: firestore.Increment(-removed)}})
+	})
+	return value, removed, translateError(err)
+}
+
+// updateCounted lee el documento padre y el miembro, aplica write y ajusta el
+// contador con el delta que devuelve
+func (c *Client) updateCounted(ctx context.Context, counter Counter, memberID string,
+	write func(tx *firestore.Transaction, ref *firestore.DocumentRef, exists bool) (int64, error)) (int64, bool, error) {
+	parent := c.client.Collection(counter.Collection).Doc(counter.ID)
+	member := parent.Collection(counter.Subcollection).Doc(memberID)
+
+	var value int64
+	var changed bool
+	err := c.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		parentDoc, err := tx.Get(parent)
+		if err != nil {
+			return err
+		}
+		memberDoc, err := tx.Get(member)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		value, _ = parentDoc.Data()[counter.Field].(int64)
+		delta, err := write(tx, member, memberDoc != nil && memberDoc.Exists())
+		if err != nil || delta == 0 {
+			changed = false
+			return err
+		}
+		value += delta
+		changed = true
+		updates := []firestore.Update{{Path: counter.Field, Value: firestore.Increment(delta)}}
+		if counter.Touch != "" && delta > 0 {
+			updates = append(updates, firestore.Update{Path: counter.Touch, Value: time.Now()})
+		}
+		return tx.Update(parent, updates)
+	})
+	return value, changed, translateError(err)
+}
+
+// Exists indica, para cada ruta completa de documento ("causes/id/likes/uid"),
+// si el documento existe
+func (c *Client) Exists(ctx context.Context, paths []string) ([]bool, error) {
+	refs := make([]*firestore.DocumentRef, len(paths))
+	for i, path := range paths {
+		refs[i] = c.client.Doc(path)
+	}
+
+	docs, err := c.client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	exists := make([]bool, len(docs))
+	for i, doc := range docs {
+		exists[i] = doc.Exists()
+	}
+	return exists, nil
+}
+
+// DeleteCollection elimina todos los documentos de una colección o
+// subcolección, por lotes
+func (c *Client) DeleteCollection(ctx context.Context, collection string) error {
+	const batchSize = 200
+	ref := c.client.Collection(collection)
+	for {
+		docs, err := ref.Limit(batchSize).Documents(ctx).GetAll()
+		if err != nil {
+			return translateError(err)
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+
+		writer := c.client.BulkWriter(ctx)
+		jobs := make([]*firestore.BulkWriterJob, 0, len(docs))
+		for _, doc := range docs {
+			job, err := writer.Delete(doc.Ref)
+			if err != nil {
+				writer.End()
+				return translateError(err)
+			}
+			jobs = append(jobs, job)
+		}
+		writer.End()
+		for _, job := range jobs {
+			if _, err := job.Results(); err != nil {
+				return translateError(err)
+			}
+		}
+	}
+}
+
+// Delete elimina un documento. Falla con repository.ErrNotFound si no existe.
 func (c *Client) Delete(ctx context.Context, collection, id string) error {
-	_, err := c.client.Collection(collection).Doc(id).Delete(ctx)
-	return err
+	_, err := c.client.Collection(collection).Doc(id).Delete(ctx, firestore.Exists)
+	return translateError(err)
 }
 
 // Query ejecuta una consulta en Firestore
@@ -78,7 +352,7 @@ func (c *Client) Query(ctx context.Context, collection string, queries []Query,
 			break
 		}
 		if err != nil {
-			return err
+			return translateError(err)
 		}
 		documents = append(documents, doc)
 	}
@@ -86,6 +360,53 @@ func (c *Client) Query(ctx context.Context, collection string, queries []Query,
 	return documentsToSlice(documents, dest)
 }
 
+// Count cuenta los documentos que cumplen la consulta usando una agregación,
+// sin leer los documentos
+func (c *Client) Count(ctx context.Context, collection string, queries []Query) (int64, error) {
+	q := c.client.Collection(collection).Query
+
+	for _, query := range queries {
+		q = query.Apply(q)
+	}
+
+	result, err := q.NewAggregationQuery().WithCount("count").Get(ctx)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	value, ok := result["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count result %T", result["count"])
+	}
+	return value.GetIntegerValue(), nil
+}
+
+// translateError convierte los errores de gRPC en los errores del dominio
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr error
+	switch status.Code(err) {
+	case codes.NotFound:
+		domainErr = repository.ErrNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		domainErr = repository.ErrConflict
+	case codes.PermissionDenied, codes.Unauthenticated:
+		domainErr = repository.ErrPermission
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		domainErr = repository.ErrUnavailable
+	default:
+		if errors.Is(err, context.DeadlineExceeded) {
+			domainErr = repository.ErrUnavailable
+		} else {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %v", domainErr, err)
+}
+
 // Query representa una consulta de Firestore
 type Query interface {
 	Apply(q firestore.Query) firestore.Query
@@ -102,6 +423,38 @@ func (w WhereQuery) Apply(q firestore.Query) firestore.Query {
 	return q.Where(w.Field, w.Op, w.Value)
 }
 
+// OrQuery representa una disyunción de condiciones: se cumple si se cumplen
+// todas las condiciones de alguna de las cláusulas
+type OrQuery struct {
+	Clauses [][]WhereQuery
+}
+
+func (o OrQuery) Apply(q firestore.Query) firestore.Query {
+	if len(o.Clauses) == 1 {
+		for _, where := range o.Clauses[0] {
+			q = where.Apply(q)
+		}
+		return q
+	}
+	filters := make([]firestore.EntityFilter, 0, len(o.Clauses))
+	for _, clause := range o.Clauses {
+		filters = append(filters, andFilter(clause))
+	}
+	return q.WhereEntity(firestore.OrFilter{Filters: filters})
+}
+
+// andFilter combina las condiciones de una cláusula de OrQuery
+func andFilter(clause []WhereQuery) firestore.EntityFilter {
+	filters := make([]firestore.EntityFilter, 0, len(clause))
+	for _, where := range clause {
+		filters = append(filters, firestore.PropertyFilter{Path: where.Field, Operator: where.Op, Value: where.Value})
+	}
+	if len(filters) == 1 {
+		return filters[0]
+	}
+	return firestore.AndFilter{Filters: filters}
+}
+
 // OrderByQuery representa una ordenación
 type OrderByQuery struct {
 	Field     string
@@ -129,3 +482,44 @@ type OffsetQuery struct {
 func (o OffsetQuery) Apply(q firestore.Query) firestore.Query {
 	return q.Offset(o.Offset)
 }
+
+// documentsToSlice decodifica los documentos en el slice apuntado por dest
+func documentsToSlice(documents []*firestore.DocumentSnapshot, dest interface{}) error {
+	ptr := reflect.ValueOf(dest)
+	if ptr.Kind() != reflect.Ptr || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be a pointer to a slice")
+	}
+
+	slice := ptr.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(documents))
+	for _, doc := range documents {
+		item := reflect.New(elemType)
+		if err := doc.DataTo(item.Interface()); err != nil {
+			return err
+		}
+		if isPtr {
+			result = reflect.Append(result, item)
+		} else {
+			result = reflect.Append(result, item.Elem())
+		}
+	}
+
+	slice.Set(result)
+	return nil
+}
+
+// StartAfterQuery continúa la consulta después del documento con los valores
+// indicados, en el mismo orden que las cláusulas OrderByQuery
+type StartAfterQuery struct {
+	Values []interface{}
+}
+
+func (s StartAfterQuery) Apply(q firestore.Query) firestore.Query {
+	return q.StartAfter(s.Values...)
+}
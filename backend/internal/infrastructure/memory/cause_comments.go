@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// AddComment agrega un comentario a una Causa
+func (r *CauseRepository) AddComment(ctx context.Context, causeID string, comment *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cause, ok := r.causes[causeID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	comment.ThreadID = ""
+	if comment.ParentID != "" {
+		i := r.findComment(causeID, comment.ParentID)
+		if i < 0 {
+			return repository.ErrNotFound
+		}
+		comment.ThreadID = models.ThreadRoot(&r.comments[causeID][i])
+	}
+
+	comment.ID = uuid.New().String()
+	comment.CreatedAt = time.Now()
+	comment.EditedAt = nil
+
+	stored := cloneComment(comment)
+	stored.Author, stored.Mentioned, stored.Replies = nil, nil, nil
+	r.comments[causeID] = append(r.comments[causeID], *stored)
+	cause.CommentCount++
+	return nil
+}
+
+// GetComment obtiene un comentario de una Causa
+func (r *CauseRepository) GetComment(ctx context.Context, causeID, commentID string) (*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.findComment(causeID, commentID)
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+	return cloneComment(&r.comments[causeID][i]), nil
+}
+
+// ListComments lista los comentarios principales de una Causa, del más nuevo
+// al más antiguo
+func (r *CauseRepository) ListComments(ctx context.Context, causeID string, filter repository.CommentFilter) ([]*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.causes[causeID]; !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	comments := []*models.Comment{}
+	for i := range r.comments[causeID] {
+		comment := &r.comments[causeID][i]
+		if comment.ParentID != "" {
+			continue
+		}
+		if filter.After == nil || afterCursor(comment.CreatedAt, comment.ID, filter.After) {
+			comments = append(comments, cloneComment(comment))
+		}
+	}
+	sortCommentsByNewest(comments)
+	return paginate(comments, 0, filter.Limit), nil
+}
+
+// ListReplies lista las respuestas de los hilos indicados, de la más antigua a
+// la más nueva
+func (r *CauseRepository) ListReplies(ctx context.Context, causeID string, threadIDs []string) ([]*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	threads := make(map[string]bool, len(threadIDs))
+	for _, id := range threadIDs {
+		threads[id] = true
+	}
+
+	replies := []*models.Comment{}
+	for i := range r.comments[causeID] {
+		comment := &r.comments[causeID][i]
+		if comment.ThreadID != "" && threads[comment.ThreadID] {
+			replies = append(replies, cloneComment(comment))
+		}
+	}
+	sortCommentsByOldest(replies)
+	return replies, nil
+}
+
+// UpdateComment cambia el contenido y las menciones de un comentario
+func (r *CauseRepository) UpdateComment(ctx context.Context, causeID string, comment *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.findComment(causeID, comment.ID)
+	if i < 0 {
+		return repository.ErrNotFound
+	}
+
+	stored := &r.comments[causeID][i]
+	editedAt := time.Now()
+	stored.Content = comment.Content
+	stored.Mentions = copyStrings(comment.Mentions)
+	stored.EditedAt = &editedAt
+	*comment = *cloneComment(stored)
+	return nil
+}
+
+// DeleteComment elimina un comentario de una Causa junto con sus respuestas
+func (r *CauseRepository) DeleteComment(ctx context.Context, causeID, commentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findComment(causeID, commentID) < 0 {
+		return repository.ErrNotFound
+	}
+
+	comments := make([]*models.Comment, len(r.comments[causeID]))
+	for i := range r.comments[causeID] {
+		comments[i] = &r.comments[causeID][i]
+	}
+	deleted := map[string]bool{commentID: true}
+	for _, id := range models.Descendants(commentID, comments) {
+		deleted[id] = true
+	}
+
+	kept := []models.Comment{}
+	for _, comment := range r.comments[causeID] {
+		if !deleted[comment.ID] {
+			kept = append(kept, comment)
+		}
+	}
+	r.comments[causeID] = kept
+	r.causes[causeID].CommentCount -= len(deleted)
+	return nil
+}
+
+// findComment devuelve la posición del comentario o -1 si no existe
+func (r *CauseRepository) findComment(causeID, commentID string) int {
+	for i, comment := range r.comments[causeID] {
+		if comment.ID == commentID {
+			return i
+		}
+	}
+	return -1
+}
+
+func cloneComment(comment *models.Comment) *models.Comment {
+	clone := *comment
+	clone.Mentions = copyStrings(comment.Mentions)
+	if comment.EditedAt != nil {
+		editedAt := *comment.EditedAt
+		clone.EditedAt = &editedAt
+	}
+	return &clone
+}
+
+// sortCommentsByNewest ordena los comentarios por fecha de creación descendente
+func sortCommentsByNewest(comments []*models.Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].ID > comments[j].ID
+		}
+		return comments[i].CreatedAt.After(comments[j].CreatedAt)
+	})
+}
+
+// sortCommentsByOldest ordena los comentarios por fecha de creación ascendente
+func sortCommentsByOldest(comments []*models.Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].ID < comments[j].ID
+		}
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+}
@@ -0,0 +1,335 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/search"
+)
+
+// CauseRepository implementa el repositorio de Causas en memoria
+type CauseRepository struct {
+	mu       sync.RWMutex
+	causes   map[string]*models.Cause
+	updates  map[string][]models.Update
+	comments map[string][]models.Comment
+	likes    map[string]map[string]bool
+	pledges  map[string][]models.Pledge
+	index    *search.Index
+}
+
+// NewCauseRepository crea una nueva instancia de CauseRepository
+func NewCauseRepository() *CauseRepository {
+	return &CauseRepository{
+		causes:   make(map[string]*models.Cause),
+		updates:  make(map[string][]models.Update),
+		comments: make(map[string][]models.Comment),
+		likes:    make(map[string]map[string]bool),
+		pledges:  make(map[string][]models.Pledge),
+		index:    search.NewIndex(),
+	}
+}
+
+// Create crea una nueva Causa
+func (r *CauseRepository) Create(ctx context.Context, cause *models.Cause) error {
+	if cause.ID == "" {
+		cause.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	cause.CreatedAt = now
+	cause.UpdatedAt = now
+	cause.Likes = 0
+	cause.CommentCount = 0
+	cause.PinnedUpdateID = ""
+	cause.RaisedAmount = models.NewMoney(0, cause.GoalAmount.Currency)
+	cause.BackerCount = 0
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.causes[cause.ID]; exists {
+		return repository.ErrConflict
+	}
+
+	updates := []models.Update{}
+	for i := range cause.Updates {
+		update := &cause.Updates[i]
+		if update.ID == "" {
+			update.ID = uuid.New().String()
+		}
+		if update.CreatedAt.IsZero() {
+			update.CreatedAt = now
+		}
+		updates = append(updates, *cloneUpdate(update))
+	}
+	r.updates[cause.ID] = updates
+	cause.UpdateCount = len(updates)
+
+	stored := cloneCause(cause)
+	stored.Updates = nil
+	r.causes[cause.ID] = stored
+	r.index.Put(cause.ID, causeDocument(cause))
+	return nil
+}
+
+// GetByID obtiene una Causa por su ID
+func (r *CauseRepository) GetByID(ctx context.Context, id string) (*models.Cause, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cause, ok := r.causes[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return r.clone(cause), nil
+}
+
+// GetSummary obtiene una Causa por su ID, sin sus actualizaciones
+func (r *CauseRepository) GetSummary(ctx context.Context, id string) (*models.Cause, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cause, ok := r.causes[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneCause(cause), nil
+}
+
+// GetByGuiverID obtiene las Causas de un Guiver
+func (r *CauseRepository) GetByGuiverID(ctx context.Context, guiverID string) ([]*models.Cause, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	causes := []*models.Cause{}
+	for _, cause := range r.causes {
+		if cause.GuiverID == guiverID {
+			causes = append(causes, cloneCause(cause))
+		}
+	}
+	sortCausesByNewest(causes)
+	return causes, nil
+}
+
+// Update actualiza una Causa, sin modificar sus contadores ni actualizaciones.
+// Lo recaudado pasa a la moneda de GoalAmount.
+func (r *CauseRepository) Update(ctx context.Context, cause *models.Cause) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.causes[cause.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	// Lo recaudado y lo que se está cobrando están en la moneda actual
+	if stored.GoalAmount.Currency != cause.GoalAmount.Currency &&
+		(!stored.RaisedAmount.IsZero() || r.hasPledges(cause.ID, models.PledgeStatusPending)) {
+		return repository.ErrConflict
+	}
+	cause.Likes = stored.Likes
+	cause.CommentCount = stored.CommentCount
+	cause.UpdateCount = stored.UpdateCount
+	cause.PinnedUpdateID = stored.PinnedUpdateID
+	cause.RaisedAmount = models.NewMoney(stored.RaisedAmount.Amount, cause.GoalAmount.Currency)
+	cause.BackerCount = stored.BackerCount
+	cause.UpdatedAt = time.Now()
+	updated := cloneCause(cause)
+	updated.Updates = nil
+	r.causes[cause.ID] = updated
+	r.index.Put(cause.ID, causeDocument(cause))
+	return nil
+}
+
+// Delete elimina una Causa junto con sus actualizaciones, comentarios, likes
+// y aportes, salvo que tenga aportes abiertos
+func (r *CauseRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.causes[id]; !ok {
+		return repository.ErrNotFound
+	}
+	if r.hasPledges(id, models.OpenPledgeStatuses...) {
+		return repository.ErrConflict
+	}
+	delete(r.causes, id)
+	r.index.Remove(id)
+	delete(r.updates, id)
+	delete(r.comments, id)
+	delete(r.likes, id)
+	delete(r.pledges, id)
+	return nil
+}
+
+// hasPledges indica si la Causa tiene aportes en alguno de los estados
+// indicados. Requiere el bloqueo.
+func (r *CauseRepository) hasPledges(causeID string, statuses ...models.PledgeStatus) bool {
+	for _, pledge := range r.pledges[causeID] {
+		for _, status := range statuses {
+			if pledge.Status == status {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// List lista las Causas según los filtros
+func (r *CauseRepository) List(ctx context.Context, filter repository.CauseFilter) ([]*models.Cause, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scores := searchScores(r.index, filter.Search)
+	causes := []*models.Cause{}
+	for _, cause := range r.causes {
+		if matchesCauseFilter(cause, filter) && matchesSearch(scores, cause.ID) {
+			causes = append(causes, cloneCause(cause))
+		}
+	}
+
+	sortCausesByNewest(causes)
+	if filter.After != nil {
+		page := []*models.Cause{}
+		for _, cause := range causes {
+			if afterCursor(cause.CreatedAt, cause.ID, filter.After) {
+				page = append(page, cause)
+			}
+		}
+		return paginate(page, 0, filter.Limit), nil
+	}
+	// Con búsqueda se ordena por relevancia; con cursor se mantiene el orden
+	// por fecha porque es el único que el cursor puede continuar
+	if scores != nil {
+		sortByScore(causes, scores, func(cause *models.Cause) string { return cause.ID })
+	}
+	return paginate(causes, filter.Offset, filter.Limit), nil
+}
+
+// Count cuenta las Causas que cumplen los filtros, ignorando Limit y Offset
+func (r *CauseRepository) Count(ctx context.Context, filter repository.CauseFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scores := searchScores(r.index, filter.Search)
+	var total int64
+	for _, cause := range r.causes {
+		if matchesCauseFilter(cause, filter) && matchesSearch(scores, cause.ID) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// Like registra el like del Guiver en la Causa, una sola vez por Guiver
+func (r *CauseRepository) Like(ctx context.Context, causeID, guiverID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cause, ok := r.causes[causeID]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+
+	if !r.likes[causeID][guiverID] {
+		if r.likes[causeID] == nil {
+			r.likes[causeID] = make(map[string]bool)
+		}
+		r.likes[causeID][guiverID] = true
+		cause.Likes++
+	}
+	return cause.Likes, nil
+}
+
+// Unlike quita el like del Guiver en la Causa, si existía
+func (r *CauseRepository) Unlike(ctx context.Context, causeID, guiverID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cause, ok := r.causes[causeID]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+
+	if r.likes[causeID][guiverID] {
+		delete(r.likes[causeID], guiverID)
+		cause.Likes--
+	}
+	return cause.Likes, nil
+}
+
+// LikedBy indica cuáles de las Causas indicadas le gustan al Guiver
+func (r *CauseRepository) LikedBy(ctx context.Context, guiverID string, causeIDs []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	liked := make(map[string]bool)
+	for _, id := range causeIDs {
+		if r.likes[id][guiverID] {
+			liked[id] = true
+		}
+	}
+	return liked, nil
+}
+
+// clone copia una Causa guardada junto con sus actualizaciones
+func (r *CauseRepository) clone(cause *models.Cause) *models.Cause {
+	clone := cloneCause(cause)
+	clone.Updates = make([]models.Update, len(r.updates[cause.ID]))
+	for i := range r.updates[cause.ID] {
+		clone.Updates[i] = *cloneUpdate(&r.updates[cause.ID][i])
+	}
+	return clone
+}
+
+// cloneCause copia una Causa para que el llamador no comparta memoria con el almacén
+func cloneCause(cause *models.Cause) *models.Cause {
+	clone := *cause
+	clone.ImageURLs = copyStrings(cause.ImageURLs)
+	clone.Collaborators = copyStrings(cause.Collaborators)
+	if cause.Deadline != nil {
+		deadline := *cause.Deadline
+		clone.Deadline = &deadline
+	}
+	if cause.Updates != nil {
+		clone.Updates = make([]models.Update, len(cause.Updates))
+		for i := range cause.Updates {
+			clone.Updates[i] = *cloneUpdate(&cause.Updates[i])
+		}
+	}
+	return &clone
+}
+
+// matchesCauseFilter indica si la Causa cumple los filtros
+func matchesCauseFilter(cause *models.Cause, filter repository.CauseFilter) bool {
+	if filter.Type != "" && cause.Type != filter.Type {
+		return false
+	}
+	if filter.Status != "" && cause.Status != filter.Status {
+		return false
+	}
+	if filter.Location != "" && cause.Location != filter.Location {
+		return false
+	}
+	return true
+}
+
+// sortCausesByNewest ordena las Causas por fecha de creación descendente
+func sortCausesByNewest(causes []*models.Cause) {
+	sort.SliceStable(causes, func(i, j int) bool {
+		if causes[i].CreatedAt.Equal(causes[j].CreatedAt) {
+			return causes[i].ID > causes[j].ID
+		}
+		return causes[i].CreatedAt.After(causes[j].CreatedAt)
+	})
+}
+
+// causeDocument es el texto de una Causa que se indexa para la búsqueda
+func causeDocument(cause *models.Cause) search.Document {
+	return search.Document{Title: cause.Title, Body: cause.Description}
+}
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// AddUpdate agrega una actualización a una Causa
+func (r *CauseRepository) AddUpdate(ctx context.Context, causeID string, update *models.Update) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cause, ok := r.causes[causeID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	update.ID = uuid.New().String()
+	update.CreatedAt = time.Now()
+	update.EditedAt = nil
+
+	r.updates[causeID] = append(r.updates[causeID], *cloneUpdate(update))
+	cause.UpdateCount++
+	cause.UpdatedAt = update.CreatedAt
+	return nil
+}
+
+// GetUpdate obtiene una actualización de una Causa
+func (r *CauseRepository) GetUpdate(ctx context.Context, causeID, updateID string) (*models.Update, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.findUpdate(causeID, updateID)
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+	return cloneUpdate(&r.updates[causeID][i]), nil
+}
+
+// ListUpdates lista las actualizaciones de una Causa, de la más nueva a la más
+// antigua
+func (r *CauseRepository) ListUpdates(ctx context.Context, causeID string, filter repository.UpdateFilter) ([]*models.Update, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.causes[causeID]; !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	updates := []*models.Update{}
+	for i := range r.updates[causeID] {
+		update := &r.updates[causeID][i]
+		if filter.After == nil || afterCursor(update.CreatedAt, update.ID, filter.After) {
+			updates = append(updates, cloneUpdate(update))
+		}
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		if updates[i].CreatedAt.Equal(updates[j].CreatedAt) {
+			return updates[i].ID > updates[j].ID
+		}
+		return updates[i].CreatedAt.After(updates[j].CreatedAt)
+	})
+	return paginate(updates, 0, filter.Limit), nil
+}
+
+// EditUpdate cambia el contenido, las imágenes y los gastos de una actualización
+func (r *CauseRepository) EditUpdate(ctx context.Context, causeID string, update *models.Update) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.findUpdate(causeID, update.ID)
+	if i < 0 {
+		return repository.ErrNotFound
+	}
+
+	stored := &r.updates[causeID][i]
+	editedAt := time.Now()
+	stored.Content = update.Content
+	stored.ImageURLs = copyStrings(update.ImageURLs)
+	stored.Expenses = copyExpenses(update.Expenses)
+	stored.EditedAt = &editedAt
+	*update = *cloneUpdate(stored)
+	return nil
+}
+
+// DeleteUpdate elimina una actualización de una Causa
+func (r *CauseRepository) DeleteUpdate(ctx context.Context, causeID, updateID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.findUpdate(causeID, updateID)
+	if i < 0 {
+		return repository.ErrNotFound
+	}
+
+	updates := r.updates[causeID]
+	r.updates[causeID] = append(updates[:i:i], updates[i+1:]...)
+
+	cause := r.causes[causeID]
+	cause.UpdateCount--
+	if cause.PinnedUpdateID == updateID {
+		cause.PinnedUpdateID = ""
+	}
+	return nil
+}
+
+// PinUpdate fija una actualización de la Causa o, con updateID vacío, desfija
+// la actual
+func (r *CauseRepository) PinUpdate(ctx context.Context, causeID, updateID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cause, ok := r.causes[causeID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if updateID != "" && r.findUpdate(causeID, updateID) < 0 {
+		return repository.ErrNotFound
+	}
+	cause.PinnedUpdateID = updateID
+	return nil
+}
+
+// ExpenseTotals suma los gastos de las actualizaciones de una Causa por moneda
+// y categoría
+func (r *CauseRepository) ExpenseTotals(ctx context.Context, causeID string) ([]models.ExpenseTotal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := models.ExpenseTotals{}
+	for _, update := range r.updates[causeID] {
+		totals.Add(update.Expenses...)
+	}
+	return totals.List(), nil
+}
+
+// findUpdate devuelve la posición de la actualización o -1 si no existe
+func (r *CauseRepository) findUpdate(causeID, updateID string) int {
+	for i, update := range r.updates[causeID] {
+		if update.ID == updateID {
+			return i
+		}
+	}
+	return -1
+}
+
+func cloneUpdate(update *models.Update) *models.Update {
+	clone := *update
+	clone.ImageURLs = copyStrings(update.ImageURLs)
+	clone.Expenses = copyExpenses(update.Expenses)
+	if update.EditedAt != nil {
+		editedAt := *update.EditedAt
+		clone.EditedAt = &editedAt
+	}
+	return &clone
+}
+
+func copyExpenses(expenses []models.Expense) []models.Expense {
+	if expenses == nil {
+		return nil
+	}
+	return append([]models.Expense(nil), expenses...)
+}
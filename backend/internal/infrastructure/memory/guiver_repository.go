@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// GuiverRepository implementa el repositorio de Guivers en memoria
+type GuiverRepository struct {
+	mu      sync.RWMutex
+	guivers map[string]models.Guiver
+}
+
+// NewGuiverRepository crea una nueva instancia de GuiverRepository
+func NewGuiverRepository() *GuiverRepository {
+	return &GuiverRepository{guivers: make(map[string]models.Guiver)}
+}
+
+// Create crea un nuevo Guiver
+func (r *GuiverRepository) Create(ctx context.Context, guiver *models.Guiver) error {
+	if guiver.ID == "" {
+		guiver.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	guiver.CreatedAt = now
+	guiver.UpdatedAt = now
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.guivers[guiver.ID]; exists {
+		return repository.ErrConflict
+	}
+	r.guivers[guiver.ID] = *guiver
+	return nil
+}
+
+// GetByID obtiene un Guiver por su ID
+func (r *GuiverRepository) GetByID(ctx context.Context, id string) (*models.Guiver, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	guiver, ok := r.guivers[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &guiver, nil
+}
+
+// Update actualiza un Guiver
+func (r *GuiverRepository) Update(ctx context.Context, guiver *models.Guiver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.guivers[guiver.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	guiver.UpdatedAt = time.Now()
+	r.guivers[guiver.ID] = *guiver
+	return nil
+}
+
+// Delete elimina un Guiver
+func (r *GuiverRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.guivers[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.guivers, id)
+	return nil
+}
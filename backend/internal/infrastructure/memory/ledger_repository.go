@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// LedgerRepository implementa el libro de las causas en memoria
+type LedgerRepository struct {
+	mu      sync.RWMutex
+	entries map[string][]models.LedgerEntry // por Causa, en orden de registro
+	ids     map[string]bool
+}
+
+// NewLedgerRepository crea una nueva instancia de LedgerRepository
+func NewLedgerRepository() *LedgerRepository {
+	return &LedgerRepository{
+		entries: make(map[string][]models.LedgerEntry),
+		ids:     make(map[string]bool),
+	}
+}
+
+// Append registra un movimiento
+func (r *LedgerRepository) Append(ctx context.Context, entry *models.LedgerEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	entry.CreatedAt = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ids[entry.ID] {
+		return repository.ErrConflict
+	}
+	if !r.balances(entry.CauseID).Covers(entry) {
+		return repository.ErrInsufficientFunds
+	}
+	stored := *entry
+	stored.Postings = append([]models.Posting(nil), entry.Postings...)
+	r.entries[entry.CauseID] = append(r.entries[entry.CauseID], stored)
+	r.ids[entry.ID] = true
+	return nil
+}
+
+// ListByCause lista los movimientos de una Causa, del más nuevo al más antiguo
+func (r *LedgerRepository) ListByCause(ctx context.Context, causeID string, filter repository.LedgerFilter) ([]*models.LedgerEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return listEntries(r.entries[causeID], filter.After, filter.Limit, func(entry *models.LedgerEntry) bool {
+		return filter.Type == "" || entry.Type == filter.Type
+	}), nil
+}
+
+// ListDonations lista los movimientos de las donaciones de los pedidos, del
+// más nuevo al más antiguo
+func (r *LedgerRepository) ListDonations(ctx context.Context, filter repository.DonationFilter) ([]*models.LedgerEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var all []models.LedgerEntry
+	for causeID, entries := range r.entries {
+		if filter.CauseID == "" || causeID == filter.CauseID {
+			all = append(all, entries...)
+		}
+	}
+	return listEntries(all, filter.After, filter.Limit, func(entry *models.LedgerEntry) bool {
+		return matchesDonation(entry, filter)
+	}), nil
+}
+
+// DonationTotals suma por moneda lo donado por los pedidos que cumplen el
+// filtro, neto de reembolsos
+func (r *LedgerRepository) DonationTotals(ctx context.Context, filter repository.DonationFilter) (map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := make(map[string]int64)
+	for _, entries := range r.entries {
+		for i := range entries {
+			if matchesDonation(&entries[i], filter) {
+				totals[entries[i].Amount.Currency] += entries[i].DonatedAmount()
+			}
+		}
+	}
+	return totals, nil
+}
+
+// listEntries copia los movimientos que cumplen match, del más nuevo al más
+// antiguo y paginados
+func listEntries(stored []models.LedgerEntry, after *repository.Cursor, limit int, match func(*models.LedgerEntry) bool) []*models.LedgerEntry {
+	entries := []*models.LedgerEntry{}
+	for _, entry := range stored {
+		if !match(&entry) {
+			continue
+		}
+		if after == nil || afterCursor(entry.CreatedAt, entry.ID, after) {
+			clone := entry
+			clone.Postings = append([]models.Posting(nil), entry.Postings...)
+			entries = append(entries, &clone)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].ID > entries[j].ID
+		}
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	return paginate(entries, 0, limit)
+}
+
+// Balances calcula los saldos de las cuentas de una Causa
+func (r *LedgerRepository) Balances(ctx context.Context, causeID string) (models.LedgerBalances, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.balances(causeID), nil
+}
+
+// balances calcula los saldos de una Causa. Debe llamarse con el mutex tomado.
+func (r *LedgerRepository) balances(causeID string) models.LedgerBalances {
+	balances := models.LedgerBalances{}
+	for i := range r.entries[causeID] {
+		balances.Add(&r.entries[causeID][i])
+	}
+	return balances
+}
+
+func matchesDonation(entry *models.LedgerEntry, filter repository.DonationFilter) bool {
+	return entry.DonatedAmount() != 0 &&
+		(filter.CauseID == "" || entry.CauseID == filter.CauseID) &&
+		(filter.GuiverID == "" || entry.GuiverID == filter.GuiverID)
+}
@@ -0,0 +1,70 @@
+// Package memory implementa los repositorios del dominio en memoria, sin
+// dependencias externas. Está pensado para desarrollo local y pruebas.
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/search"
+)
+
+// searchScores devuelve la relevancia de cada documento del índice que
+// coincide con la búsqueda, o nil si no hay búsqueda
+func searchScores(index *search.Index, text string) map[string]float64 {
+	q := search.ParseQuery(text)
+	if q.Empty() {
+		return nil
+	}
+	return index.Search(q)
+}
+
+// matchesSearch indica si el elemento coincide con la búsqueda. Sin búsqueda
+// (scores nil) todos coinciden.
+func matchesSearch(scores map[string]float64, id string) bool {
+	if scores == nil {
+		return true
+	}
+	_, ok := scores[id]
+	return ok
+}
+
+// sortByScore ordena por relevancia descendente, conservando el orden previo
+// en los empates
+func sortByScore[T any](items []T, scores map[string]float64, id func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return scores[id(items[i])] > scores[id(items[j])]
+	})
+}
+
+// paginate aplica offset y limit a un slice ya ordenado
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// afterCursor indica si un elemento va después del cursor en el orden
+// fecha de creación descendente, ID descendente
+func afterCursor(createdAt time.Time, id string, cursor *repository.Cursor) bool {
+	if createdAt.Equal(cursor.CreatedAt) {
+		return id < cursor.ID
+	}
+	return createdAt.Before(cursor.CreatedAt)
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// OrderRepository implementa el repositorio de pedidos en memoria
+type OrderRepository struct {
+	mu     sync.RWMutex
+	orders map[string]*models.Order
+}
+
+// NewOrderRepository crea una nueva instancia de OrderRepository
+func NewOrderRepository() *OrderRepository {
+	return &OrderRepository{orders: make(map[string]*models.Order)}
+}
+
+// Create registra un pedido pendiente
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error {
+	if order.ID == "" {
+		order.ID = uuid.New().String()
+	}
+	order.Status = models.OrderStatusPending
+	order.CalculateTotals()
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.ID]; exists {
+		return repository.ErrConflict
+	}
+	stored := *order
+	stored.CheckoutURL = ""
+	r.orders[order.ID] = &stored
+	return nil
+}
+
+// GetByID obtiene un pedido por su ID
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	clone := *order
+	return &clone, nil
+}
+
+// UpdateStatus cambia el estado de un pedido
+func (r *OrderRepository) UpdateStatus(ctx context.Context, id string, status models.OrderStatus) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if order.Status != status {
+		if !order.Status.CanBecome(status) {
+			return nil, repository.ErrConflict
+		}
+		order.Status = status
+		order.UpdatedAt = time.Now()
+	}
+	clone := *order
+	return &clone, nil
+}
+
+// List lista los pedidos según los filtros, del más nuevo al más antiguo
+func (r *OrderRepository) List(ctx context.Context, filter repository.OrderFilter) ([]*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := []*models.Order{}
+	for _, order := range r.orders {
+		if !matchesOrder(order, filter) {
+			continue
+		}
+		if filter.After == nil || afterCursor(order.CreatedAt, order.ID, filter.After) {
+			clone := *order
+			orders = append(orders, &clone)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].ID > orders[j].ID
+		}
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+	return paginate(orders, 0, filter.Limit), nil
+}
+
+func matchesOrder(order *models.Order, filter repository.OrderFilter) bool {
+	return (filter.BuyerID == "" || order.BuyerID == filter.BuyerID) &&
+		(filter.SellerID == "" || order.SellerID == filter.SellerID) &&
+		(filter.CauseID == "" || order.CauseID == filter.CauseID) &&
+		(filter.Status == "" || order.Status == filter.Status)
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+// PledgeRepository implementa el repositorio de aportes en memoria. Guarda
+// los aportes junto con las Causas de causes para actualizar sus totales bajo
+// el mismo bloqueo.
+type PledgeRepository struct {
+	causes *CauseRepository
+}
+
+// NewPledgeRepository crea una nueva instancia de PledgeRepository sobre el
+// repositorio de Causas indicado
+func NewPledgeRepository(causes *CauseRepository) *PledgeRepository {
+	return &PledgeRepository{causes: causes}
+}
+
+// Create registra un aporte y, si ya está pagado, actualiza los totales de la Causa
+func (r *PledgeRepository) Create(ctx context.Context, pledge *models.Pledge) error {
+	r.causes.mu.Lock()
+	defer r.causes.mu.Unlock()
+
+	cause, ok := r.causes.causes[pledge.CauseID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if pledge.ID == "" {
+		pledge.ID = uuid.New().String()
+	} else if r.findPledge(pledge.CauseID, pledge.ID) >= 0 {
+		return repository.ErrConflict
+	}
+	if pledge.Status == "" {
+		pledge.Status = models.PledgeStatusPending
+	}
+	pledge.CreatedAt = time.Now()
+
+	stored := *pledge
+	stored.CheckoutURL = ""
+	r.causes.pledges[pledge.CauseID] = append(r.causes.pledges[pledge.CauseID], stored)
+	if pledge.Status == models.PledgeStatusPaid {
+		r.count(cause, &stored, 1)
+	}
+	return nil
+}
+
+// GetByID obtiene un aporte de una Causa
+func (r *PledgeRepository) GetByID(ctx context.Context, causeID, id string) (*models.Pledge, error) {
+	r.causes.mu.RLock()
+	defer r.causes.mu.RUnlock()
+
+	i := r.findPledge(causeID, id)
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+	clone := r.causes.pledges[causeID][i]
+	return &clone, nil
+}
+
+// UpdateStatus cambia el estado de un aporte y ajusta los totales de la Causa
+func (r *PledgeRepository) UpdateStatus(ctx context.Context, causeID, id string, status models.PledgeStatus) (*models.Pledge, error) {
+	r.causes.mu.Lock()
+	defer r.causes.mu.Unlock()
+
+	i := r.findPledge(causeID, id)
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+	pledge := &r.causes.pledges[causeID][i]
+	if pledge.Status != status {
+		if !pledge.Status.CanBecome(status) {
+			return nil, repository.ErrConflict
+		}
+		pledge.Status = status
+		switch status {
+		case models.PledgeStatusPaid:
+			r.count(r.causes.causes[causeID], pledge, 1)
+		case models.PledgeStatusRefunded:
+			r.count(r.causes.causes[causeID], pledge, -1)
+		}
+	}
+	clone := *pledge
+	return &clone, nil
+}
+
+// ListByCause lista los aportes de una Causa, del más nuevo al más antiguo
+func (r *PledgeRepository) ListByCause(ctx context.Context, causeID string, filter repository.PledgeFilter) ([]*models.Pledge, error) {
+	r.causes.mu.RLock()
+	defer r.causes.mu.RUnlock()
+
+	if _, ok := r.causes.causes[causeID]; !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	pledges := []*models.Pledge{}
+	for _, pledge := range r.causes.pledges[causeID] {
+		if filter.After == nil || afterCursor(pledge.CreatedAt, pledge.ID, filter.After) {
+			clone := pledge
+			pledges = append(pledges, &clone)
+		}
+	}
+	sort.SliceStable(pledges, func(i, j int) bool {
+		if pledges[i].CreatedAt.Equal(pledges[j].CreatedAt) {
+			return pledges[i].ID > pledges[j].ID
+		}
+		return pledges[i].CreatedAt.After(pledges[j].CreatedAt)
+	})
+	return paginate(pledges, 0, filter.Limit), nil
+}
+
+// count suma (sign 1) o resta (sign -1) el aporte en los totales de la Causa.
+// El Guiver cuenta como aportante mientras tenga algún otro aporte pagado.
+func (r *PledgeRepository) count(cause *models.Cause, pledge *models.Pledge, sign int) {
+	cause.RaisedAmount.Amount += int64(sign) * pledge.Amount.Amount
+	for _, other := range r.causes.pledges[cause.ID] {
+		if other.ID != pledge.ID && other.GuiverID == pledge.GuiverID && other.Status == models.PledgeStatusPaid {
+			return
+		}
+	}
+	cause.BackerCount += sign
+}
+
+// findPledge devuelve la posición del aporte en la Causa, o -1 si no existe
+func (r *PledgeRepository) findPledge(causeID, id string) int {
+	for i := range r.causes.pledges[causeID] {
+		if r.causes.pledges[causeID][i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
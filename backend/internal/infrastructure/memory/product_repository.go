@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/search"
+)
+
+// ProductRepository implementa el repositorio de Productos en memoria
+type ProductRepository struct {
+	mu       sync.RWMutex
+	products map[string]*models.Product
+	index    *search.Index
+}
+
+// NewProductRepository crea una nueva instancia de ProductRepository
+func NewProductRepository() *ProductRepository {
+	return &ProductRepository{
+		products: make(map[string]*models.Product),
+		index:    search.NewIndex(),
+	}
+}
+
+// Create crea un nuevo Producto
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	if product.ID == "" {
+		product.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	product.CreatedAt = now
+	product.UpdatedAt = now
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.products[product.ID]; exists {
+		return repository.ErrConflict
+	}
+	r.products[product.ID] = cloneProduct(product)
+	r.index.Put(product.ID, productDocument(product))
+	return nil
+}
+
+// GetByID obtiene un Producto por su ID
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return cloneProduct(product), nil
+}
+
+// GetByCauseID obtiene los Productos asociados a una Causa
+func (r *ProductRepository) GetByCauseID(ctx context.Context, causeID string) ([]*models.Product, error) {
+	return r.List(ctx, repository.ProductFilter{CauseID: causeID})
+}
+
+// GetByGuiverID obtiene los Productos de un Guiver
+func (r *ProductRepository) GetByGuiverID(ctx context.Context, guiverID string) ([]*models.Product, error) {
+	return r.List(ctx, repository.ProductFilter{GuiverID: guiverID})
+}
+
+// Update actualiza un Producto
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product, replaceStock bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[product.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if !replaceStock {
+		kept := cloneProduct(stored)
+		product.Stock, product.Variants = kept.Stock, kept.Variants
+	}
+	product.SyncStock()
+	product.UpdatedAt = time.Now()
+	r.products[product.ID] = cloneProduct(product)
+	r.index.Put(product.ID, productDocument(product))
+	return nil
+}
+
+// AdjustStock suma delta a las existencias del Producto o de su variante
+func (r *ProductRepository) AdjustStock(ctx context.Context, id, variantID string, delta int) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	product := cloneProduct(stored)
+	if err := product.AdjustStock(variantID, delta); err != nil {
+		return nil, err
+	}
+	product.UpdatedAt = time.Now()
+	r.products[id] = product
+	return cloneProduct(product), nil
+}
+
+// Delete elimina un Producto
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.products, id)
+	r.index.Remove(id)
+	return nil
+}
+
+// List lista los Productos según los filtros
+func (r *ProductRepository) List(ctx context.Context, filter repository.ProductFilter) ([]*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scores := searchScores(r.index, filter.Search)
+	products := []*models.Product{}
+	for _, product := range r.products {
+		if matchesProductFilter(product, filter) && matchesSearch(scores, product.ID) {
+			products = append(products, cloneProduct(product))
+		}
+	}
+
+	sort.SliceStable(products, func(i, j int) bool {
+		if products[i].CreatedAt.Equal(products[j].CreatedAt) {
+			return products[i].ID > products[j].ID
+		}
+		return products[i].CreatedAt.After(products[j].CreatedAt)
+	})
+	if filter.After != nil {
+		page := []*models.Product{}
+		for _, product := range products {
+			if afterCursor(product.CreatedAt, product.ID, filter.After) {
+				page = append(page, product)
+			}
+		}
+		return paginate(page, 0, filter.Limit), nil
+	}
+	// Con búsqueda se ordena por relevancia; con cursor se mantiene el orden
+	// por fecha porque es el único que el cursor puede continuar
+	if scores != nil {
+		sortByScore(products, scores, func(product *models.Product) string { return product.ID })
+	}
+	return paginate(products, filter.Offset, filter.Limit), nil
+}
+
+// Count cuenta los Productos que cumplen los filtros, ignorando Limit y Offset
+func (r *ProductRepository) Count(ctx context.Context, filter repository.ProductFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scores := searchScores(r.index, filter.Search)
+	var total int64
+	for _, product := range r.products {
+		if matchesProductFilter(product, filter) && matchesSearch(scores, product.ID) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// matchesProductFilter indica si el Producto cumple los filtros
+func matchesProductFilter(product *models.Product, filter repository.ProductFilter) bool {
+	if filter.CauseID != "" && product.CauseID != filter.CauseID {
+		return false
+	}
+	if filter.GuiverID != "" && product.GuiverID != filter.GuiverID {
+		return false
+	}
+	if len(filter.PriceRanges) == 0 {
+		return true
+	}
+	for _, priceRange := range filter.PriceRanges {
+		if priceRange.Contains(product.Price) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneProduct copia un Producto para que el llamador no comparta memoria con el almacén
+func cloneProduct(product *models.Product) *models.Product {
+	clone := *product
+	clone.ImageURLs = copyStrings(product.ImageURLs)
+	if product.Stock != nil {
+		stock := *product.Stock
+		clone.Stock = &stock
+	}
+	if product.Variants != nil {
+		clone.Variants = append([]models.ProductVariant(nil), product.Variants...)
+	}
+	return &clone
+}
+
+// productDocument es el texto de un Producto que se indexa para la búsqueda
+func productDocument(product *models.Product) search.Document {
+	return search.Document{Title: product.Title, Body: product.Description}
+}
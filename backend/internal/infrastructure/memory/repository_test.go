@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/domain/repository/repositorytest"
+)
+
+func TestGuiverRepository(t *testing.T) {
+	repositorytest.TestGuiverRepository(t, func(t *testing.T) repository.GuiverRepository {
+		return NewGuiverRepository()
+	})
+}
+
+func TestCauseRepository(t *testing.T) {
+	repositorytest.TestCauseRepository(t, func(t *testing.T) repository.CauseRepository {
+		return NewCauseRepository()
+	})
+}
+
+func TestProductRepository(t *testing.T) {
+	repositorytest.TestProductRepository(t, func(t *testing.T) repository.ProductRepository {
+		return NewProductRepository()
+	})
+}
+
+func TestPledgeRepository(t *testing.T) {
+	repositorytest.TestPledgeRepository(t, func(t *testing.T) (repository.CauseRepository, repository.PledgeRepository) {
+		causes := NewCauseRepository()
+		return causes, NewPledgeRepository(causes)
+	})
+}
+
+func TestOrderRepository(t *testing.T) {
+	repositorytest.TestOrderRepository(t, func(t *testing.T) repository.OrderRepository {
+		return NewOrderRepository()
+	})
+}
+
+func TestLedgerRepository(t *testing.T) {
+	repositorytest.TestLedgerRepository(t, func(t *testing.T) repository.LedgerRepository {
+		return NewLedgerRepository()
+	})
+}
+
+func TestRoleRepository(t *testing.T) {
+	repositorytest.TestRoleRepository(t, func(t *testing.T) repository.RoleRepository {
+		return NewRoleRepository()
+	})
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/guiver/internal/domain/models"
+)
+
+// RoleRepository implementa el repositorio de roles en memoria
+type RoleRepository struct {
+	mu    sync.RWMutex
+	roles map[string][]models.Role // por Guiver
+}
+
+// NewRoleRepository crea una nueva instancia de RoleRepository
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{roles: make(map[string][]models.Role)}
+}
+
+// GetRoles devuelve los roles asignados a un Guiver
+func (r *RoleRepository) GetRoles(ctx context.Context, guiverID string) ([]models.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]models.Role{}, r.roles[guiverID]...), nil
+}
+
+// SetRoles reemplaza los roles asignados a un Guiver
+func (r *RoleRepository) SetRoles(ctx context.Context, guiverID string, roles []models.Role) error {
+	roles, err := models.NormalizeRoles(roles)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(roles) == 0 {
+		delete(r.roles, guiverID)
+		return nil
+	}
+	r.roles[guiverID] = roles
+	return nil
+}
+
+// ListByRole lista los Guivers que tienen el rol
+func (r *RoleRepository) ListByRole(ctx context.Context, role models.Role) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := []string{}
+	for guiverID, roles := range r.roles {
+		for _, assigned := range roles {
+			if assigned == role {
+				ids = append(ids, guiverID)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
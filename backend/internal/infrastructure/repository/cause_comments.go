@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/infrastructure/firestore"
+)
+
+// commentsPath es la subcolección de comentarios de una Causa
+func commentsPath(causeID string) string {
+	return causesCollection + "/" + causeID + "/" + commentsCollection
+}
+
+// maxInValues es la cantidad máxima de valores que Firestore acepta en un
+// filtro "in"
+const maxInValues = 30
+
+// commentsCounter es el contador de comentarios de una Causa
+func commentsCounter(causeID string) firestore.Counter {
+	return firestore.Counter{
+		Collection:    causesCollection,
+		ID:            causeID,
+		Field:         "commentCount",
+		Subcollection: commentsCollection,
+	}
+}
+
+// AddComment agrega un comentario a una Causa
+func (r *CauseRepository) AddComment(ctx context.Context, causeID string, comment *models.Comment) error {
+	comment.ThreadID = ""
+	if comment.ParentID != "" {
+		// El padre se verifica fuera de la transacción: una respuesta que
+		// compita con el borrado del hilo queda huérfana y no se muestra
+		parent, err := r.GetComment(ctx, causeID, comment.ParentID)
+		if err != nil {
+			return err
+		}
+		comment.ThreadID = models.ThreadRoot(parent)
+	}
+
+	comment.ID = uuid.New().String()
+	comment.CreatedAt = time.Now()
+	comment.EditedAt = nil
+
+	_, _, err := r.db.AddCounted(ctx, commentsCounter(causeID), comment.ID, comment)
+	return err
+}
+
+// GetComment obtiene un comentario de una Causa
+func (r *CauseRepository) GetComment(ctx context.Context, causeID, commentID string) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.db.Get(ctx, commentsPath(causeID), commentID, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListComments lista los comentarios principales de una Causa, del más nuevo
+// al más antiguo
+func (r *CauseRepository) ListComments(ctx context.Context, causeID string, filter repository.CommentFilter) ([]*models.Comment, error) {
+	// La subcolección vacía no distingue una Causa sin comentarios de una inexistente
+	if _, err := r.GetByID(ctx, causeID); err != nil {
+		return nil, err
+	}
+
+	comments := []*models.Comment{}
+	queries := append([]firestore.Query{
+		firestore.WhereQuery{Field: "parentId", Op: "==", Value: ""},
+	}, pageQueries(filter.After, filter.Limit, 0)...)
+	if err := r.db.Query(ctx, commentsPath(causeID), queries, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ListReplies lista las respuestas de los hilos indicados, de la más antigua a
+// la más nueva
+func (r *CauseRepository) ListReplies(ctx context.Context, causeID string, threadIDs []string) ([]*models.Comment, error) {
+	replies := []*models.Comment{}
+	for start := 0; start < len(threadIDs); start += maxInValues {
+		end := start + maxInValues
+		if end > len(threadIDs) {
+			end = len(threadIDs)
+		}
+
+		var chunk []*models.Comment
+		queries := []firestore.Query{
+			firestore.WhereQuery{Field: "threadId", Op: "in", Value: threadIDs[start:end]},
+		}
+		if err := r.db.Query(ctx, commentsPath(causeID), queries, &chunk); err != nil {
+			return nil, err
+		}
+		replies = append(replies, chunk...)
+	}
+
+	sort.SliceStable(replies, func(i, j int) bool {
+		if replies[i].CreatedAt.Equal(replies[j].CreatedAt) {
+			return replies[i].ID < replies[j].ID
+		}
+		return replies[i].CreatedAt.Before(replies[j].CreatedAt)
+	})
+	return replies, nil
+}
+
+// UpdateComment cambia el contenido y las menciones de un comentario
+func (r *CauseRepository) UpdateComment(ctx context.Context, causeID string, comment *models.Comment) error {
+	return r.db.Transform(ctx, commentsPath(causeID), comment.ID, func(decode func(dest interface{}) error) (interface{}, error) {
+		var stored models.Comment
+		if err := decode(&stored); err != nil {
+			return nil, err
+		}
+		editedAt := time.Now()
+		stored.Content = comment.Content
+		stored.Mentions = comment.Mentions
+		stored.EditedAt = &editedAt
+		*comment = stored
+		return &stored, nil
+	})
+}
+
+// DeleteComment elimina un comentario de una Causa junto con sus respuestas
+func (r *CauseRepository) DeleteComment(ctx context.Context, causeID, commentID string) error {
+	comment, err := r.GetComment(ctx, causeID, commentID)
+	if err != nil {
+		return err
+	}
+	thread, err := r.ListReplies(ctx, causeID, []string{models.ThreadRoot(comment)})
+	if err != nil {
+		return err
+	}
+
+	ids := append([]string{commentID}, models.Descendants(commentID, thread)...)
+	_, removed, err := r.db.RemoveCountedAll(ctx, commentsCounter(causeID), ids)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
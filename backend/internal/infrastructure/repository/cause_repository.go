@@ -8,12 +8,16 @@ import (
 	"github.com/guiver/internal/domain/models"
 	"github.com/guiver/internal/domain/repository"
 	"github.com/guiver/internal/infrastructure/firestore"
+	"github.com/guiver/internal/search"
 )
 
 const (
 	causesCollection   = "causes"
 	updatesCollection  = "updates"
 	commentsCollection = "comments"
+	likesCollection    = "likes"
+	pledgesCollection  = "pledges"
+	backersCollection  = "backers"
 )
 
 // CauseRepository implementa el repositorio de Causas usando Firestore
@@ -36,12 +40,44 @@ func (r *CauseRepository) Create(ctx context.Context, cause *models.Cause) error
 	cause.CreatedAt = now
 	cause.UpdatedAt = now
 	cause.Likes = 0
+	cause.CommentCount = 0
+	cause.UpdateCount = len(cause.Updates)
+	cause.PinnedUpdateID = ""
+	cause.RaisedAmount = models.NewMoney(0, cause.GoalAmount.Currency)
+	cause.BackerCount = 0
 
-	return r.db.Create(ctx, causesCollection, cause.ID, cause)
+	if err := r.db.Create(ctx, causesCollection, cause.ID, newCauseDocument(cause)); err != nil {
+		return err
+	}
+	for i := range cause.Updates {
+		update := &cause.Updates[i]
+		if update.ID == "" {
+			update.ID = uuid.New().String()
+		}
+		if update.CreatedAt.IsZero() {
+			update.CreatedAt = now
+		}
+		if err := r.db.Create(ctx, updatesPath(cause.ID), update.ID, update); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// GetByID obtiene una Causa por su ID
+// GetByID obtiene una Causa por su ID con sus actualizaciones
 func (r *CauseRepository) GetByID(ctx context.Context, id string) (*models.Cause, error) {
+	cause, err := r.GetSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadUpdates(ctx, cause); err != nil {
+		return nil, err
+	}
+	return cause, nil
+}
+
+// GetSummary obtiene una Causa por su ID, sin sus actualizaciones
+func (r *CauseRepository) GetSummary(ctx context.Context, id string) (*models.Cause, error) {
 	var cause models.Cause
 	err := r.db.Get(ctx, causesCollection, id, &cause)
 	if err != nil {
@@ -65,37 +101,114 @@ func (r *CauseRepository) GetByGuiverID(ctx context.Context, guiverID string) ([
 	return causes, nil
 }
 
-// Update actualiza una Causa
+// Update actualiza una Causa, conservando los contadores, los totales
+// recaudados, que pasan a la moneda de GoalAmount, y la actualización fijada.
+// Las actualizaciones viven en su subcolección y no se modifican. La
+// transacción compite con las de los aportes, que leen la Causa.
 func (r *CauseRepository) Update(ctx context.Context, cause *models.Cause) error {
 	cause.UpdatedAt = time.Now()
-	return r.db.Update(ctx, causesCollection, cause.ID, cause)
+	path := causesCollection + "/" + cause.ID
+	return r.db.RunTransaction(ctx, func(tx *firestore.Tx) error {
+		var stored causeDocument
+		if err := tx.Get(path, &stored); err != nil {
+			return err
+		}
+		// Lo recaudado y lo que se está cobrando están en la moneda actual
+		if stored.GoalAmount.Currency != cause.GoalAmount.Currency {
+			pending, err := hasPledges(tx, cause.ID, models.PledgeStatusPending)
+			if err != nil {
+				return err
+			}
+			if pending || !stored.RaisedAmount.IsZero() {
+				return repository.ErrConflict
+			}
+		}
+
+		cause.Likes = stored.Likes
+		cause.CommentCount = stored.CommentCount
+		cause.UpdateCount = stored.UpdateCount
+		cause.PinnedUpdateID = stored.PinnedUpdateID
+		cause.RaisedAmount = models.NewMoney(stored.RaisedAmount.Amount, cause.GoalAmount.Currency)
+		cause.BackerCount = stored.BackerCount
+		doc := newCauseDocument(cause)
+		doc.LegacyUpdates = stored.LegacyUpdates
+		return tx.Set(path, doc)
+	})
 }
 
-// Delete elimina una Causa
-func (r *CauseRepository) Delete(ctx context.Context, id string) error {
-	return r.db.Delete(ctx, causesCollection, id)
+// transform reemplaza atómicamente la Causa guardada por el resultado de fn,
+// conservando las actualizaciones que aún no se migraron a su subcolección
+func (r *CauseRepository) transform(ctx context.Context, id string, fn func(stored *models.Cause) *models.Cause) error {
+	return r.db.Transform(ctx, causesCollection, id, func(decode func(dest interface{}) error) (interface{}, error) {
+		var stored causeDocument
+		if err := decode(&stored); err != nil {
+			return nil, err
+		}
+		doc := newCauseDocument(fn(&stored.Cause))
+		doc.LegacyUpdates = stored.LegacyUpdates
+		return doc, nil
+	})
 }
 
-// List lista las Causas según los filtros
-func (r *CauseRepository) List(ctx context.Context, filter repository.CauseFilter) ([]*models.Cause, error) {
-	var causes []*models.Cause
-	var queries []firestore.Query
+// Delete elimina una Causa junto con sus actualizaciones, comentarios, likes
+// y aportes, salvo que tenga aportes abiertos. La Causa se elimina en una
+// transacción que compite con las de los aportes; sus subcolecciones, después.
+func (r *CauseRepository) Delete(ctx context.Context, id string) error {
+	err := r.db.RunTransaction(ctx, func(tx *firestore.Tx) error {
+		exists, err := tx.Exists(causesCollection + "/" + id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return repository.ErrNotFound
+		}
+		open, err := hasPledges(tx, id, models.OpenPledgeStatuses...)
+		if err != nil {
+			return err
+		}
+		if open {
+			return repository.ErrConflict
+		}
+		return tx.Delete(causesCollection + "/" + id)
+	})
+	if err != nil {
+		return err
+	}
+	for _, sub := range []string{updatesCollection, commentsCollection, likesCollection, pledgesCollection, backersCollection} {
+		if err := r.db.DeleteCollection(ctx, causesCollection+"/"+id+"/"+sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	if filter.Type != "" {
-		queries = append(queries, firestore.WhereQuery{Field: "type", Op: "==", Value: filter.Type})
+// hasPledges indica si la Causa tiene aportes en alguno de los estados
+// indicados
+func hasPledges(tx *firestore.Tx, causeID string, statuses ...models.PledgeStatus) (bool, error) {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
 	}
-	if filter.Status != "" {
-		queries = append(queries, firestore.WhereQuery{Field: "status", Op: "==", Value: filter.Status})
+	var pledges []*models.Pledge
+	queries := []firestore.Query{
+		firestore.WhereQuery{Field: "status", Op: "in", Value: values},
+		firestore.LimitQuery{Limit: 1},
 	}
-	if filter.Location != "" {
-		queries = append(queries, firestore.WhereQuery{Field: "location", Op: "==", Value: filter.Location})
+	if err := tx.Query(pledgesPath(causeID), queries, &pledges); err != nil {
+		return false, err
 	}
+	return len(pledges) > 0, nil
+}
 
-	queries = append(queries,
-		firestore.OrderByQuery{Field: "createdAt", Direction: firestore.DESC},
-		firestore.LimitQuery{Limit: filter.Limit},
-		firestore.OffsetQuery{Offset: filter.Offset},
-	)
+// List lista las Causas según los filtros
+func (r *CauseRepository) List(ctx context.Context, filter repository.CauseFilter) ([]*models.Cause, error) {
+	if q := search.ParseQuery(filter.Search); !q.Empty() {
+		return r.search(ctx, filter, q)
+	}
+
+	var causes []*models.Cause
+	queries := causeFilterQueries(filter)
+	queries = append(queries, pageQueries(filter.After, filter.Limit, filter.Offset)...)
 
 	err := r.db.Query(ctx, causesCollection, queries, &causes)
 	if err != nil {
@@ -104,40 +217,128 @@ func (r *CauseRepository) List(ctx context.Context, filter repository.CauseFilte
 	return causes, nil
 }
 
-// AddUpdate agrega una actualización a una Causa
-func (r *CauseRepository) AddUpdate(ctx context.Context, causeID string, update *models.Update) error {
-	update.ID = uuid.New().String()
-	update.CreatedAt = time.Now()
+// Count cuenta las Causas que cumplen los filtros, ignorando Limit y Offset
+func (r *CauseRepository) Count(ctx context.Context, filter repository.CauseFilter) (int64, error) {
+	if q := search.ParseQuery(filter.Search); !q.Empty() {
+		causes, err := searchMatches(ctx, r.db, causesCollection, causeFilterQueries(filter), q, causeText, causeCursor, nil, 0)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(causes)), nil
+	}
+	return r.db.Count(ctx, causesCollection, causeFilterQueries(filter))
+}
+
+// search busca Causas por palabras clave en el título y la descripción y las
+// ordena por relevancia
+func (r *CauseRepository) search(ctx context.Context, filter repository.CauseFilter, q search.Query) ([]*models.Cause, error) {
+	filters := causeFilterQueries(filter)
+	if filter.After != nil {
+		// Con cursor se conserva el orden por fecha, el único que puede continuar
+		return searchMatches(ctx, r.db, causesCollection, filters, q, causeText, causeCursor, filter.After, filter.Limit)
+	}
+	return searchRanked(ctx, r.db, causesCollection, filters, q, causeText, causeCursor, filter.Limit, filter.Offset)
+}
 
-	cause, err := r.GetByID(ctx, causeID)
-	if err != nil {
+// causeCursor devuelve la posición de una Causa en los listados por fecha
+func causeCursor(cause *models.Cause) repository.Cursor {
+	return repository.Cursor{CreatedAt: cause.CreatedAt, ID: cause.ID}
+}
+
+// ReindexSearch recalcula los términos de búsqueda de todas las Causas, por
+// ejemplo para los documentos creados antes de existir el índice
+func (r *CauseRepository) ReindexSearch(ctx context.Context) error {
+	var causes []*models.Cause
+	if err := r.db.Query(ctx, causesCollection, nil, &causes); err != nil {
 		return err
 	}
+	for _, cause := range causes {
+		// Se relee dentro de la transacción para no pisar contadores concurrentes
+		err := r.transform(ctx, cause.ID, func(stored *models.Cause) *models.Cause { return stored })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	cause.Updates = append(cause.Updates, *update)
-	return r.Update(ctx, cause)
+// pageQueries ordena por fecha de creación descendente, desempatando por ID, y
+// aplica la paginación por cursor o, si no hay cursor, por offset
+func pageQueries(after *repository.Cursor, limit, offset int) []firestore.Query {
+	queries := []firestore.Query{
+		firestore.OrderByQuery{Field: "createdAt", Direction: firestore.DESC},
+		firestore.OrderByQuery{Field: "id", Direction: firestore.DESC},
+	}
+	if after != nil {
+		queries = append(queries, firestore.StartAfterQuery{Values: []interface{}{after.CreatedAt, after.ID}})
+	} else if offset > 0 {
+		queries = append(queries, firestore.OffsetQuery{Offset: offset})
+	}
+	if limit > 0 {
+		queries = append(queries, firestore.LimitQuery{Limit: limit})
+	}
+	return queries
 }
 
-// AddComment agrega un comentario a una Causa
-func (r *CauseRepository) AddComment(ctx context.Context, causeID string, comment *models.Comment) error {
-	comment.ID = uuid.New().String()
-	comment.CreatedAt = time.Now()
+// causeFilterQueries construye las condiciones where de un CauseFilter
+func causeFilterQueries(filter repository.CauseFilter) []firestore.Query {
+	var queries []firestore.Query
 
-	return r.db.Create(ctx, causesCollection+"/"+causeID+"/"+commentsCollection, comment.ID, comment)
+	if filter.Type != "" {
+		queries = append(queries, firestore.WhereQuery{Field: "type", Op: "==", Value: filter.Type})
+	}
+	if filter.Status != "" {
+		queries = append(queries, firestore.WhereQuery{Field: "status", Op: "==", Value: filter.Status})
+	}
+	if filter.Location != "" {
+		queries = append(queries, firestore.WhereQuery{Field: "location", Op: "==", Value: filter.Location})
+	}
+	return queries
 }
 
-// UpdateLikes actualiza los likes de una Causa
-func (r *CauseRepository) UpdateLikes(ctx context.Context, causeID string, increment bool) error {
-	cause, err := r.GetByID(ctx, causeID)
-	if err != nil {
-		return err
+// likesCounter es el contador de likes de una Causa, respaldado por un
+// documento por Guiver en la subcolección likes
+func likesCounter(causeID string) firestore.Counter {
+	return firestore.Counter{
+		Collection:    causesCollection,
+		ID:            causeID,
+		Field:         "likes",
+		Subcollection: likesCollection,
 	}
+}
 
-	if increment {
-		cause.Likes++
-	} else if cause.Likes > 0 {
-		cause.Likes--
+// Like registra el like del Guiver en la Causa, una sola vez por Guiver
+func (r *CauseRepository) Like(ctx context.Context, causeID, guiverID string) (int, error) {
+	like := &models.Like{GuiverID: guiverID, CreatedAt: time.Now()}
+	likes, _, err := r.db.AddCounted(ctx, likesCounter(causeID), guiverID, like)
+	return int(likes), err
+}
+
+// Unlike quita el like del Guiver en la Causa, si existía
+func (r *CauseRepository) Unlike(ctx context.Context, causeID, guiverID string) (int, error) {
+	likes, _, err := r.db.RemoveCounted(ctx, likesCounter(causeID), guiverID)
+	return int(likes), err
+}
+
+// LikedBy indica cuáles de las Causas indicadas le gustan al Guiver
+func (r *CauseRepository) LikedBy(ctx context.Context, guiverID string, causeIDs []string) (map[string]bool, error) {
+	liked := make(map[string]bool)
+	if len(causeIDs) == 0 {
+		return liked, nil
 	}
 
-	return r.Update(ctx, cause)
+	paths := make([]string, len(causeIDs))
+	for i, id := range causeIDs {
+		paths[i] = causesCollection + "/" + id + "/" + likesCollection + "/" + guiverID
+	}
+	exists, err := r.db.Exists(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range causeIDs {
+		if exists[i] {
+			liked[id] = true
+		}
+	}
+	return liked, nil
 }
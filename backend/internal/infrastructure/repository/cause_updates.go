@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/infrastructure/firestore"
+)
+
+// updatesPath es la subcolección de actualizaciones de una Causa
+func updatesPath(causeID string) string {
+	return causesCollection + "/" + causeID + "/" + updatesCollection
+}
+
+// updatesCounter es el contador de actualizaciones de una Causa
+func updatesCounter(causeID string) firestore.Counter {
+	return firestore.Counter{
+		Collection:    causesCollection,
+		ID:            causeID,
+		Field:         "updateCount",
+		Subcollection: updatesCollection,
+		Touch:         "updatedAt",
+	}
+}
+
+// AddUpdate agrega una actualización a una Causa
+func (r *CauseRepository) AddUpdate(ctx context.Context, causeID string, update *models.Update) error {
+	update.ID = uuid.New().String()
+	update.CreatedAt = time.Now()
+	update.EditedAt = nil
+
+	_, _, err := r.db.AddCounted(ctx, updatesCounter(causeID), update.ID, update)
+	return err
+}
+
+// GetUpdate obtiene una actualización de una Causa
+func (r *CauseRepository) GetUpdate(ctx context.Context, causeID, updateID string) (*models.Update, error) {
+	var update models.Update
+	if err := r.db.Get(ctx, updatesPath(causeID), updateID, &update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// ListUpdates lista las actualizaciones de una Causa, de la más nueva a la más
+// antigua
+func (r *CauseRepository) ListUpdates(ctx context.Context, causeID string, filter repository.UpdateFilter) ([]*models.Update, error) {
+	// La subcolección vacía no distingue una Causa sin actualizaciones de una inexistente
+	if _, err := r.GetSummary(ctx, causeID); err != nil {
+		return nil, err
+	}
+
+	updates := []*models.Update{}
+	err := r.db.Query(ctx, updatesPath(causeID), pageQueries(filter.After, filter.Limit, 0), &updates)
+	if err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// EditUpdate cambia el contenido, las imágenes y los gastos de una actualización
+func (r *CauseRepository) EditUpdate(ctx context.Context, causeID string, update *models.Update) error {
+	return r.db.Transform(ctx, updatesPath(causeID), update.ID, func(decode func(dest interface{}) error) (interface{}, error) {
+		var stored models.Update
+		if err := decode(&stored); err != nil {
+			return nil, err
+		}
+		editedAt := time.Now()
+		stored.Content = update.Content
+		stored.ImageURLs = update.ImageURLs
+		stored.Expenses = update.Expenses
+		stored.EditedAt = &editedAt
+		*update = stored
+		return &stored, nil
+	})
+}
+
+// DeleteUpdate elimina una actualización de una Causa y la desfija si estaba fijada
+func (r *CauseRepository) DeleteUpdate(ctx context.Context, causeID, updateID string) error {
+	_, deleted, err := r.db.RemoveCounted(ctx, updatesCounter(causeID), updateID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return repository.ErrNotFound
+	}
+
+	// Si otra petición la fija mientras tanto queda fijada una actualización
+	// inexistente, que los lectores ignoran
+	return r.transform(ctx, causeID, func(stored *models.Cause) *models.Cause {
+		if stored.PinnedUpdateID == updateID {
+			stored.PinnedUpdateID = ""
+		}
+		return stored
+	})
+}
+
+// PinUpdate fija una actualización de la Causa o, con updateID vacío, desfija
+// la actual
+func (r *CauseRepository) PinUpdate(ctx context.Context, causeID, updateID string) error {
+	if updateID != "" {
+		if _, err := r.GetUpdate(ctx, causeID, updateID); err != nil {
+			return err
+		}
+	}
+	return r.transform(ctx, causeID, func(stored *models.Cause) *models.Cause {
+		stored.PinnedUpdateID = updateID
+		return stored
+	})
+}
+
+// ExpenseTotals suma los gastos de las actualizaciones de una Causa por moneda
+// y categoría leyendo todas sus actualizaciones, ya que los gastos están dentro
+// de cada una
+func (r *CauseRepository) ExpenseTotals(ctx context.Context, causeID string) ([]models.ExpenseTotal, error) {
+	var updates []models.Update
+	if err := r.db.Query(ctx, updatesPath(causeID), nil, &updates); err != nil {
+		return nil, err
+	}
+
+	totals := models.ExpenseTotals{}
+	for _, update := range updates {
+		totals.Add(update.Expenses...)
+	}
+	return totals.List(), nil
+}
+
+// loadUpdates completa las actualizaciones de la Causa, de la más antigua a la
+// más nueva
+func (r *CauseRepository) loadUpdates(ctx context.Context, cause *models.Cause) error {
+	var updates []models.Update
+	if err := r.db.Query(ctx, updatesPath(cause.ID), nil, &updates); err != nil {
+		return err
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		if updates[i].CreatedAt.Equal(updates[j].CreatedAt) {
+			return updates[i].ID < updates[j].ID
+		}
+		return updates[i].CreatedAt.Before(updates[j].CreatedAt)
+	})
+	cause.Updates = updates
+	return nil
+}
+
+// MigrateUpdates mueve a la subcolección updates las actualizaciones que
+// todavía se guardan dentro del documento de cada Causa. Se puede repetir sin
+// duplicarlas.
+func (r *CauseRepository) MigrateUpdates(ctx context.Context) error {
+	var docs []*causeDocument
+	if err := r.db.Query(ctx, causesCollection, nil, &docs); err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if len(doc.LegacyUpdates) == 0 {
+			continue
+		}
+		for i := range doc.LegacyUpdates {
+			update := &doc.LegacyUpdates[i]
+			if update.GuiverID == "" {
+				update.GuiverID = doc.GuiverID
+			}
+			err := r.db.Create(ctx, updatesPath(doc.ID), update.ID, update)
+			if err != nil && !errors.Is(err, repository.ErrConflict) {
+				return err
+			}
+		}
+
+		err := r.db.Transform(ctx, causesCollection, doc.ID, func(decode func(dest interface{}) error) (interface{}, error) {
+			var stored causeDocument
+			if err := decode(&stored); err != nil {
+				return nil, err
+			}
+			count, err := r.db.Count(ctx, updatesPath(doc.ID), nil)
+			if err != nil {
+				return nil, err
+			}
+			stored.UpdateCount = int(count)
+			return newCauseDocument(&stored.Cause), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/infrastructure/firestore"
+)
+
+// ledgerCollection guarda los movimientos de todas las Causas. No es una
+// subcolección de la Causa para que el historial sobreviva a su eliminación.
+const ledgerCollection = "ledger"
+
+// LedgerRepository implementa el libro de las causas usando Firestore. Cada
+// documento es un movimiento con sus imputaciones.
+type LedgerRepository struct {
+	db *firestore.Client
+}
+
+// NewLedgerRepository crea una nueva instancia de LedgerRepository
+func NewLedgerRepository(db *firestore.Client) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Append registra un movimiento
+func (r *LedgerRepository) Append(ctx context.Context, entry *models.LedgerEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	entry.CreatedAt = time.Now()
+
+	if _, ok := entry.FundingAccount(); !ok {
+		return r.db.Create(ctx, ledgerCollection, entry.ID, entry)
+	}
+	// El saldo se verifica en la misma transacción que registra el movimiento
+	return r.db.RunTransaction(ctx, func(tx *firestore.Tx) error {
+		var entries []*models.LedgerEntry
+		queries := []firestore.Query{
+			firestore.WhereQuery{Field: "causeId", Op: "==", Value: entry.CauseID},
+			firestore.WhereQuery{Field: "amount.currency", Op: "==", Value: entry.Amount.Currency},
+		}
+		if err := tx.Query(ledgerCollection, queries, &entries); err != nil {
+			return err
+		}
+		balances := models.LedgerBalances{}
+		for _, stored := range entries {
+			balances.Add(stored)
+		}
+		if !balances.Covers(entry) {
+			return repository.ErrInsufficientFunds
+		}
+		return tx.Create(ledgerCollection+"/"+entry.ID, entry)
+	})
+}
+
+// ListByCause lista los movimientos de una Causa, del más nuevo al más antiguo
+func (r *LedgerRepository) ListByCause(ctx context.Context, causeID string, filter repository.LedgerFilter) ([]*models.LedgerEntry, error) {
+	queries := []firestore.Query{firestore.WhereQuery{Field: "causeId", Op: "==", Value: causeID}}
+	if filter.Type != "" {
+		queries = append(queries, firestore.WhereQuery{Field: "type", Op: "==", Value: string(filter.Type)})
+	}
+	queries = append(queries, pageQueries(filter.After, filter.Limit, 0)...)
+
+	entries := []*models.LedgerEntry{}
+	if err := r.db.Query(ctx, ledgerCollection, queries, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Balances calcula los saldos de las cuentas de una Causa leyendo todos sus
+// movimientos, ya que Firestore no agrupa las agregaciones
+func (r *LedgerRepository) Balances(ctx context.Context, causeID string) (models.LedgerBalances, error) {
+	var entries []*models.LedgerEntry
+	queries := []firestore.Query{firestore.WhereQuery{Field: "causeId", Op: "==", Value: causeID}}
+	if err := r.db.Query(ctx, ledgerCollection, queries, &entries); err != nil {
+		return nil, err
+	}
+
+	balances := models.LedgerBalances{}
+	for _, entry := range entries {
+		balances.Add(entry)
+	}
+	return balances, nil
+}
+
+// ListDonations lista los movimientos de las donaciones de los pedidos, del
+// más nuevo al más antiguo
+func (r *LedgerRepository) ListDonations(ctx context.Context, filter repository.DonationFilter) ([]*models.LedgerEntry, error) {
+	queries := append(donationQueries(filter), pageQueries(filter.After, filter.Limit, 0)...)
+
+	entries := []*models.LedgerEntry{}
+	if err := r.db.Query(ctx, ledgerCollection, queries, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DonationTotals suma por moneda lo donado por los pedidos que cumplen el
+// filtro, neto de reembolsos. Firestore no agrupa las agregaciones, así que se
+// leen los movimientos.
+func (r *LedgerRepository) DonationTotals(ctx context.Context, filter repository.DonationFilter) (map[string]int64, error) {
+	var entries []*models.LedgerEntry
+	if err := r.db.Query(ctx, ledgerCollection, donationQueries(filter), &entries); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64)
+	for _, entry := range entries {
+		totals[entry.Amount.Currency] += entry.DonatedAmount()
+	}
+	return totals, nil
+}
+
+// donationQueries construye las condiciones where de un DonationFilter
+func donationQueries(filter repository.DonationFilter) []firestore.Query {
+	types := make([]string, len(models.ProductDonationTypes))
+	for i, entryType := range models.ProductDonationTypes {
+		types[i] = string(entryType)
+	}
+	queries := []firestore.Query{firestore.WhereQuery{Field: "type", Op: "in", Value: types}}
+	if filter.CauseID != "" {
+		queries = append(queries, firestore.WhereQuery{Field: "causeId", Op: "==", Value: filter.CauseID})
+	}
+	if filter.GuiverID != "" {
+		queries = append(queries, firestore.WhereQuery{Field: "guiverId", Op: "==", Value: filter.GuiverID})
+	}
+	return queries
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/infrastructure/firestore"
+)
+
+const ordersCollection = "orders"
+
+// OrderRepository implementa el repositorio de pedidos usando Firestore
+type OrderRepository struct {
+	db *firestore.Client
+}
+
+// NewOrderRepository crea una nueva instancia de OrderRepository
+func NewOrderRepository(db *firestore.Client) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// Create registra un pedido pendiente
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error {
+	if order.ID == "" {
+		order.ID = uuid.New().String()
+	}
+	order.Status = models.OrderStatusPending
+	order.CalculateTotals()
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	return r.db.Create(ctx, ordersCollection, order.ID, order)
+}
+
+// GetByID obtiene un pedido por su ID
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.Get(ctx, ordersCollection, id, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// UpdateStatus cambia el estado de un pedido
+func (r *OrderRepository) UpdateStatus(ctx context.Context, id string, status models.OrderStatus) (*models.Order, error) {
+	path := ordersCollection + "/" + id
+	var order models.Order
+	err := r.db.RunTransaction(ctx, func(tx *firestore.Tx) error {
+		order = models.Order{}
+		if err := tx.Get(path, &order); err != nil {
+			return err
+		}
+		if order.Status == status {
+			return nil
+		}
+		if !order.Status.CanBecome(status) {
+			return repository.ErrConflict
+		}
+
+		order.Status = status
+		order.UpdatedAt = time.Now()
+		return tx.Set(path, &order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// List lista los pedidos según los filtros, del más nuevo al más antiguo
+func (r *OrderRepository) List(ctx context.Context, filter repository.OrderFilter) ([]*models.Order, error) {
+	var queries []firestore.Query
+	for _, f := range []struct{ field, value string }{
+		{"buyerId", filter.BuyerID},
+		{"sellerId", filter.SellerID},
+		{"causeId", filter.CauseID},
+		{"status", string(filter.Status)},
+	} {
+		if f.value != "" {
+			queries = append(queries, firestore.WhereQuery{Field: f.field, Op: "==", Value: f.value})
+		}
+	}
+	queries = append(queries, pageQueries(filter.After, filter.Limit, 0)...)
+
+	orders := []*models.Order{}
+	if err := r.db.Query(ctx, ordersCollection, queries, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
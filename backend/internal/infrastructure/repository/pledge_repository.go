@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/infrastructure/firestore"
+)
+
+// PledgeRepository implementa el repositorio de aportes usando Firestore. Los
+// aportes se guardan en la subcolección pledges de cada Causa y los Guivers
+// con aportes pagados en la subcolección backers.
+type PledgeRepository struct {
+	db *firestore.Client
+}
+
+// NewPledgeRepository crea una nueva instancia de PledgeRepository
+func NewPledgeRepository(db *firestore.Client) *PledgeRepository {
+	return &PledgeRepository{db: db}
+}
+
+// backer lleva la cuenta de los aportes pagados de un Guiver a una Causa, para
+// contarlo una sola vez en BackerCount
+type backer struct {
+	GuiverID  string    `firestore:"guiverId"`
+	Pledges   int       `firestore:"pledges"`
+	CreatedAt time.Time `firestore:"createdAt"`
+}
+
+// pledgesPath es la subcolección de aportes de una Causa
+func pledgesPath(causeID string) string {
+	return causesCollection + "/" + causeID + "/" + pledgesCollection
+}
+
+// Create registra un aporte y, si ya está pagado, actualiza los totales de la
+// Causa en la misma transacción
+func (r *PledgeRepository) Create(ctx context.Context, pledge *models.Pledge) error {
+	if pledge.ID == "" {
+		pledge.ID = uuid.New().String()
+	}
+	if pledge.Status == "" {
+		pledge.Status = models.PledgeStatusPending
+	}
+	pledge.CreatedAt = time.Now()
+
+	return r.db.RunTransaction(ctx, func(tx *firestore.Tx) error {
+		exists, err := tx.Exists(causesCollection + "/" + pledge.CauseID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return repository.ErrNotFound
+		}
+
+		apply := func() error { return nil }
+		if pledge.Status == models.PledgeStatusPaid {
+			if apply, err = r.count(tx, pledge, 1); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(pledgesPath(pledge.CauseID)+"/"+pledge.ID, pledge); err != nil {
+			return err
+		}
+		return apply()
+	})
+}
+
+// GetByID obtiene un aporte de una Causa
+func (r *PledgeRepository) GetByID(ctx context.Context, causeID, id string) (*models.Pledge, error) {
+	var pledge models.Pledge
+	if err := r.db.Get(ctx, pledgesPath(causeID), id, &pledge); err != nil {
+		return nil, err
+	}
+	return &pledge, nil
+}
+
+// UpdateStatus cambia el estado de un aporte y ajusta los totales de la Causa
+// en la misma transacción
+func (r *PledgeRepository) UpdateStatus(ctx context.Context, causeID, id string, status models.PledgeStatus) (*models.Pledge, error) {
+	path := pledgesPath(causeID) + "/" + id
+	var pledge models.Pledge
+	err := r.db.RunTransaction(ctx, func(tx *firestore.Tx) error {
+		pledge = models.Pledge{}
+		if err := tx.Get(path, &pledge); err != nil {
+			return err
+		}
+		if pledge.Status == status {
+			return nil
+		}
+		if !pledge.Status.CanBecome(status) {
+			return repository.ErrConflict
+		}
+
+		apply := func() error { return nil }
+		var err error
+		switch status {
+		case models.PledgeStatusPaid:
+			apply, err = r.count(tx, &pledge, 1)
+		case models.PledgeStatusRefunded:
+			apply, err = r.count(tx, &pledge, -1)
+		}
+		if err != nil {
+			return err
+		}
+
+		pledge.Status = status
+		if err := tx.Set(path, &pledge); err != nil {
+			return err
+		}
+		return apply()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pledge, nil
+}
+
+// count prepara la suma (sign 1) o resta (sign -1) del aporte en los totales
+// de la Causa. Hace las lecturas y devuelve las escrituras, que Firestore exige
+// hacer después.
+func (r *PledgeRepository) count(tx *firestore.Tx, pledge *models.Pledge, sign int) (func() error, error) {
+	causePath := causesCollection + "/" + pledge.CauseID
+	backerPath := causePath + "/" + backersCollection + "/" + pledge.GuiverID
+
+	var stored backer
+	known, err := tx.Exists(backerPath)
+	if err != nil {
+		return nil, err
+	}
+	if known {
+		if err := tx.Get(backerPath, &stored); err != nil {
+			return nil, err
+		}
+	} else {
+		stored = backer{GuiverID: pledge.GuiverID, CreatedAt: time.Now()}
+	}
+
+	return func() error {
+		deltas := map[string]int64{"raisedAmount.amount": int64(sign) * pledge.Amount.Amount}
+		stored.Pledges += sign
+		switch {
+		case sign > 0 && !known:
+			deltas["backerCount"] = 1
+		case sign < 0 && known && stored.Pledges <= 0:
+			deltas["backerCount"] = -1
+		}
+
+		var err error
+		if stored.Pledges > 0 {
+			err = tx.Set(backerPath, stored)
+		} else if known {
+			err = tx.Delete(backerPath)
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Increment(causePath, deltas)
+	}, nil
+}
+
+// ListByCause lista los aportes de una Causa, del más nuevo al más antiguo
+func (r *PledgeRepository) ListByCause(ctx context.Context, causeID string, filter repository.PledgeFilter) ([]*models.Pledge, error) {
+	// La subcolección vacía no distingue una Causa sin aportes de una inexistente
+	var cause models.Cause
+	if err := r.db.Get(ctx, causesCollection, causeID, &cause); err != nil {
+		return nil, err
+	}
+
+	pledges := []*models.Pledge{}
+	if err := r.db.Query(ctx, pledgesPath(causeID), pageQueries(filter.After, filter.Limit, 0), &pledges); err != nil {
+		return nil, err
+	}
+	return pledges, nil
+}
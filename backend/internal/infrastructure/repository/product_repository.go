@@ -8,6 +8,7 @@ import (
 	"github.com/guiver/internal/domain/models"
 	"github.com/guiver/internal/domain/repository"
 	"github.com/guiver/internal/infrastructure/firestore"
+	"github.com/guiver/internal/search"
 )
 
 const productsCollection = "products"
@@ -32,7 +33,7 @@ func (r *ProductRepository) Create(ctx context.Context, product *models.Product)
 	product.CreatedAt = now
 	product.UpdatedAt = now
 
-	return r.db.Create(ctx, productsCollection, product.ID, product)
+	return r.db.Create(ctx, productsCollection, product.ID, newProductDocument(product))
 }
 
 // GetByID obtiene un Producto por su ID
@@ -75,10 +76,42 @@ func (r *ProductRepository) GetByGuiverID(ctx context.Context, guiverID string)
 	return products, nil
 }
 
-// Update actualiza un Producto
-func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+// Update actualiza un Producto en una transacción, conservando las
+// existencias guardadas salvo con replaceStock
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product, replaceStock bool) error {
 	product.UpdatedAt = time.Now()
-	return r.db.Update(ctx, productsCollection, product.ID, product)
+	return r.db.Transform(ctx, productsCollection, product.ID, func(decode func(dest interface{}) error) (interface{}, error) {
+		if !replaceStock {
+			var stored models.Product
+			if err := decode(&stored); err != nil {
+				return nil, err
+			}
+			product.Stock, product.Variants = stored.Stock, stored.Variants
+		}
+		product.SyncStock()
+		return newProductDocument(product), nil
+	})
+}
+
+// AdjustStock suma delta a las existencias del Producto o de su variante en
+// una transacción, de modo que dos compras simultáneas no vendan la misma unidad
+func (r *ProductRepository) AdjustStock(ctx context.Context, id, variantID string, delta int) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Transform(ctx, productsCollection, id, func(decode func(dest interface{}) error) (interface{}, error) {
+		product = models.Product{}
+		if err := decode(&product); err != nil {
+			return nil, err
+		}
+		if err := product.AdjustStock(variantID, delta); err != nil {
+			return nil, err
+		}
+		product.UpdatedAt = time.Now()
+		return newProductDocument(&product), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
 }
 
 // Delete elimina un Producto
@@ -88,7 +121,117 @@ func (r *ProductRepository) Delete(ctx context.Context, id string) error {
 
 // List lista los Productos según los filtros
 func (r *ProductRepository) List(ctx context.Context, filter repository.ProductFilter) ([]*models.Product, error) {
+	if q := search.ParseQuery(filter.Search); !q.Empty() {
+		return r.search(ctx, filter, q)
+	}
+
 	var products []*models.Product
+	queries := productFilterQueries(filter)
+	queries = append(queries, pageQueries(filter.After, filter.Limit, filter.Offset)...)
+
+	err := r.db.Query(ctx, productsCollection, queries, &products)
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Count cuenta los Productos que cumplen los filtros, ignorando Limit y Offset
+func (r *ProductRepository) Count(ctx context.Context, filter repository.ProductFilter) (int64, error) {
+	if q := search.ParseQuery(filter.Search); !q.Empty() {
+		products, err := searchMatches(ctx, r.db, productsCollection, productFilterQueries(filter), q, productText, productCursor, nil, 0)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(products)), nil
+	}
+	return r.db.Count(ctx, productsCollection, productFilterQueries(filter))
+}
+
+// search busca Productos por palabras clave en el título y la descripción y los
+// ordena por relevancia
+func (r *ProductRepository) search(ctx context.Context, filter repository.ProductFilter, q search.Query) ([]*models.Product, error) {
+	filters := productFilterQueries(filter)
+	if filter.After != nil {
+		// Con cursor se conserva el orden por fecha, el único que puede continuar
+		return searchMatches(ctx, r.db, productsCollection, filters, q, productText, productCursor, filter.After, filter.Limit)
+	}
+	return searchRanked(ctx, r.db, productsCollection, filters, q, productText, productCursor, filter.Limit, filter.Offset)
+}
+
+// productCursor devuelve la posición de un Producto en los listados por fecha
+func productCursor(product *models.Product) repository.Cursor {
+	return repository.Cursor{CreatedAt: product.CreatedAt, ID: product.ID}
+}
+
+// ReindexSearch recalcula los términos de búsqueda de todos los Productos, por
+// ejemplo para los documentos creados antes de existir el índice
+func (r *ProductRepository) ReindexSearch(ctx context.Context) error {
+	var products []*models.Product
+	if err := r.db.Query(ctx, productsCollection, nil, &products); err != nil {
+		return err
+	}
+	for _, product := range products {
+		if err := r.db.Update(ctx, productsCollection, product.ID, newProductDocument(product)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyProductPrice es el precio de un Producto anterior a Money: un número en
+// unidades mayores, sin moneda
+type legacyProductPrice struct {
+	ID    string      `firestore:"id"`
+	Price interface{} `firestore:"price"`
+}
+
+// MigratePrices convierte los precios guardados como número en unidades mayores
+// a Money en la moneda indicada. Se puede repetir: los ya convertidos no cambian.
+func (r *ProductRepository) MigratePrices(ctx context.Context, currency string) error {
+	if !models.IsCurrency(currency) {
+		return models.ErrInvalidCurrency
+	}
+	var docs []*legacyProductPrice
+	if err := r.db.Query(ctx, productsCollection, nil, &docs); err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if _, ok := legacyPrice(doc.Price); !ok {
+			continue
+		}
+		err := r.db.Transform(ctx, productsCollection, doc.ID, func(decode func(dest interface{}) error) (interface{}, error) {
+			var stored map[string]interface{}
+			if err := decode(&stored); err != nil {
+				return nil, err
+			}
+			if major, ok := legacyPrice(stored["price"]); ok {
+				stored["price"] = models.MoneyFromMajor(major, currency)
+			}
+			return stored, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyPrice devuelve el precio en unidades mayores si value es un precio
+// anterior a Money
+func legacyPrice(value interface{}) (float64, bool) {
+	switch price := value.(type) {
+	case float64:
+		return price, true
+	case int64:
+		return float64(price), true
+	}
+	return 0, false
+}
+
+// productFilterQueries construye las condiciones where de un ProductFilter
+func productFilterQueries(filter repository.ProductFilter) []firestore.Query {
 	var queries []firestore.Query
 
 	if filter.CauseID != "" {
@@ -97,22 +240,25 @@ func (r *ProductRepository) List(ctx context.Context, filter repository.ProductF
 	if filter.GuiverID != "" {
 		queries = append(queries, firestore.WhereQuery{Field: "guiverId", Op: "==", Value: filter.GuiverID})
 	}
-	if filter.MinPrice > 0 {
-		queries = append(queries, firestore.WhereQuery{Field: "price", Op: ">=", Value: filter.MinPrice})
-	}
-	if filter.MaxPrice > 0 {
-		queries = append(queries, firestore.WhereQuery{Field: "price", Op: "<=", Value: filter.MaxPrice})
+	if len(filter.PriceRanges) > 0 {
+		queries = append(queries, priceRangesQuery(filter.PriceRanges))
 	}
+	return queries
+}
 
-	queries = append(queries,
-		firestore.OrderByQuery{Field: "createdAt", Direction: firestore.DESC},
-		firestore.LimitQuery{Limit: filter.Limit},
-		firestore.OffsetQuery{Offset: filter.Offset},
-	)
-
-	err := r.db.Query(ctx, productsCollection, queries, &products)
-	if err != nil {
-		return nil, err
+// priceRangesQuery construye la condición de que el precio caiga en alguno de
+// los rangos, cada uno en su moneda
+func priceRangesQuery(ranges []repository.PriceRange) firestore.OrQuery {
+	var query firestore.OrQuery
+	for _, priceRange := range ranges {
+		clause := []firestore.WhereQuery{{Field: "price.currency", Op: "==", Value: priceRange.Currency}}
+		if priceRange.Min > 0 {
+			clause = append(clause, firestore.WhereQuery{Field: "price.amount", Op: ">=", Value: priceRange.Min})
+		}
+		if priceRange.Max > 0 {
+			clause = append(clause, firestore.WhereQuery{Field: "price.amount", Op: "<=", Value: priceRange.Max})
+		}
+		query.Clauses = append(query.Clauses, clause)
 	}
-	return products, nil
+	return query
 }
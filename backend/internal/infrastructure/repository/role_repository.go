@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/infrastructure/firestore"
+)
+
+const rolesCollection = "roles"
+
+// roleAssignment es el documento con los roles de un Guiver, cuyo ID es el
+// del Guiver
+type roleAssignment struct {
+	GuiverID  string        `firestore:"guiverId"`
+	Roles     []models.Role `firestore:"roles"`
+	UpdatedAt time.Time     `firestore:"updatedAt"`
+}
+
+// RoleRepository implementa el repositorio de roles usando Firestore
+type RoleRepository struct {
+	db *firestore.Client
+}
+
+// NewRoleRepository crea una nueva instancia de RoleRepository
+func NewRoleRepository(db *firestore.Client) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// GetRoles devuelve los roles asignados a un Guiver
+func (r *RoleRepository) GetRoles(ctx context.Context, guiverID string) ([]models.Role, error) {
+	var assignment roleAssignment
+	err := r.db.Get(ctx, rolesCollection, guiverID, &assignment)
+	if errors.Is(err, repository.ErrNotFound) {
+		return []models.Role{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.Role{}, assignment.Roles...), nil
+}
+
+// SetRoles reemplaza los roles asignados a un Guiver. Sin roles elimina su
+// documento.
+func (r *RoleRepository) SetRoles(ctx context.Context, guiverID string, roles []models.Role) error {
+	roles, err := models.NormalizeRoles(roles)
+	if err != nil {
+		return err
+	}
+
+	return r.db.RunTransaction(ctx, func(tx *firestore.Tx) error {
+		path := rolesCollection + "/" + guiverID
+		if len(roles) == 0 {
+			return tx.Delete(path)
+		}
+		return tx.Set(path, roleAssignment{GuiverID: guiverID, Roles: roles, UpdatedAt: time.Now()})
+	})
+}
+
+// ListByRole lista los Guivers que tienen el rol
+func (r *RoleRepository) ListByRole(ctx context.Context, role models.Role) ([]string, error) {
+	var assignments []roleAssignment
+	queries := []firestore.Query{firestore.WhereQuery{Field: "roles", Op: "array-contains", Value: string(role)}}
+	if err := r.db.Query(ctx, rolesCollection, queries, &assignments); err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, assignment := range assignments {
+		ids = append(ids, assignment.GuiverID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/infrastructure/firestore"
+	"github.com/guiver/internal/search"
+)
+
+// searchTermsField es el campo donde se guardan los términos del índice de
+// búsqueda de cada documento
+const searchTermsField = "searchTerms"
+
+// searchBatch es la cantidad de candidatos que se leen de Firestore en cada
+// consulta al recorrer los resultados de una búsqueda
+const searchBatch = 500
+
+// searchMaxCandidates limita los candidatos que lee una búsqueda: cada uno es
+// una lectura de Firestore. Las búsquedas con más candidatos solo consideran
+// los más nuevos, por lo que sus resultados, su orden por relevancia y su
+// cuenta pueden quedar incompletos.
+const searchMaxCandidates = 5000
+
+// causeDocument es una Causa tal como se guarda en Firestore, con los
+// términos de búsqueda de su título y descripción
+type causeDocument struct {
+	models.Cause
+	SearchTerms []string `firestore:"searchTerms"`
+	// LegacyUpdates son las actualizaciones que se guardaban dentro de la Causa
+	// antes de tener su propia subcolección. Se conservan hasta MigrateUpdates.
+	LegacyUpdates []models.Update `firestore:"updates,omitempty"`
+}
+
+func newCauseDocument(cause *models.Cause) *causeDocument {
+	return &causeDocument{Cause: *cause, SearchTerms: search.Terms(causeText(cause))}
+}
+
+func causeText(cause *models.Cause) search.Document {
+	return search.Document{Title: cause.Title, Body: cause.Description}
+}
+
+// productDocument es un Producto tal como se guarda en Firestore, con los
+// términos de búsqueda de su título y descripción
+type productDocument struct {
+	models.Product
+	SearchTerms []string `firestore:"searchTerms"`
+}
+
+func newProductDocument(product *models.Product) *productDocument {
+	return &productDocument{Product: *product, SearchTerms: search.Terms(productText(product))}
+}
+
+func productText(product *models.Product) search.Document {
+	return search.Document{Title: product.Title, Body: product.Description}
+}
+
+// searchMatches lee de collection los documentos que cumplen filters y
+// contienen todos los términos de q, del más nuevo al más antiguo y, si se
+// indica, posteriores a after. Firestore solo preselecciona los que contienen
+// alguno de los términos, así que los candidatos se recorren en lotes de
+// searchBatch hasta reunir limit coincidencias o, con limit 0, hasta agotarlos
+// o leer searchMaxCandidates.
+func searchMatches[T any](ctx context.Context, db *firestore.Client, collection string, filters []firestore.Query, q search.Query, text func(T) search.Document, cursor func(T) repository.Cursor, after *repository.Cursor, limit int) ([]T, error) {
+	matches := []T{}
+	for read := 0; ; {
+		queries := append(append([]firestore.Query(nil), filters...),
+			firestore.WhereQuery{Field: searchTermsField, Op: "array-contains-any", Value: q.Terms})
+		queries = append(queries, pageQueries(after, searchBatch, 0)...)
+
+		var batch []T
+		if err := db.Query(ctx, collection, queries, &batch); err != nil {
+			return nil, err
+		}
+		matches = append(matches, search.Filter(batch, q, text)...)
+		if limit > 0 && len(matches) >= limit {
+			return matches[:limit], nil
+		}
+		read += len(batch)
+		if len(batch) < searchBatch || read >= searchMaxCandidates {
+			return matches, nil
+		}
+		last := cursor(batch[len(batch)-1])
+		after = &last
+	}
+}
+
+// searchRanked devuelve la página indicada de los documentos que coinciden con
+// la búsqueda, ordenados por relevancia. Para ordenarlos lee todas las
+// coincidencias, hasta searchMaxCandidates candidatos.
+func searchRanked[T any](ctx context.Context, db *firestore.Client, collection string, filters []firestore.Query, q search.Query, text func(T) search.Document, cursor func(T) repository.Cursor, limit, offset int) ([]T, error) {
+	matches, err := searchMatches(ctx, db, collection, filters, q, text, cursor, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	matches = search.Rank(matches, q, text)
+
+	if offset >= len(matches) {
+		return []T{}, nil
+	}
+	matches = matches[offset:]
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
@@ -0,0 +1,183 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+const commentColumns = `cause_comments.id, cause_comments.guiver_id, cause_comments.content,
+	cause_comments.parent_id, cause_comments.thread_id, cause_comments.mentions,
+	cause_comments.created_at, cause_comments.edited_at`
+
+// AddComment agrega un comentario a una Causa
+func (r *CauseRepository) AddComment(ctx context.Context, causeID string, comment *models.Comment) error {
+	comment.ID = uuid.New().String()
+	comment.CreatedAt = now()
+	comment.EditedAt = nil
+	comment.ThreadID = ""
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := r.db.exec(ctx, tx, `UPDATE causes SET comment_count = comment_count + 1 WHERE id = ?`, causeID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(res); err != nil {
+			return err
+		}
+
+		if comment.ParentID != "" {
+			parent, err := r.getComment(ctx, tx, causeID, comment.ParentID)
+			if err != nil {
+				return err
+			}
+			comment.ThreadID = models.ThreadRoot(parent)
+		}
+
+		_, err = r.db.exec(ctx, tx,
+			`INSERT INTO cause_comments (id, cause_id, guiver_id, content, parent_id, thread_id, mentions, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			comment.ID, causeID, comment.GuiverID, comment.Content, comment.ParentID, comment.ThreadID,
+			stringList{&comment.Mentions}, sqlTime{&comment.CreatedAt})
+		return err
+	})
+}
+
+// GetComment obtiene un comentario de una Causa
+func (r *CauseRepository) GetComment(ctx context.Context, causeID, commentID string) (*models.Comment, error) {
+	return r.getComment(ctx, r.db.db, causeID, commentID)
+}
+
+func (r *CauseRepository) getComment(ctx context.Context, q querier, causeID, commentID string) (*models.Comment, error) {
+	var comment models.Comment
+	err := scanRow(r.db.queryRow(ctx, q,
+		`SELECT `+commentColumns+` FROM cause_comments WHERE cause_comments.cause_id = ? AND cause_comments.id = ?`,
+		causeID, commentID),
+		commentFields(&comment)...)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListComments lista los comentarios principales de una Causa, del más nuevo
+// al más antiguo
+func (r *CauseRepository) ListComments(ctx context.Context, causeID string, filter repository.CommentFilter) ([]*models.Comment, error) {
+	if err := r.requireCause(ctx, r.db.db, causeID); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"cause_comments.cause_id = ?", "cause_comments.parent_id = ''"}
+	args := []interface{}{causeID}
+	if filter.After != nil {
+		cond, condArgs := afterCondition("cause_comments", filter.After)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	query := `SELECT ` + commentColumns + ` FROM cause_comments` + whereClause(conditions) +
+		` ORDER BY cause_comments.created_at DESC, cause_comments.id DESC`
+	query, args = limitOffset(query, args, filter.Limit, 0)
+	return r.queryComments(ctx, r.db.db, query, args...)
+}
+
+// ListReplies lista las respuestas de los hilos indicados, de la más antigua a
+// la más nueva
+func (r *CauseRepository) ListReplies(ctx context.Context, causeID string, threadIDs []string) ([]*models.Comment, error) {
+	if len(threadIDs) == 0 {
+		return []*models.Comment{}, nil
+	}
+	return r.listReplies(ctx, r.db.db, causeID, threadIDs)
+}
+
+func (r *CauseRepository) listReplies(ctx context.Context, q querier, causeID string, threadIDs []string) ([]*models.Comment, error) {
+	args := []interface{}{causeID}
+	for _, id := range threadIDs {
+		args = append(args, id)
+	}
+	return r.queryComments(ctx, q,
+		`SELECT `+commentColumns+` FROM cause_comments
+		WHERE cause_comments.cause_id = ? AND cause_comments.thread_id IN (`+placeholders(len(threadIDs))+`)
+		ORDER BY cause_comments.created_at, cause_comments.id`,
+		args...)
+}
+
+// UpdateComment cambia el contenido y las menciones de un comentario
+func (r *CauseRepository) UpdateComment(ctx context.Context, causeID string, comment *models.Comment) error {
+	editedAt := now()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := r.db.exec(ctx, tx,
+			`UPDATE cause_comments SET content = ?, mentions = ?, edited_at = ? WHERE cause_id = ? AND id = ?`,
+			comment.Content, stringList{&comment.Mentions}, sqlTime{&editedAt}, causeID, comment.ID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(res); err != nil {
+			return err
+		}
+
+		stored, err := r.getComment(ctx, tx, causeID, comment.ID)
+		if err != nil {
+			return err
+		}
+		*comment = *stored
+		return nil
+	})
+}
+
+// DeleteComment elimina un comentario de una Causa junto con sus respuestas
+func (r *CauseRepository) DeleteComment(ctx context.Context, causeID, commentID string) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		comment, err := r.getComment(ctx, tx, causeID, commentID)
+		if err != nil {
+			return err
+		}
+		thread, err := r.listReplies(ctx, tx, causeID, []string{models.ThreadRoot(comment)})
+		if err != nil {
+			return err
+		}
+
+		ids := append([]string{commentID}, models.Descendants(commentID, thread)...)
+		args := []interface{}{causeID}
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		_, err = r.db.exec(ctx, tx,
+			`DELETE FROM cause_comments WHERE cause_id = ? AND id IN (`+placeholders(len(ids))+`)`, args...)
+		if err != nil {
+			return err
+		}
+		_, err = r.db.exec(ctx, tx,
+			`UPDATE causes SET comment_count = CASE WHEN comment_count > ? THEN comment_count - ? ELSE 0 END WHERE id = ?`,
+			len(ids), len(ids), causeID)
+		return err
+	})
+}
+
+func (r *CauseRepository) queryComments(ctx context.Context, q querier, query string, args ...interface{}) ([]*models.Comment, error) {
+	rows, err := r.db.query(ctx, q, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*models.Comment{}
+	for rows.Next() {
+		var comment models.Comment
+		if err := rows.Scan(commentFields(&comment)...); err != nil {
+			return nil, err
+		}
+		comments = append(comments, &comment)
+	}
+	return comments, rows.Err()
+}
+
+// commentFields son los destinos de Scan para commentColumns
+func commentFields(comment *models.Comment) []interface{} {
+	return []interface{}{&comment.ID, &comment.GuiverID, &comment.Content,
+		&comment.ParentID, &comment.ThreadID, stringList{&comment.Mentions},
+		sqlTime{&comment.CreatedAt}, nullTime{&comment.EditedAt}}
+}
@@ -0,0 +1,421 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+const causeColumns = `causes.id, causes.guiver_id, causes.title, causes.description, causes.type,
+	causes.image_urls, causes.status, causes.location, causes.contact_whatsapp,
+	causes.contact_instagram, causes.contact_email, causes.collaborators, causes.goal_amount,
+	causes.currency, causes.deadline, causes.raised_amount, causes.backer_count, causes.likes,
+	causes.comment_count, causes.update_count, causes.pinned_update_id, causes.created_at,
+	causes.updated_at`
+
+// CauseRepository implementa el repositorio de Causas sobre SQL
+type CauseRepository struct {
+	db *DB
+}
+
+// NewCauseRepository crea una nueva instancia de CauseRepository
+func NewCauseRepository(db *DB) *CauseRepository {
+	return &CauseRepository{db: db}
+}
+
+// Create crea una nueva Causa
+func (r *CauseRepository) Create(ctx context.Context, cause *models.Cause) error {
+	if cause.ID == "" {
+		cause.ID = uuid.New().String()
+	}
+
+	now := now()
+	cause.CreatedAt = now
+	cause.UpdatedAt = now
+	cause.Likes = 0
+	cause.CommentCount = 0
+	cause.UpdateCount = len(cause.Updates)
+	cause.PinnedUpdateID = ""
+	cause.RaisedAmount = models.NewMoney(0, cause.GoalAmount.Currency)
+	cause.BackerCount = 0
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := r.db.exec(ctx, tx,
+			`INSERT INTO causes (id, guiver_id, title, description, type, image_urls, status, location,
+				contact_whatsapp, contact_instagram, contact_email, collaborators, goal_amount, currency,
+				deadline, likes, update_count, created_at, updated_at, search_title, search_body)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			cause.ID, cause.GuiverID, cause.Title, cause.Description, cause.Type,
+			stringList{&cause.ImageURLs}, cause.Status, cause.Location, cause.ContactInfo.WhatsApp,
+			cause.ContactInfo.Instagram, cause.ContactInfo.Email, stringList{&cause.Collaborators},
+			cause.GoalAmount.Amount, cause.GoalAmount.Currency, nullTime{&cause.Deadline}, cause.Likes, cause.UpdateCount,
+			sqlTime{&cause.CreatedAt}, sqlTime{&cause.UpdatedAt},
+			searchText(cause.Title), searchText(cause.Description))
+		if err != nil {
+			return err
+		}
+
+		for i := range cause.Updates {
+			update := &cause.Updates[i]
+			if update.ID == "" {
+				update.ID = uuid.New().String()
+			}
+			if update.CreatedAt.IsZero() {
+				update.CreatedAt = now
+			}
+			if err := r.insertUpdate(ctx, tx, cause.ID, update); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByID obtiene una Causa por su ID con sus actualizaciones
+func (r *CauseRepository) GetByID(ctx context.Context, id string) (*models.Cause, error) {
+	cause, err := r.GetSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadUpdates(ctx, cause); err != nil {
+		return nil, err
+	}
+	return cause, nil
+}
+
+// GetSummary obtiene una Causa por su ID, sin sus actualizaciones
+func (r *CauseRepository) GetSummary(ctx context.Context, id string) (*models.Cause, error) {
+	causes, err := r.queryCauses(ctx, `SELECT `+causeColumns+` FROM causes WHERE causes.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(causes) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return causes[0], nil
+}
+
+// GetByGuiverID obtiene las Causas de un Guiver
+func (r *CauseRepository) GetByGuiverID(ctx context.Context, guiverID string) ([]*models.Cause, error) {
+	return r.queryCauses(ctx,
+		`SELECT `+causeColumns+` FROM causes WHERE causes.guiver_id = ?
+		ORDER BY causes.created_at DESC, causes.id DESC`, guiverID)
+}
+
+// Update actualiza una Causa, sin modificar sus contadores ni actualizaciones
+func (r *CauseRepository) Update(ctx context.Context, cause *models.Cause) error {
+	cause.UpdatedAt = now()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		var currency string
+		if err := scanRow(r.db.queryRow(ctx, tx, `SELECT currency FROM causes WHERE id = ?`, cause.ID), &currency); err != nil {
+			return err
+		}
+		if err := r.update(ctx, tx, cause); err != nil {
+			return err
+		}
+		// La fila de la Causa queda bloqueada por el UPDATE, así que lo
+		// recaudado no cambia hasta el final de tx. Lo recaudado y lo que se
+		// está cobrando están en la moneda anterior.
+		if currency == cause.GoalAmount.Currency {
+			return nil
+		}
+		pending, err := r.hasPledges(ctx, tx, cause.ID, models.PledgeStatusPending)
+		if err != nil {
+			return err
+		}
+		if pending || !cause.RaisedAmount.IsZero() {
+			return repository.ErrConflict
+		}
+		return nil
+	})
+}
+
+// update escribe los campos editables de la Causa y lee los demás
+func (r *CauseRepository) update(ctx context.Context, tx *sql.Tx, cause *models.Cause) error {
+	res, err := r.db.exec(ctx, tx,
+		`UPDATE causes SET guiver_id = ?, title = ?, description = ?, type = ?, image_urls = ?,
+			status = ?, location = ?, contact_whatsapp = ?, contact_instagram = ?, contact_email = ?,
+			collaborators = ?, goal_amount = ?, currency = ?, deadline = ?, updated_at = ?,
+			search_title = ?, search_body = ?
+		WHERE id = ?`,
+		cause.GuiverID, cause.Title, cause.Description, cause.Type, stringList{&cause.ImageURLs},
+		cause.Status, cause.Location, cause.ContactInfo.WhatsApp, cause.ContactInfo.Instagram,
+		cause.ContactInfo.Email, stringList{&cause.Collaborators}, cause.GoalAmount.Amount,
+		cause.GoalAmount.Currency, nullTime{&cause.Deadline}, sqlTime{&cause.UpdatedAt},
+		searchText(cause.Title), searchText(cause.Description), cause.ID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res); err != nil {
+		return err
+	}
+	// Los contadores, los totales recaudados y la actualización fijada no se
+	// escriben: se devuelven los valores guardados
+	cause.RaisedAmount.Currency = cause.GoalAmount.Currency
+	return scanRow(r.db.queryRow(ctx, tx,
+		`SELECT likes, comment_count, update_count, pinned_update_id, raised_amount, backer_count
+		FROM causes WHERE id = ?`, cause.ID),
+		&cause.Likes, &cause.CommentCount, &cause.UpdateCount, &cause.PinnedUpdateID,
+		&cause.RaisedAmount.Amount, &cause.BackerCount)
+}
+
+// Delete elimina una Causa junto con sus actualizaciones, comentarios, likes
+// y aportes, salvo que tenga aportes abiertos
+func (r *CauseRepository) Delete(ctx context.Context, id string) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		open, err := r.hasPledges(ctx, tx, id, models.OpenPledgeStatuses...)
+		if err != nil {
+			return err
+		}
+		if open {
+			return repository.ErrConflict
+		}
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM cause_pledges WHERE cause_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM cause_update_expenses WHERE cause_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM cause_updates WHERE cause_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM cause_comments WHERE cause_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM cause_likes WHERE cause_id = ?`, id); err != nil {
+			return err
+		}
+		res, err := r.db.exec(ctx, tx, `DELETE FROM causes WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		return requireAffected(res)
+	})
+}
+
+// hasPledges indica si la Causa tiene aportes en alguno de los estados
+// indicados. Bloquea sus aportes hasta el final de tx para que no se registren
+// otros mientras tanto.
+func (r *CauseRepository) hasPledges(ctx context.Context, tx *sql.Tx, causeID string, statuses ...models.PledgeStatus) (bool, error) {
+	if err := r.db.lock(ctx, tx, pledgesLock(causeID)); err != nil {
+		return false, err
+	}
+	args := []interface{}{causeID}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+	var count int
+	err := scanRow(r.db.queryRow(ctx, tx,
+		`SELECT COUNT(*) FROM cause_pledges WHERE cause_id = ? AND status IN (`+placeholders(len(statuses))+`)`,
+		args...), &count)
+	return count > 0, err
+}
+
+// List lista las Causas según los filtros. Con búsqueda se ordenan por
+// relevancia, salvo al paginar por cursor, que conserva el orden por fecha.
+func (r *CauseRepository) List(ctx context.Context, filter repository.CauseFilter) ([]*models.Cause, error) {
+	conditions, args := r.filterConditions(filter)
+	offset := filter.Offset
+	if filter.After != nil {
+		cond, condArgs := afterCondition("causes", filter.After)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+		offset = 0
+	}
+
+	from := ` FROM causes`
+	order := ` ORDER BY causes.created_at DESC, causes.id DESC`
+	if search, ok := r.db.searchClause("causes", filter.Search); ok {
+		from += search.join
+		conditions = append(conditions, search.cond)
+		args = append(args, search.args...)
+		if filter.After == nil {
+			order = ` ORDER BY ` + search.order + `, causes.created_at DESC, causes.id DESC`
+			args = append(args, search.orderArgs...)
+		}
+	}
+
+	query := `SELECT ` + causeColumns + from + whereClause(conditions) + order
+	query, args = limitOffset(query, args, filter.Limit, offset)
+
+	return r.queryCauses(ctx, query, args...)
+}
+
+// Count cuenta las Causas que cumplen los filtros, ignorando Limit y Offset
+func (r *CauseRepository) Count(ctx context.Context, filter repository.CauseFilter) (int64, error) {
+	conditions, args := r.filterConditions(filter)
+	from := ` FROM causes`
+	if search, ok := r.db.searchClause("causes", filter.Search); ok {
+		from += search.join
+		conditions = append(conditions, search.cond)
+		args = append(args, search.args...)
+	}
+
+	var total int64
+	err := scanRow(r.db.queryRow(ctx, r.db.db, `SELECT COUNT(*)`+from+whereClause(conditions), args...), &total)
+	return total, err
+}
+
+// filterConditions construye las condiciones WHERE de un CauseFilter
+func (r *CauseRepository) filterConditions(filter repository.CauseFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Type != "" {
+		conditions = append(conditions, "causes.type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "causes.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Location != "" {
+		conditions = append(conditions, "causes.location = ?")
+		args = append(args, filter.Location)
+	}
+	return conditions, args
+}
+
+// Like registra el like del Guiver en la Causa, una sola vez por Guiver
+func (r *CauseRepository) Like(ctx context.Context, causeID, guiverID string) (int, error) {
+	createdAt := now()
+	return r.setLike(ctx, causeID, `UPDATE causes SET likes = likes + 1 WHERE id = ?`,
+		func(tx *sql.Tx) (sql.Result, error) {
+			return r.db.exec(ctx, tx,
+				`INSERT INTO cause_likes (cause_id, guiver_id, created_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+				causeID, guiverID, sqlTime{&createdAt})
+		})
+}
+
+// Unlike quita el like del Guiver en la Causa, si existía
+func (r *CauseRepository) Unlike(ctx context.Context, causeID, guiverID string) (int, error) {
+	return r.setLike(ctx, causeID, `UPDATE causes SET likes = CASE WHEN likes > 0 THEN likes - 1 ELSE 0 END WHERE id = ?`,
+		func(tx *sql.Tx) (sql.Result, error) {
+			return r.db.exec(ctx, tx, `DELETE FROM cause_likes WHERE cause_id = ? AND guiver_id = ?`, causeID, guiverID)
+		})
+}
+
+// setLike aplica change sobre cause_likes y, solo si modificó una fila,
+// ajusta el contador con count en la misma transacción
+func (r *CauseRepository) setLike(ctx context.Context, causeID, count string, change func(tx *sql.Tx) (sql.Result, error)) (int, error) {
+	var likes int
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if err := r.requireCause(ctx, tx, causeID); err != nil {
+			return err
+		}
+
+		res, err := change(tx)
+		if err != nil {
+			return err
+		}
+		changed, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if changed > 0 {
+			if _, err := r.db.exec(ctx, tx, count, causeID); err != nil {
+				return err
+			}
+		}
+		return scanRow(r.db.queryRow(ctx, tx, `SELECT likes FROM causes WHERE id = ?`, causeID), &likes)
+	})
+	return likes, err
+}
+
+// LikedBy indica cuáles de las Causas indicadas le gustan al Guiver
+func (r *CauseRepository) LikedBy(ctx context.Context, guiverID string, causeIDs []string) (map[string]bool, error) {
+	liked := make(map[string]bool)
+	if len(causeIDs) == 0 {
+		return liked, nil
+	}
+
+	args := []interface{}{guiverID}
+	for _, id := range causeIDs {
+		args = append(args, id)
+	}
+	rows, err := r.db.query(ctx, r.db.db,
+		`SELECT cause_id FROM cause_likes WHERE guiver_id = ? AND cause_id IN (`+placeholders(len(causeIDs))+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		liked[id] = true
+	}
+	return liked, rows.Err()
+}
+
+// requireCause devuelve repository.ErrNotFound si la Causa no existe
+func (r *CauseRepository) requireCause(ctx context.Context, q querier, causeID string) error {
+	var exists int
+	return scanRow(r.db.queryRow(ctx, q, `SELECT 1 FROM causes WHERE id = ?`, causeID), &exists)
+}
+
+// queryCauses ejecuta la consulta y devuelve las Causas sin sus actualizaciones
+func (r *CauseRepository) queryCauses(ctx context.Context, query string, args ...interface{}) ([]*models.Cause, error) {
+	rows, err := r.db.query(ctx, r.db.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	causes := []*models.Cause{}
+	for rows.Next() {
+		var cause models.Cause
+		err := rows.Scan(&cause.ID, &cause.GuiverID, &cause.Title, &cause.Description, &cause.Type,
+			stringList{&cause.ImageURLs}, &cause.Status, &cause.Location, &cause.ContactInfo.WhatsApp,
+			&cause.ContactInfo.Instagram, &cause.ContactInfo.Email, stringList{&cause.Collaborators},
+			&cause.GoalAmount.Amount, &cause.GoalAmount.Currency, nullTime{&cause.Deadline}, &cause.RaisedAmount.Amount,
+			&cause.BackerCount, &cause.Likes, &cause.CommentCount, &cause.UpdateCount, &cause.PinnedUpdateID,
+			sqlTime{&cause.CreatedAt}, sqlTime{&cause.UpdatedAt})
+		if err != nil {
+			return nil, err
+		}
+		cause.RaisedAmount.Currency = cause.GoalAmount.Currency
+		causes = append(causes, &cause)
+	}
+	return causes, rows.Err()
+}
+
+// whereClause une las condiciones con AND
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND ")
+}
+
+// afterCondition selecciona las filas posteriores al cursor en el orden
+// created_at DESC, id DESC
+func afterCondition(table string, cursor *repository.Cursor) (string, []interface{}) {
+	createdAt := cursor.CreatedAt
+	return "(" + table + ".created_at < ? OR (" + table + ".created_at = ? AND " + table + ".id < ?))",
+		[]interface{}{sqlTime{&createdAt}, sqlTime{&createdAt}, cursor.ID}
+}
+
+// limitOffset agrega LIMIT y OFFSET a la consulta cuando corresponde
+func limitOffset(query string, args []interface{}, limit, offset int) (string, []interface{}) {
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	} else if offset > 0 {
+		// Ambos motores aceptan un límite muy alto para expresar "sin límite"
+		query += ` LIMIT ?`
+		args = append(args, int64(1)<<62)
+	}
+	if offset > 0 {
+		query += ` OFFSET ?`
+		args = append(args, offset)
+	}
+	return query, args
+}
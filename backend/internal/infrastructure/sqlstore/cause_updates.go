@@ -0,0 +1,270 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+const updateColumns = `cause_updates.id, cause_updates.guiver_id, cause_updates.content,
+	cause_updates.image_urls, cause_updates.created_at, cause_updates.edited_at`
+
+// AddUpdate agrega una actualización a una Causa
+func (r *CauseRepository) AddUpdate(ctx context.Context, causeID string, update *models.Update) error {
+	update.ID = uuid.New().String()
+	update.CreatedAt = now()
+	update.EditedAt = nil
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := r.db.exec(ctx, tx, `UPDATE causes SET update_count = update_count + 1, updated_at = ? WHERE id = ?`,
+			sqlTime{&update.CreatedAt}, causeID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(res); err != nil {
+			return err
+		}
+		return r.insertUpdate(ctx, tx, causeID, update)
+	})
+}
+
+// GetUpdate obtiene una actualización de una Causa
+func (r *CauseRepository) GetUpdate(ctx context.Context, causeID, updateID string) (*models.Update, error) {
+	return r.getUpdate(ctx, r.db.db, causeID, updateID)
+}
+
+func (r *CauseRepository) getUpdate(ctx context.Context, q querier, causeID, updateID string) (*models.Update, error) {
+	var update models.Update
+	err := scanRow(r.db.queryRow(ctx, q,
+		`SELECT `+updateColumns+` FROM cause_updates WHERE cause_updates.cause_id = ? AND cause_updates.id = ?`,
+		causeID, updateID),
+		updateFields(&update)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadExpenses(ctx, q, []*models.Update{&update}); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// ListUpdates lista las actualizaciones de una Causa, de la más nueva a la más
+// antigua
+func (r *CauseRepository) ListUpdates(ctx context.Context, causeID string, filter repository.UpdateFilter) ([]*models.Update, error) {
+	if err := r.requireCause(ctx, r.db.db, causeID); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"cause_updates.cause_id = ?"}
+	args := []interface{}{causeID}
+	if filter.After != nil {
+		cond, condArgs := afterCondition("cause_updates", filter.After)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	query := `SELECT ` + updateColumns + ` FROM cause_updates` + whereClause(conditions) +
+		` ORDER BY cause_updates.created_at DESC, cause_updates.id DESC`
+	query, args = limitOffset(query, args, filter.Limit, 0)
+	return r.queryUpdates(ctx, query, args...)
+}
+
+// EditUpdate cambia el contenido, las imágenes y los gastos de una actualización
+func (r *CauseRepository) EditUpdate(ctx context.Context, causeID string, update *models.Update) error {
+	editedAt := now()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := r.db.exec(ctx, tx,
+			`UPDATE cause_updates SET content = ?, image_urls = ?, edited_at = ? WHERE cause_id = ? AND id = ?`,
+			update.Content, stringList{&update.ImageURLs}, sqlTime{&editedAt}, causeID, update.ID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(res); err != nil {
+			return err
+		}
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM cause_update_expenses WHERE update_id = ?`, update.ID); err != nil {
+			return err
+		}
+		if err := r.insertExpenses(ctx, tx, causeID, update); err != nil {
+			return err
+		}
+
+		stored, err := r.getUpdate(ctx, tx, causeID, update.ID)
+		if err != nil {
+			return err
+		}
+		*update = *stored
+		return nil
+	})
+}
+
+// DeleteUpdate elimina una actualización de una Causa y la desfija si estaba fijada
+func (r *CauseRepository) DeleteUpdate(ctx context.Context, causeID, updateID string) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := r.db.exec(ctx, tx, `DELETE FROM cause_update_expenses WHERE cause_id = ? AND update_id = ?`,
+			causeID, updateID)
+		if err != nil {
+			return err
+		}
+		res, err := r.db.exec(ctx, tx, `DELETE FROM cause_updates WHERE cause_id = ? AND id = ?`, causeID, updateID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(res); err != nil {
+			return err
+		}
+		_, err = r.db.exec(ctx, tx,
+			`UPDATE causes SET
+				update_count = CASE WHEN update_count > 0 THEN update_count - 1 ELSE 0 END,
+				pinned_update_id = CASE WHEN pinned_update_id = ? THEN '' ELSE pinned_update_id END
+			WHERE id = ?`,
+			updateID, causeID)
+		return err
+	})
+}
+
+// PinUpdate fija una actualización de la Causa o, con updateID vacío, desfija
+// la actual
+func (r *CauseRepository) PinUpdate(ctx context.Context, causeID, updateID string) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if updateID != "" {
+			if _, err := r.getUpdate(ctx, tx, causeID, updateID); err != nil {
+				return err
+			}
+		}
+		res, err := r.db.exec(ctx, tx, `UPDATE causes SET pinned_update_id = ? WHERE id = ?`, updateID, causeID)
+		if err != nil {
+			return err
+		}
+		return requireAffected(res)
+	})
+}
+
+// loadUpdates completa las actualizaciones de la Causa, de la más antigua a la
+// más nueva
+func (r *CauseRepository) loadUpdates(ctx context.Context, cause *models.Cause) error {
+	updates, err := r.queryUpdates(ctx,
+		`SELECT `+updateColumns+` FROM cause_updates WHERE cause_updates.cause_id = ?
+		ORDER BY cause_updates.created_at, cause_updates.id`, cause.ID)
+	if err != nil {
+		return err
+	}
+	cause.Updates = nil
+	for _, update := range updates {
+		cause.Updates = append(cause.Updates, *update)
+	}
+	return nil
+}
+
+func (r *CauseRepository) queryUpdates(ctx context.Context, query string, args ...interface{}) ([]*models.Update, error) {
+	rows, err := r.db.query(ctx, r.db.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := []*models.Update{}
+	for rows.Next() {
+		var update models.Update
+		if err := rows.Scan(updateFields(&update)...); err != nil {
+			return nil, err
+		}
+		updates = append(updates, &update)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	return updates, r.loadExpenses(ctx, r.db.db, updates)
+}
+
+// ExpenseTotals suma los gastos de las actualizaciones de una Causa por moneda
+// y categoría
+func (r *CauseRepository) ExpenseTotals(ctx context.Context, causeID string) ([]models.ExpenseTotal, error) {
+	rows, err := r.db.query(ctx, r.db.db,
+		`SELECT currency, category, SUM(amount), COUNT(*) FROM cause_update_expenses WHERE cause_id = ?
+		GROUP BY currency, category ORDER BY currency, category`, causeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []models.ExpenseTotal{}
+	for rows.Next() {
+		var total models.ExpenseTotal
+		if err := rows.Scan(&total.Currency, &total.Category, &total.Amount, &total.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+	return totals, rows.Err()
+}
+
+// loadExpenses completa los gastos de las actualizaciones indicadas
+func (r *CauseRepository) loadExpenses(ctx context.Context, q querier, updates []*models.Update) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.Update, len(updates))
+	ids := make([]interface{}, 0, len(updates))
+	for _, update := range updates {
+		update.Expenses = nil
+		byID[update.ID] = update
+		ids = append(ids, update.ID)
+	}
+
+	rows, err := r.db.query(ctx, q,
+		`SELECT update_id, concept, category, amount, currency, receipt_url, spent_at FROM cause_update_expenses
+		WHERE update_id IN (`+placeholders(len(ids))+`) ORDER BY update_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var updateID string
+		var expense models.Expense
+		if err := rows.Scan(&updateID, &expense.Concept, &expense.Category, &expense.Amount.Amount,
+			&expense.Amount.Currency, &expense.ReceiptURL, sqlTime{&expense.Date}); err != nil {
+			return err
+		}
+		update := byID[updateID]
+		update.Expenses = append(update.Expenses, expense)
+	}
+	return rows.Err()
+}
+
+// insertExpenses guarda los gastos de la actualización en orden
+func (r *CauseRepository) insertExpenses(ctx context.Context, tx *sql.Tx, causeID string, update *models.Update) error {
+	for i, expense := range update.Expenses {
+		_, err := r.db.exec(ctx, tx,
+			`INSERT INTO cause_update_expenses (update_id, position, cause_id, concept, category, amount, currency,
+				receipt_url, spent_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			update.ID, i, causeID, expense.Concept, expense.Category, expense.Amount.Amount, expense.Amount.Currency,
+			expense.ReceiptURL, sqlTime{&expense.Date})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *CauseRepository) insertUpdate(ctx context.Context, tx *sql.Tx, causeID string, update *models.Update) error {
+	_, err := r.db.exec(ctx, tx,
+		`INSERT INTO cause_updates (id, cause_id, guiver_id, content, image_urls, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		update.ID, causeID, update.GuiverID, update.Content, stringList{&update.ImageURLs}, sqlTime{&update.CreatedAt})
+	if err != nil {
+		return err
+	}
+	return r.insertExpenses(ctx, tx, causeID, update)
+}
+
+// updateFields son los destinos de Scan para updateColumns
+func updateFields(update *models.Update) []interface{} {
+	return []interface{}{&update.ID, &update.GuiverID, &update.Content,
+		stringList{&update.ImageURLs}, sqlTime{&update.CreatedAt}, nullTime{&update.EditedAt}}
+}
@@ -0,0 +1,59 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/guiver/internal/domain/repository"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// translateError convierte los errores de los drivers en los errores del dominio
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if domainErr := classify(err); domainErr != nil {
+		return fmt.Errorf("%w: %v", domainErr, err)
+	}
+	return err
+}
+
+func classify(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_CONSTRAINT:
+			return repository.ErrConflict
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED, sqlite3.SQLITE_CANTOPEN:
+			return repository.ErrUnavailable
+		case sqlite3.SQLITE_PERM, sqlite3.SQLITE_READONLY, sqlite3.SQLITE_AUTH:
+			return repository.ErrPermission
+		}
+		return nil
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505", pqErr.Code == "40001", pqErr.Code == "40P01":
+			return repository.ErrConflict
+		case pqErr.Code == "42501", pqErr.Code.Class() == "28":
+			return repository.ErrPermission
+		case pqErr.Code.Class() == "08", pqErr.Code.Class() == "53", pqErr.Code.Class() == "57":
+			return repository.ErrUnavailable
+		}
+		return nil
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return repository.ErrUnavailable
+	}
+	return nil
+}
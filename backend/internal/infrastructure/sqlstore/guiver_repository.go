@@ -0,0 +1,73 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+)
+
+const guiverColumns = `id, email, display_name, photo_url, type, bio, whatsapp, instagram, created_at, updated_at`
+
+// GuiverRepository implementa el repositorio de Guivers sobre SQL
+type GuiverRepository struct {
+	db *DB
+}
+
+// NewGuiverRepository crea una nueva instancia de GuiverRepository
+func NewGuiverRepository(db *DB) *GuiverRepository {
+	return &GuiverRepository{db: db}
+}
+
+// Create crea un nuevo Guiver
+func (r *GuiverRepository) Create(ctx context.Context, guiver *models.Guiver) error {
+	if guiver.ID == "" {
+		guiver.ID = uuid.New().String()
+	}
+
+	now := now()
+	guiver.CreatedAt = now
+	guiver.UpdatedAt = now
+
+	_, err := r.db.exec(ctx, r.db.db,
+		`INSERT INTO guivers (`+guiverColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		guiver.ID, guiver.Email, guiver.DisplayName, guiver.PhotoURL, guiver.Type, guiver.Bio,
+		guiver.WhatsApp, guiver.Instagram, sqlTime{&guiver.CreatedAt}, sqlTime{&guiver.UpdatedAt})
+	return err
+}
+
+// GetByID obtiene un Guiver por su ID
+func (r *GuiverRepository) GetByID(ctx context.Context, id string) (*models.Guiver, error) {
+	var guiver models.Guiver
+	row := r.db.queryRow(ctx, r.db.db, `SELECT `+guiverColumns+` FROM guivers WHERE id = ?`, id)
+	err := scanRow(row, &guiver.ID, &guiver.Email, &guiver.DisplayName, &guiver.PhotoURL, &guiver.Type,
+		&guiver.Bio, &guiver.WhatsApp, &guiver.Instagram, sqlTime{&guiver.CreatedAt}, sqlTime{&guiver.UpdatedAt})
+	if err != nil {
+		return nil, err
+	}
+	return &guiver, nil
+}
+
+// Update actualiza un Guiver
+func (r *GuiverRepository) Update(ctx context.Context, guiver *models.Guiver) error {
+	guiver.UpdatedAt = now()
+	res, err := r.db.exec(ctx, r.db.db,
+		`UPDATE guivers SET email = ?, display_name = ?, photo_url = ?, type = ?, bio = ?,
+			whatsapp = ?, instagram = ?, updated_at = ?
+		WHERE id = ?`,
+		guiver.Email, guiver.DisplayName, guiver.PhotoURL, guiver.Type, guiver.Bio,
+		guiver.WhatsApp, guiver.Instagram, sqlTime{&guiver.UpdatedAt}, guiver.ID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// Delete elimina un Guiver
+func (r *GuiverRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.exec(ctx, r.db.db, `DELETE FROM guivers WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
@@ -0,0 +1,229 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+const ledgerColumns = `ledger_entries.id, ledger_entries.cause_id, ledger_entries.type, ledger_entries.amount,
+	ledger_entries.currency, ledger_entries.description, ledger_entries.reference, ledger_entries.created_by,
+	ledger_entries.guiver_id, ledger_entries.created_at`
+
+// LedgerRepository implementa el libro de las causas sobre SQL. Las
+// imputaciones de cada movimiento se guardan en ledger_postings.
+type LedgerRepository struct {
+	db *DB
+}
+
+// NewLedgerRepository crea una nueva instancia de LedgerRepository
+func NewLedgerRepository(db *DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Append registra un movimiento junto con sus imputaciones
+func (r *LedgerRepository) Append(ctx context.Context, entry *models.LedgerEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	entry.CreatedAt = now()
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if account, ok := entry.FundingAccount(); ok {
+			if err := r.db.lock(ctx, tx, "ledger:"+entry.CauseID); err != nil {
+				return err
+			}
+			var balance int64
+			err := scanRow(r.db.queryRow(ctx, tx,
+				`SELECT COALESCE(SUM(amount), 0) FROM ledger_postings WHERE cause_id = ? AND currency = ? AND account = ?`,
+				entry.CauseID, entry.Amount.Currency, account), &balance)
+			if err != nil {
+				return err
+			}
+			if balance < entry.Amount.Amount {
+				return repository.ErrInsufficientFunds
+			}
+		}
+
+		_, err := r.db.exec(ctx, tx,
+			`INSERT INTO ledger_entries (id, cause_id, type, amount, currency, description, reference, created_by,
+				guiver_id, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			entry.ID, entry.CauseID, entry.Type, entry.Amount.Amount, entry.Amount.Currency, entry.Description, entry.Reference,
+			entry.CreatedBy, entry.GuiverID, sqlTime{&entry.CreatedAt})
+		if err != nil {
+			return err
+		}
+		for i, posting := range entry.Postings {
+			_, err := r.db.exec(ctx, tx,
+				`INSERT INTO ledger_postings (entry_id, position, cause_id, account, amount, currency)
+				VALUES (?, ?, ?, ?, ?, ?)`,
+				entry.ID, i, entry.CauseID, posting.Account, posting.Amount, entry.Amount.Currency)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListByCause lista los movimientos de una Causa, del más nuevo al más antiguo
+func (r *LedgerRepository) ListByCause(ctx context.Context, causeID string, filter repository.LedgerFilter) ([]*models.LedgerEntry, error) {
+	conditions := []string{"ledger_entries.cause_id = ?"}
+	args := []interface{}{causeID}
+	if filter.Type != "" {
+		conditions = append(conditions, "ledger_entries.type = ?")
+		args = append(args, filter.Type)
+	}
+	return r.list(ctx, conditions, args, filter.After, filter.Limit)
+}
+
+// ListDonations lista los movimientos de las donaciones de los pedidos, del
+// más nuevo al más antiguo
+func (r *LedgerRepository) ListDonations(ctx context.Context, filter repository.DonationFilter) ([]*models.LedgerEntry, error) {
+	conditions, args := donationConditions(filter)
+	return r.list(ctx, conditions, args, filter.After, filter.Limit)
+}
+
+// DonationTotals suma por moneda lo donado por los pedidos que cumplen el
+// filtro, neto de reembolsos
+func (r *LedgerRepository) DonationTotals(ctx context.Context, filter repository.DonationFilter) (map[string]int64, error) {
+	conditions, args := donationConditions(filter)
+	args = append([]interface{}{models.LedgerEntryProductRefund}, args...)
+	rows, err := r.db.query(ctx, r.db.db,
+		`SELECT ledger_entries.currency,
+			SUM(CASE WHEN ledger_entries.type = ? THEN -ledger_entries.amount ELSE ledger_entries.amount END)
+		FROM ledger_entries`+whereClause(conditions)+` GROUP BY ledger_entries.currency`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var currency string
+		var total int64
+		if err := rows.Scan(&currency, &total); err != nil {
+			return nil, err
+		}
+		totals[currency] = total
+	}
+	return totals, rows.Err()
+}
+
+// list lista los movimientos que cumplen las condiciones, del más nuevo al más
+// antiguo, con sus imputaciones
+func (r *LedgerRepository) list(ctx context.Context, conditions []string, args []interface{}, after *repository.Cursor, limit int) ([]*models.LedgerEntry, error) {
+	if after != nil {
+		cond, condArgs := afterCondition("ledger_entries", after)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	query := `SELECT ` + ledgerColumns + ` FROM ledger_entries` + whereClause(conditions) +
+		` ORDER BY ledger_entries.created_at DESC, ledger_entries.id DESC`
+	query, args = limitOffset(query, args, limit, 0)
+
+	rows, err := r.db.query(ctx, r.db.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.LedgerEntry{}
+	byID := make(map[string]*models.LedgerEntry)
+	for rows.Next() {
+		var entry models.LedgerEntry
+		if err := rows.Scan(&entry.ID, &entry.CauseID, &entry.Type, &entry.Amount.Amount, &entry.Amount.Currency,
+			&entry.Description, &entry.Reference, &entry.CreatedBy, &entry.GuiverID, sqlTime{&entry.CreatedAt}); err != nil {
+			return nil, err
+		}
+		entry.Postings = []models.Posting{}
+		entries = append(entries, &entry)
+		byID[entry.ID] = &entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	return entries, r.loadPostings(ctx, byID)
+}
+
+// loadPostings completa las imputaciones de los movimientos indicados
+func (r *LedgerRepository) loadPostings(ctx context.Context, entries map[string]*models.LedgerEntry) error {
+	ids := make([]interface{}, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	rows, err := r.db.query(ctx, r.db.db,
+		`SELECT entry_id, account, amount FROM ledger_postings WHERE entry_id IN (`+placeholders(len(ids))+`)
+		ORDER BY entry_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entryID string
+		var posting models.Posting
+		if err := rows.Scan(&entryID, &posting.Account, &posting.Amount); err != nil {
+			return err
+		}
+		entry := entries[entryID]
+		entry.Postings = append(entry.Postings, posting)
+	}
+	return rows.Err()
+}
+
+// Balances calcula los saldos de las cuentas de una Causa
+func (r *LedgerRepository) Balances(ctx context.Context, causeID string) (models.LedgerBalances, error) {
+	rows, err := r.db.query(ctx, r.db.db,
+		`SELECT currency, account, SUM(amount) FROM ledger_postings WHERE cause_id = ? GROUP BY currency, account`,
+		causeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := models.LedgerBalances{}
+	for rows.Next() {
+		var currency string
+		var account models.LedgerAccount
+		var amount int64
+		if err := rows.Scan(&currency, &account, &amount); err != nil {
+			return nil, err
+		}
+		if balances[currency] == nil {
+			balances[currency] = make(map[models.LedgerAccount]int64)
+		}
+		balances[currency][account] = amount
+	}
+	return balances, rows.Err()
+}
+
+// donationConditions construye las condiciones where de un DonationFilter,
+// sin el cursor
+func donationConditions(filter repository.DonationFilter) ([]string, []interface{}) {
+	conditions := []string{"ledger_entries.type IN (" + placeholders(len(models.ProductDonationTypes)) + ")"}
+	var args []interface{}
+	for _, entryType := range models.ProductDonationTypes {
+		args = append(args, entryType)
+	}
+	if filter.CauseID != "" {
+		conditions = append(conditions, "ledger_entries.cause_id = ?")
+		args = append(args, filter.CauseID)
+	}
+	if filter.GuiverID != "" {
+		conditions = append(conditions, "ledger_entries.guiver_id = ?")
+		args = append(args, filter.GuiverID)
+	}
+	return conditions, args
+}
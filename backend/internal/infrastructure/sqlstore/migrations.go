@@ -0,0 +1,481 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migration es un cambio de esquema versionado. Las sentencias se eligen
+// según el dialecto; si un dialecto no tiene sentencias propias se usan las
+// comunes. Las funciones de backfill completan los datos que no se pueden
+// calcular en SQL y se ejecutan después de las sentencias, en la misma
+// transacción.
+type migration struct {
+	version  int
+	common   []string
+	sqlite   []string
+	postgres []string
+	backfill []func(ctx context.Context, d *DB, tx *sql.Tx) error
+}
+
+func (m migration) statements(dialect Dialect) []string {
+	stmts := append([]string(nil), m.common...)
+	switch dialect {
+	case DialectSQLite:
+		stmts = append(stmts, m.sqlite...)
+	case DialectPostgres:
+		stmts = append(stmts, m.postgres...)
+	}
+	return stmts
+}
+
+// migrations contiene el historial completo del esquema. Nunca se modifica
+// una migración ya publicada: los cambios se agregan al final.
+var migrations = []migration{
+	{
+		version: 1,
+		common: []string{
+			`CREATE TABLE guivers (
+				id           VARCHAR(64) PRIMARY KEY,
+				email        TEXT NOT NULL,
+				display_name TEXT NOT NULL,
+				photo_url    TEXT NOT NULL DEFAULT '',
+				type         VARCHAR(32) NOT NULL,
+				bio          TEXT NOT NULL DEFAULT '',
+				whatsapp     TEXT NOT NULL DEFAULT '',
+				instagram    TEXT NOT NULL DEFAULT '',
+				created_at   TIMESTAMP NOT NULL,
+				updated_at   TIMESTAMP NOT NULL
+			)`,
+			`CREATE TABLE causes (
+				id                VARCHAR(64) PRIMARY KEY,
+				guiver_id         VARCHAR(64) NOT NULL,
+				title             TEXT NOT NULL,
+				description       TEXT NOT NULL,
+				type              VARCHAR(32) NOT NULL,
+				image_urls        TEXT NOT NULL DEFAULT '[]',
+				status            VARCHAR(32) NOT NULL,
+				location          TEXT NOT NULL DEFAULT '',
+				contact_whatsapp  TEXT NOT NULL DEFAULT '',
+				contact_instagram TEXT NOT NULL DEFAULT '',
+				contact_email     TEXT NOT NULL DEFAULT '',
+				likes             INTEGER NOT NULL DEFAULT 0,
+				created_at        TIMESTAMP NOT NULL,
+				updated_at        TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX causes_guiver_id_idx ON causes (guiver_id, created_at)`,
+			`CREATE INDEX causes_created_at_idx ON causes (created_at, id)`,
+			`CREATE TABLE cause_updates (
+				id         VARCHAR(64) PRIMARY KEY,
+				cause_id   VARCHAR(64) NOT NULL REFERENCES causes (id) ON DELETE CASCADE,
+				content    TEXT NOT NULL,
+				image_urls TEXT NOT NULL DEFAULT '[]',
+				created_at TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX cause_updates_cause_id_idx ON cause_updates (cause_id, created_at)`,
+			`CREATE TABLE cause_comments (
+				id         VARCHAR(64) PRIMARY KEY,
+				cause_id   VARCHAR(64) NOT NULL REFERENCES causes (id) ON DELETE CASCADE,
+				guiver_id  VARCHAR(64) NOT NULL,
+				content    TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX cause_comments_cause_id_idx ON cause_comments (cause_id, created_at)`,
+			`CREATE TABLE products (
+				id                  VARCHAR(64) PRIMARY KEY,
+				guiver_id           VARCHAR(64) NOT NULL,
+				cause_id            VARCHAR(64) NOT NULL,
+				title               TEXT NOT NULL,
+				description         TEXT NOT NULL,
+				image_urls          TEXT NOT NULL DEFAULT '[]',
+				price_amount        BIGINT NOT NULL,
+				price_currency      VARCHAR(3) NOT NULL,
+				donation_percentage INTEGER NOT NULL,
+				status              VARCHAR(32) NOT NULL,
+				contact_whatsapp    TEXT NOT NULL DEFAULT '',
+				contact_instagram   TEXT NOT NULL DEFAULT '',
+				contact_email       TEXT NOT NULL DEFAULT '',
+				created_at          TIMESTAMP NOT NULL,
+				updated_at          TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX products_cause_id_idx ON products (cause_id, created_at)`,
+			`CREATE INDEX products_guiver_id_idx ON products (guiver_id, created_at)`,
+			`CREATE INDEX products_created_at_idx ON products (created_at, id)`,
+			`CREATE INDEX products_price_idx ON products (price_currency, price_amount)`,
+		},
+		// Búsqueda de texto completo: FTS5 con contenido externo en SQLite
+		sqlite: []string{
+			`CREATE VIRTUAL TABLE causes_fts USING fts5(
+				title, description,
+				content='causes', content_rowid='rowid',
+				tokenize='unicode61 remove_diacritics 2'
+			)`,
+			`CREATE TRIGGER causes_fts_insert AFTER INSERT ON causes BEGIN
+				INSERT INTO causes_fts (rowid, title, description) VALUES (new.rowid, new.title, new.description);
+			END`,
+			`CREATE TRIGGER causes_fts_delete AFTER DELETE ON causes BEGIN
+				INSERT INTO causes_fts (causes_fts, rowid, title, description) VALUES ('delete', old.rowid, old.title, old.description);
+			END`,
+			`CREATE TRIGGER causes_fts_update AFTER UPDATE ON causes BEGIN
+				INSERT INTO causes_fts (causes_fts, rowid, title, description) VALUES ('delete', old.rowid, old.title, old.description);
+				INSERT INTO causes_fts (rowid, title, description) VALUES (new.rowid, new.title, new.description);
+			END`,
+			`CREATE VIRTUAL TABLE products_fts USING fts5(
+				title, description,
+				content='products', content_rowid='rowid',
+				tokenize='unicode61 remove_diacritics 2'
+			)`,
+			`CREATE TRIGGER products_fts_insert AFTER INSERT ON products BEGIN
+				INSERT INTO products_fts (rowid, title, description) VALUES (new.rowid, new.title, new.description);
+			END`,
+			`CREATE TRIGGER products_fts_delete AFTER DELETE ON products BEGIN
+				INSERT INTO products_fts (products_fts, rowid, title, description) VALUES ('delete', old.rowid, old.title, old.description);
+			END`,
+			`CREATE TRIGGER products_fts_update AFTER UPDATE ON products BEGIN
+				INSERT INTO products_fts (products_fts, rowid, title, description) VALUES ('delete', old.rowid, old.title, old.description);
+				INSERT INTO products_fts (rowid, title, description) VALUES (new.rowid, new.title, new.description);
+			END`,
+		},
+		// Búsqueda de texto completo: tsvector generado con índice GIN en PostgreSQL
+		postgres: []string{
+			`ALTER TABLE causes ADD COLUMN search_vector tsvector
+				GENERATED ALWAYS AS (to_tsvector('spanish', title || ' ' || description)) STORED`,
+			`CREATE INDEX causes_search_idx ON causes USING GIN (search_vector)`,
+			`ALTER TABLE products ADD COLUMN search_vector tsvector
+				GENERATED ALWAYS AS (to_tsvector('spanish', title || ' ' || description)) STORED`,
+			`CREATE INDEX products_search_idx ON products USING GIN (search_vector)`,
+		},
+	},
+	{
+		// Texto normalizado para el índice de búsqueda. Se calcula en Go con
+		// el paquete search (sin tildes, sin palabras vacías, plurales
+		// reducidos) para que todos los backends encuentren lo mismo.
+		version: 2,
+		common: []string{
+			`ALTER TABLE causes ADD COLUMN search_title TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE causes ADD COLUMN search_body TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE products ADD COLUMN search_title TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE products ADD COLUMN search_body TEXT NOT NULL DEFAULT ''`,
+		},
+		sqlite: []string{
+			`DROP TRIGGER causes_fts_insert`,
+			`DROP TRIGGER causes_fts_delete`,
+			`DROP TRIGGER causes_fts_update`,
+			`DROP TABLE causes_fts`,
+			`DROP TRIGGER products_fts_insert`,
+			`DROP TRIGGER products_fts_delete`,
+			`DROP TRIGGER products_fts_update`,
+			`DROP TABLE products_fts`,
+		},
+		postgres: []string{
+			`ALTER TABLE causes DROP COLUMN search_vector`,
+			`ALTER TABLE products DROP COLUMN search_vector`,
+		},
+		backfill: []func(ctx context.Context, d *DB, tx *sql.Tx) error{
+			backfillSearch("causes"),
+			backfillSearch("products"),
+		},
+	},
+	{
+		// Índice de búsqueda sobre el texto normalizado, con más peso para el
+		// título. Se crea después de completar los datos de la migración 2.
+		version: 3,
+		sqlite: []string{
+			`CREATE VIRTUAL TABLE causes_search USING fts5(
+				search_title, search_body,
+				content='causes', content_rowid='rowid',
+				tokenize='unicode61'
+			)`,
+			`INSERT INTO causes_search (causes_search) VALUES ('rebuild')`,
+			`CREATE TRIGGER causes_search_insert AFTER INSERT ON causes BEGIN
+				INSERT INTO causes_search (rowid, search_title, search_body) VALUES (new.rowid, new.search_title, new.search_body);
+			END`,
+			`CREATE TRIGGER causes_search_delete AFTER DELETE ON causes BEGIN
+				INSERT INTO causes_search (causes_search, rowid, search_title, search_body) VALUES ('delete', old.rowid, old.search_title, old.search_body);
+			END`,
+			`CREATE TRIGGER causes_search_update AFTER UPDATE ON causes BEGIN
+				INSERT INTO causes_search (causes_search, rowid, search_title, search_body) VALUES ('delete', old.rowid, old.search_title, old.search_body);
+				INSERT INTO causes_search (rowid, search_title, search_body) VALUES (new.rowid, new.search_title, new.search_body);
+			END`,
+			`CREATE VIRTUAL TABLE products_search USING fts5(
+				search_title, search_body,
+				content='products', content_rowid='rowid',
+				tokenize='unicode61'
+			)`,
+			`INSERT INTO products_search (products_search) VALUES ('rebuild')`,
+			`CREATE TRIGGER products_search_insert AFTER INSERT ON products BEGIN
+				INSERT INTO products_search (rowid, search_title, search_body) VALUES (new.rowid, new.search_title, new.search_body);
+			END`,
+			`CREATE TRIGGER products_search_delete AFTER DELETE ON products BEGIN
+				INSERT INTO products_search (products_search, rowid, search_title, search_body) VALUES ('delete', old.rowid, old.search_title, old.search_body);
+			END`,
+			`CREATE TRIGGER products_search_update AFTER UPDATE ON products BEGIN
+				INSERT INTO products_search (products_search, rowid, search_title, search_body) VALUES ('delete', old.rowid, old.search_title, old.search_body);
+				INSERT INTO products_search (rowid, search_title, search_body) VALUES (new.rowid, new.search_title, new.search_body);
+			END`,
+		},
+		postgres: []string{
+			`ALTER TABLE causes ADD COLUMN search_vector tsvector
+				GENERATED ALWAYS AS (setweight(to_tsvector('simple', search_title), 'A') ||
+					setweight(to_tsvector('simple', search_body), 'B')) STORED`,
+			`CREATE INDEX causes_search_idx ON causes USING GIN (search_vector)`,
+			`ALTER TABLE products ADD COLUMN search_vector tsvector
+				GENERATED ALWAYS AS (setweight(to_tsvector('simple', search_title), 'A') ||
+					setweight(to_tsvector('simple', search_body), 'B')) STORED`,
+			`CREATE INDEX products_search_idx ON products USING GIN (search_vector)`,
+		},
+	},
+	{
+		version: 4,
+		common: []string{
+			`CREATE TABLE cause_likes (
+				cause_id   VARCHAR(64) NOT NULL REFERENCES causes (id) ON DELETE CASCADE,
+				guiver_id  VARCHAR(64) NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (cause_id, guiver_id)
+			)`,
+			`CREATE INDEX cause_likes_guiver_id_idx ON cause_likes (guiver_id)`,
+		},
+	},
+	{
+		version: 5,
+		common: []string{
+			`ALTER TABLE causes ADD COLUMN comment_count INTEGER NOT NULL DEFAULT 0`,
+			`UPDATE causes SET comment_count = (SELECT COUNT(*) FROM cause_comments WHERE cause_comments.cause_id = causes.id)`,
+			`ALTER TABLE cause_comments ADD COLUMN edited_at TIMESTAMP`,
+			`CREATE INDEX cause_comments_page_idx ON cause_comments (cause_id, created_at, id)`,
+		},
+	},
+	{
+		version: 6,
+		common: []string{
+			`ALTER TABLE cause_comments ADD COLUMN parent_id VARCHAR(64) NOT NULL DEFAULT ''`,
+			`ALTER TABLE cause_comments ADD COLUMN thread_id VARCHAR(64) NOT NULL DEFAULT ''`,
+			`ALTER TABLE cause_comments ADD COLUMN mentions TEXT NOT NULL DEFAULT '[]'`,
+			`DROP INDEX cause_comments_page_idx`,
+			`CREATE INDEX cause_comments_page_idx ON cause_comments (cause_id, parent_id, created_at, id)`,
+			`CREATE INDEX cause_comments_thread_idx ON cause_comments (cause_id, thread_id, created_at, id)`,
+		},
+	},
+	{
+		version: 7,
+		common: []string{
+			`ALTER TABLE causes ADD COLUMN collaborators TEXT NOT NULL DEFAULT '[]'`,
+			`ALTER TABLE cause_updates ADD COLUMN guiver_id VARCHAR(64) NOT NULL DEFAULT ''`,
+			`UPDATE cause_updates SET guiver_id = (SELECT causes.guiver_id FROM causes WHERE causes.id = cause_updates.cause_id)`,
+			`ALTER TABLE cause_updates ADD COLUMN edited_at TIMESTAMP`,
+		},
+	},
+	{
+		version: 8,
+		common: []string{
+			`ALTER TABLE causes ADD COLUMN update_count INTEGER NOT NULL DEFAULT 0`,
+			`UPDATE causes SET update_count = (SELECT COUNT(*) FROM cause_updates WHERE cause_updates.cause_id = causes.id)`,
+			`ALTER TABLE causes ADD COLUMN pinned_update_id VARCHAR(64) NOT NULL DEFAULT ''`,
+			`DROP INDEX cause_updates_cause_id_idx`,
+			`CREATE INDEX cause_updates_page_idx ON cause_updates (cause_id, created_at, id)`,
+		},
+	},
+	{
+		version: 9,
+		common: []string{
+			`ALTER TABLE causes ADD COLUMN goal_amount BIGINT NOT NULL DEFAULT 0`,
+			`ALTER TABLE causes ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT ''`,
+			`ALTER TABLE causes ADD COLUMN deadline TIMESTAMP`,
+			`ALTER TABLE causes ADD COLUMN raised_amount BIGINT NOT NULL DEFAULT 0`,
+			`ALTER TABLE causes ADD COLUMN backer_count INTEGER NOT NULL DEFAULT 0`,
+			`CREATE TABLE cause_pledges (
+				id         VARCHAR(64) PRIMARY KEY,
+				cause_id   VARCHAR(64) NOT NULL REFERENCES causes (id) ON DELETE CASCADE,
+				guiver_id  VARCHAR(64) NOT NULL,
+				amount     BIGINT NOT NULL,
+				currency   VARCHAR(3) NOT NULL,
+				message    TEXT NOT NULL DEFAULT '',
+				anonymous  BOOLEAN NOT NULL DEFAULT FALSE,
+				status     VARCHAR(32) NOT NULL,
+				payment_id VARCHAR(128) NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX cause_pledges_page_idx ON cause_pledges (cause_id, created_at, id)`,
+			`CREATE INDEX cause_pledges_backer_idx ON cause_pledges (cause_id, guiver_id)`,
+		},
+	},
+	{
+		// Los pedidos no dependen de la Causa ni del producto: se conservan
+		// aunque estos se eliminen
+		version: 10,
+		common: []string{
+			`CREATE TABLE orders (
+				id                  VARCHAR(64) PRIMARY KEY,
+				product_id          VARCHAR(64) NOT NULL,
+				cause_id            VARCHAR(64) NOT NULL,
+				seller_id           VARCHAR(64) NOT NULL,
+				buyer_id            VARCHAR(64) NOT NULL,
+				quantity            INTEGER NOT NULL,
+				unit_price          BIGINT NOT NULL,
+				currency            VARCHAR(3) NOT NULL,
+				total               BIGINT NOT NULL,
+				donation_percentage INTEGER NOT NULL,
+				donation_amount     BIGINT NOT NULL,
+				status              VARCHAR(32) NOT NULL,
+				payment_id          VARCHAR(128) NOT NULL DEFAULT '',
+				created_at          TIMESTAMP NOT NULL,
+				updated_at          TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX orders_buyer_idx ON orders (buyer_id, created_at, id)`,
+			`CREATE INDEX orders_seller_idx ON orders (seller_id, created_at, id)`,
+			`CREATE INDEX orders_created_at_idx ON orders (created_at, id)`,
+		},
+	},
+	{
+		// Libro de partida doble de las causas. Es de solo agregado: los
+		// disparadores rechazan cualquier modificación o borrado.
+		version: 11,
+		common: []string{
+			`CREATE TABLE ledger_entries (
+				id          VARCHAR(128) PRIMARY KEY,
+				cause_id    VARCHAR(64) NOT NULL,
+				type        VARCHAR(32) NOT NULL,
+				amount      BIGINT NOT NULL,
+				currency    VARCHAR(3) NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				reference   VARCHAR(255) NOT NULL DEFAULT '',
+				created_by  VARCHAR(64) NOT NULL DEFAULT '',
+				guiver_id   VARCHAR(64) NOT NULL DEFAULT '',
+				created_at  TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX ledger_entries_page_idx ON ledger_entries (cause_id, created_at, id)`,
+			`CREATE INDEX ledger_entries_guiver_idx ON ledger_entries (guiver_id, created_at, id)`,
+			`CREATE TABLE ledger_postings (
+				entry_id VARCHAR(128) NOT NULL REFERENCES ledger_entries (id),
+				position INTEGER NOT NULL,
+				cause_id VARCHAR(64) NOT NULL,
+				account  VARCHAR(64) NOT NULL,
+				amount   BIGINT NOT NULL,
+				currency VARCHAR(3) NOT NULL,
+				PRIMARY KEY (entry_id, position)
+			)`,
+			`CREATE INDEX ledger_postings_cause_idx ON ledger_postings (cause_id, currency, account)`,
+		},
+		sqlite: []string{
+			`CREATE TRIGGER ledger_entries_no_update BEFORE UPDATE ON ledger_entries BEGIN
+				SELECT RAISE(ABORT, 'ledger entries are immutable');
+			END`,
+			`CREATE TRIGGER ledger_entries_no_delete BEFORE DELETE ON ledger_entries BEGIN
+				SELECT RAISE(ABORT, 'ledger entries are immutable');
+			END`,
+			`CREATE TRIGGER ledger_postings_no_update BEFORE UPDATE ON ledger_postings BEGIN
+				SELECT RAISE(ABORT, 'ledger entries are immutable');
+			END`,
+			`CREATE TRIGGER ledger_postings_no_delete BEFORE DELETE ON ledger_postings BEGIN
+				SELECT RAISE(ABORT, 'ledger entries are immutable');
+			END`,
+		},
+		postgres: []string{
+			`CREATE FUNCTION ledger_immutable() RETURNS trigger LANGUAGE plpgsql AS $$
+			BEGIN
+				RAISE EXCEPTION 'ledger entries are immutable';
+			END
+			$$`,
+			`CREATE TRIGGER ledger_entries_immutable BEFORE UPDATE OR DELETE ON ledger_entries
+				FOR EACH ROW EXECUTE FUNCTION ledger_immutable()`,
+			`CREATE TRIGGER ledger_postings_immutable BEFORE UPDATE OR DELETE ON ledger_postings
+				FOR EACH ROW EXECUTE FUNCTION ledger_immutable()`,
+		},
+	},
+	{
+		// Gastos informados en las actualizaciones, uno por fila para poder
+		// sumarlos por categoría
+		version: 12,
+		common: []string{
+			`CREATE TABLE cause_update_expenses (
+				update_id   VARCHAR(64) NOT NULL REFERENCES cause_updates (id) ON DELETE CASCADE,
+				position    INTEGER NOT NULL,
+				cause_id    VARCHAR(64) NOT NULL,
+				concept     TEXT NOT NULL,
+				category    VARCHAR(32) NOT NULL,
+				amount      BIGINT NOT NULL,
+				currency    VARCHAR(3) NOT NULL,
+				receipt_url TEXT NOT NULL DEFAULT '',
+				spent_at    TIMESTAMP NOT NULL,
+				PRIMARY KEY (update_id, position)
+			)`,
+			`CREATE INDEX cause_update_expenses_cause_idx ON cause_update_expenses (cause_id, currency, category)`,
+		},
+	},
+
+	{
+		// Existencias: NULL en products.stock indica que el producto no las
+		// controla. Las variantes tienen las suyas y products.stock es la suma.
+		version: 13,
+		common: []string{
+			`ALTER TABLE products ADD COLUMN stock INTEGER`,
+			`CREATE TABLE product_variants (
+				product_id VARCHAR(64) NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+				id         VARCHAR(64) NOT NULL,
+				position   INTEGER NOT NULL,
+				size       TEXT NOT NULL DEFAULT '',
+				color      TEXT NOT NULL DEFAULT '',
+				stock      INTEGER NOT NULL,
+				PRIMARY KEY (product_id, id)
+			)`,
+			`ALTER TABLE orders ADD COLUMN variant_id VARCHAR(64) NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		// Los roles pertenecen a la cuenta y no dependen de que exista su Guiver
+		version: 14,
+		common: []string{
+			`CREATE TABLE guiver_roles (
+				guiver_id VARCHAR(64) NOT NULL,
+				role      VARCHAR(32) NOT NULL,
+				PRIMARY KEY (guiver_id, role)
+			)`,
+			`CREATE INDEX guiver_roles_role ON guiver_roles (role, guiver_id)`,
+		},
+	},
+}
+
+// migrate aplica en orden las migraciones que aún no se han ejecutado
+func (d *DB) migrate(ctx context.Context) error {
+	_, err := d.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %v", err)
+	}
+
+	current := 0
+	row := d.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`)
+	if err := row.Scan(&current); err != nil {
+		return fmt.Errorf("error reading schema version: %v", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		err := d.withTx(ctx, func(tx *sql.Tx) error {
+			for _, stmt := range m.statements(d.dialect) {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			for _, backfill := range m.backfill {
+				if err := backfill(ctx, d, tx); err != nil {
+					return err
+				}
+			}
+			appliedAt := now()
+			_, err := d.exec(ctx, tx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+				m.version, sqlTime{&appliedAt})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error applying migration %d: %v", m.version, err)
+		}
+	}
+	return nil
+}
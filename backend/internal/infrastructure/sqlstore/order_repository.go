@@ -0,0 +1,156 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+const orderColumns = `orders.id, orders.product_id, orders.variant_id, orders.cause_id, orders.seller_id,
+	orders.buyer_id, orders.quantity, orders.unit_price, orders.currency, orders.total, orders.donation_percentage,
+	orders.donation_amount, orders.status, orders.payment_id, orders.created_at, orders.updated_at`
+
+// OrderRepository implementa el repositorio de pedidos sobre SQL
+type OrderRepository struct {
+	db *DB
+}
+
+// NewOrderRepository crea una nueva instancia de OrderRepository
+func NewOrderRepository(db *DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// Create registra un pedido pendiente
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error {
+	if order.ID == "" {
+		order.ID = uuid.New().String()
+	}
+	order.Status = models.OrderStatusPending
+	order.CalculateTotals()
+	order.CreatedAt = now()
+	order.UpdatedAt = order.CreatedAt
+
+	_, err := r.db.exec(ctx, r.db.db,
+		`INSERT INTO orders (id, product_id, variant_id, cause_id, seller_id, buyer_id, quantity, unit_price,
+			currency, total, donation_percentage, donation_amount, status, payment_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		order.ID, order.ProductID, order.VariantID, order.CauseID, order.SellerID, order.BuyerID, order.Quantity,
+		order.UnitPrice.Amount, order.UnitPrice.Currency, order.Total.Amount, order.DonationPercentage,
+		order.DonationAmount.Amount, order.Status, order.PaymentID, sqlTime{&order.CreatedAt}, sqlTime{&order.UpdatedAt})
+	return err
+}
+
+// GetByID obtiene un pedido por su ID
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	return r.get(ctx, r.db.db, id)
+}
+
+func (r *OrderRepository) get(ctx context.Context, q querier, id string) (*models.Order, error) {
+	var order models.Order
+	err := scanRow(r.db.queryRow(ctx, q, `SELECT `+orderColumns+` FROM orders WHERE orders.id = ?`, id),
+		orderFields(&order)...)
+	if err != nil {
+		return nil, err
+	}
+	return withCurrency(&order), nil
+}
+
+// UpdateStatus cambia el estado de un pedido
+func (r *OrderRepository) UpdateStatus(ctx context.Context, id string, status models.OrderStatus) (*models.Order, error) {
+	var order *models.Order
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		order, err = r.get(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status == status {
+			return nil
+		}
+		if !order.Status.CanBecome(status) {
+			return repository.ErrConflict
+		}
+
+		// La condición sobre el estado anterior evita aplicar dos veces la
+		// misma transición si compite con otra transacción
+		updatedAt := now()
+		res, err := r.db.exec(ctx, tx, `UPDATE orders SET status = ?, updated_at = ? WHERE id = ? AND status = ?`,
+			status, sqlTime{&updatedAt}, id, order.Status)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return repository.ErrConflict
+		}
+
+		order.Status = status
+		order.UpdatedAt = updatedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// List lista los pedidos según los filtros, del más nuevo al más antiguo
+func (r *OrderRepository) List(ctx context.Context, filter repository.OrderFilter) ([]*models.Order, error) {
+	var conditions []string
+	var args []interface{}
+	for _, f := range []struct{ column, value string }{
+		{"orders.buyer_id", filter.BuyerID},
+		{"orders.seller_id", filter.SellerID},
+		{"orders.cause_id", filter.CauseID},
+		{"orders.status", string(filter.Status)},
+	} {
+		if f.value != "" {
+			conditions = append(conditions, f.column+" = ?")
+			args = append(args, f.value)
+		}
+	}
+	if filter.After != nil {
+		cond, condArgs := afterCondition("orders", filter.After)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	query := `SELECT ` + orderColumns + ` FROM orders` + whereClause(conditions) +
+		` ORDER BY orders.created_at DESC, orders.id DESC`
+	query, args = limitOffset(query, args, filter.Limit, 0)
+
+	rows, err := r.db.query(ctx, r.db.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []*models.Order{}
+	for rows.Next() {
+		var order models.Order
+		if err := rows.Scan(orderFields(&order)...); err != nil {
+			return nil, err
+		}
+		orders = append(orders, withCurrency(&order))
+	}
+	return orders, rows.Err()
+}
+
+// orderFields son los destinos de Scan para orderColumns
+func orderFields(order *models.Order) []interface{} {
+	return []interface{}{&order.ID, &order.ProductID, &order.VariantID, &order.CauseID, &order.SellerID, &order.BuyerID,
+		&order.Quantity, &order.UnitPrice.Amount, &order.UnitPrice.Currency, &order.Total.Amount, &order.DonationPercentage,
+		&order.DonationAmount.Amount, &order.Status, &order.PaymentID, sqlTime{&order.CreatedAt}, sqlTime{&order.UpdatedAt}}
+}
+
+// withCurrency completa la moneda de Total y DonationAmount, que se guarda una
+// sola vez junto a UnitPrice
+func withCurrency(order *models.Order) *models.Order {
+	order.Total.Currency = order.UnitPrice.Currency
+	order.DonationAmount.Currency = order.UnitPrice.Currency
+	return order
+}
@@ -0,0 +1,196 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+const pledgeColumns = `cause_pledges.id, cause_pledges.cause_id, cause_pledges.guiver_id, cause_pledges.amount,
+	cause_pledges.currency, cause_pledges.message, cause_pledges.anonymous, cause_pledges.status,
+	cause_pledges.payment_id, cause_pledges.created_at`
+
+// PledgeRepository implementa el repositorio de aportes sobre SQL
+type PledgeRepository struct {
+	db *DB
+}
+
+// NewPledgeRepository crea una nueva instancia de PledgeRepository
+func NewPledgeRepository(db *DB) *PledgeRepository {
+	return &PledgeRepository{db: db}
+}
+
+// Create registra un aporte y, si ya está pagado, actualiza los totales de la Causa
+func (r *PledgeRepository) Create(ctx context.Context, pledge *models.Pledge) error {
+	if pledge.ID == "" {
+		pledge.ID = uuid.New().String()
+	}
+	if pledge.Status == "" {
+		pledge.Status = models.PledgeStatusPending
+	}
+	pledge.CreatedAt = now()
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if err := r.db.lock(ctx, tx, pledgesLock(pledge.CauseID)); err != nil {
+			return err
+		}
+		var exists int
+		if err := scanRow(r.db.queryRow(ctx, tx, `SELECT 1 FROM causes WHERE id = ?`, pledge.CauseID), &exists); err != nil {
+			return err
+		}
+
+		_, err := r.db.exec(ctx, tx,
+			`INSERT INTO cause_pledges (id, cause_id, guiver_id, amount, currency, message, anonymous, status,
+				payment_id, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			pledge.ID, pledge.CauseID, pledge.GuiverID, pledge.Amount.Amount, pledge.Amount.Currency, pledge.Message,
+			pledge.Anonymous, pledge.Status, pledge.PaymentID, sqlTime{&pledge.CreatedAt})
+		if err != nil {
+			return err
+		}
+		if pledge.Status != models.PledgeStatusPaid {
+			return nil
+		}
+		return r.count(ctx, tx, pledge, 1)
+	})
+}
+
+// pledgesLock es la clave que bloquea el registro de aportes de una Causa
+func pledgesLock(causeID string) string {
+	return "pledges:" + causeID
+}
+
+// GetByID obtiene un aporte de una Causa
+func (r *PledgeRepository) GetByID(ctx context.Context, causeID, id string) (*models.Pledge, error) {
+	return r.get(ctx, r.db.db, causeID, id)
+}
+
+func (r *PledgeRepository) get(ctx context.Context, q querier, causeID, id string) (*models.Pledge, error) {
+	var pledge models.Pledge
+	err := scanRow(r.db.queryRow(ctx, q,
+		`SELECT `+pledgeColumns+` FROM cause_pledges WHERE cause_pledges.cause_id = ? AND cause_pledges.id = ?`,
+		causeID, id),
+		pledgeFields(&pledge)...)
+	if err != nil {
+		return nil, err
+	}
+	return &pledge, nil
+}
+
+// UpdateStatus cambia el estado de un aporte y ajusta los totales de la Causa
+func (r *PledgeRepository) UpdateStatus(ctx context.Context, causeID, id string, status models.PledgeStatus) (*models.Pledge, error) {
+	var pledge *models.Pledge
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		pledge, err = r.get(ctx, tx, causeID, id)
+		if err != nil {
+			return err
+		}
+		if pledge.Status == status {
+			return nil
+		}
+		if !pledge.Status.CanBecome(status) {
+			return repository.ErrConflict
+		}
+
+		// La condición sobre el estado anterior evita aplicar dos veces la
+		// misma transición si compite con otra transacción
+		res, err := r.db.exec(ctx, tx, `UPDATE cause_pledges SET status = ? WHERE cause_id = ? AND id = ? AND status = ?`,
+			status, causeID, id, pledge.Status)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return repository.ErrConflict
+		}
+
+		pledge.Status = status
+		switch status {
+		case models.PledgeStatusPaid:
+			return r.count(ctx, tx, pledge, 1)
+		case models.PledgeStatusRefunded:
+			return r.count(ctx, tx, pledge, -1)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pledge, nil
+}
+
+// count suma (sign 1) o resta (sign -1) el aporte en los totales de la Causa.
+// El Guiver cuenta como aportante mientras tenga algún otro aporte pagado.
+func (r *PledgeRepository) count(ctx context.Context, tx *sql.Tx, pledge *models.Pledge, sign int) error {
+	// Se actualiza primero la Causa para bloquear su fila: los cambios
+	// concurrentes en la misma Causa esperan y ven el anterior al decidir si
+	// el Guiver era aportante
+	res, err := r.db.exec(ctx, tx, `UPDATE causes SET raised_amount = raised_amount + ? WHERE id = ?`,
+		int64(sign)*pledge.Amount.Amount, pledge.CauseID)
+	if err != nil {
+		return err
+	}
+	if err := requireAffected(res); err != nil {
+		return err
+	}
+
+	var others int
+	err = scanRow(r.db.queryRow(ctx, tx,
+		`SELECT COUNT(*) FROM cause_pledges WHERE cause_id = ? AND guiver_id = ? AND status = ? AND id <> ?`,
+		pledge.CauseID, pledge.GuiverID, models.PledgeStatusPaid, pledge.ID), &others)
+	if err != nil || others > 0 {
+		return err
+	}
+	_, err = r.db.exec(ctx, tx,
+		`UPDATE causes SET backer_count = CASE WHEN backer_count + ? > 0 THEN backer_count + ? ELSE 0 END WHERE id = ?`,
+		sign, sign, pledge.CauseID)
+	return err
+}
+
+// ListByCause lista los aportes de una Causa, del más nuevo al más antiguo
+func (r *PledgeRepository) ListByCause(ctx context.Context, causeID string, filter repository.PledgeFilter) ([]*models.Pledge, error) {
+	var exists int
+	if err := scanRow(r.db.queryRow(ctx, r.db.db, `SELECT 1 FROM causes WHERE id = ?`, causeID), &exists); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"cause_pledges.cause_id = ?"}
+	args := []interface{}{causeID}
+	if filter.After != nil {
+		cond, condArgs := afterCondition("cause_pledges", filter.After)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	query := `SELECT ` + pledgeColumns + ` FROM cause_pledges` + whereClause(conditions) +
+		` ORDER BY cause_pledges.created_at DESC, cause_pledges.id DESC`
+	query, args = limitOffset(query, args, filter.Limit, 0)
+
+	rows, err := r.db.query(ctx, r.db.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pledges := []*models.Pledge{}
+	for rows.Next() {
+		var pledge models.Pledge
+		if err := rows.Scan(pledgeFields(&pledge)...); err != nil {
+			return nil, err
+		}
+		pledges = append(pledges, &pledge)
+	}
+	return pledges, rows.Err()
+}
+
+// pledgeFields son los destinos de Scan para pledgeColumns
+func pledgeFields(pledge *models.Pledge) []interface{} {
+	return []interface{}{&pledge.ID, &pledge.CauseID, &pledge.GuiverID, &pledge.Amount.Amount,
+		&pledge.Amount.Currency, &pledge.Message, &pledge.Anonymous, &pledge.Status, &pledge.PaymentID,
+		sqlTime{&pledge.CreatedAt}}
+}
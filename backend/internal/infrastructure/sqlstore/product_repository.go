@@ -0,0 +1,351 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/domain/repository"
+)
+
+const productColumns = `products.id, products.guiver_id, products.cause_id, products.title,
+	products.description, products.image_urls, products.price_amount, products.price_currency, products.donation_percentage,
+	products.status, products.stock, products.contact_whatsapp, products.contact_instagram, products.contact_email,
+	products.created_at, products.updated_at`
+
+// ProductRepository implementa el repositorio de Productos sobre SQL
+type ProductRepository struct {
+	db *DB
+}
+
+// NewProductRepository crea una nueva instancia de ProductRepository
+func NewProductRepository(db *DB) *ProductRepository {
+	return &ProductRepository{db: db}
+}
+
+// Create crea un nuevo Producto
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	if product.ID == "" {
+		product.ID = uuid.New().String()
+	}
+
+	now := now()
+	product.CreatedAt = now
+	product.UpdatedAt = now
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := r.db.exec(ctx, tx,
+			`INSERT INTO products (id, guiver_id, cause_id, title, description, image_urls, price_amount,
+				price_currency, donation_percentage, status, stock, contact_whatsapp, contact_instagram,
+				contact_email, created_at, updated_at, search_title, search_body)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			product.ID, product.GuiverID, product.CauseID, product.Title, product.Description,
+			stringList{&product.ImageURLs}, product.Price.Amount, product.Price.Currency, product.DonationPercentage,
+			product.Status, nullInt{&product.Stock},
+			product.ContactInfo.WhatsApp, product.ContactInfo.Instagram, product.ContactInfo.Email,
+			sqlTime{&product.CreatedAt}, sqlTime{&product.UpdatedAt},
+			searchText(product.Title), searchText(product.Description))
+		if err != nil {
+			return err
+		}
+		return r.insertVariants(ctx, tx, product)
+	})
+}
+
+// GetByID obtiene un Producto por su ID
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	return r.get(ctx, r.db.db, id)
+}
+
+func (r *ProductRepository) get(ctx context.Context, q querier, id string) (*models.Product, error) {
+	products, err := r.queryProducts(ctx, q, `SELECT `+productColumns+` FROM products WHERE products.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return products[0], nil
+}
+
+// GetByCauseID obtiene los Productos asociados a una Causa
+func (r *ProductRepository) GetByCauseID(ctx context.Context, causeID string) ([]*models.Product, error) {
+	return r.List(ctx, repository.ProductFilter{CauseID: causeID})
+}
+
+// GetByGuiverID obtiene los Productos de un Guiver
+func (r *ProductRepository) GetByGuiverID(ctx context.Context, guiverID string) ([]*models.Product, error) {
+	return r.List(ctx, repository.ProductFilter{GuiverID: guiverID})
+}
+
+// Update actualiza un Producto en una transacción. Sin replaceStock no
+// escribe las existencias ni las variantes, de modo que no pisa lo que
+// descontó AdjustStock desde que se leyó el Producto.
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product, replaceStock bool) error {
+	product.UpdatedAt = now()
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := r.db.exec(ctx, tx,
+			`UPDATE products SET guiver_id = ?, cause_id = ?, title = ?, description = ?, image_urls = ?,
+				price_amount = ?, price_currency = ?, donation_percentage = ?, status = ?,
+				contact_whatsapp = ?, contact_instagram = ?, contact_email = ?, updated_at = ?,
+				search_title = ?, search_body = ?
+			WHERE id = ?`,
+			product.GuiverID, product.CauseID, product.Title, product.Description,
+			stringList{&product.ImageURLs}, product.Price.Amount, product.Price.Currency, product.DonationPercentage,
+			product.Status, product.ContactInfo.WhatsApp, product.ContactInfo.Instagram, product.ContactInfo.Email,
+			sqlTime{&product.UpdatedAt}, searchText(product.Title), searchText(product.Description), product.ID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(res); err != nil {
+			return err
+		}
+		if replaceStock {
+			if _, err := r.db.exec(ctx, tx, `UPDATE products SET stock = ? WHERE id = ?`,
+				nullInt{&product.Stock}, product.ID); err != nil {
+				return err
+			}
+			if _, err := r.db.exec(ctx, tx, `DELETE FROM product_variants WHERE product_id = ?`, product.ID); err != nil {
+				return err
+			}
+			if err := r.insertVariants(ctx, tx, product); err != nil {
+				return err
+			}
+		}
+
+		// Con la fila ya bloqueada se leen las existencias definitivas para
+		// decidir el estado
+		stored, err := r.get(ctx, tx, product.ID)
+		if err != nil {
+			return err
+		}
+		product.Stock, product.Variants = stored.Stock, stored.Variants
+		product.SyncStock()
+		_, err = r.db.exec(ctx, tx, `UPDATE products SET status = ? WHERE id = ?`, product.Status, product.ID)
+		return err
+	})
+}
+
+// AdjustStock suma delta a las existencias del Producto o de su variante en
+// una transacción. Las condiciones sobre el stock resultante evitan vender dos
+// veces la misma unidad si compite con otra transacción.
+func (r *ProductRepository) AdjustStock(ctx context.Context, id, variantID string, delta int) (*models.Product, error) {
+	var product *models.Product
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		product, err = r.get(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := product.AdjustStock(variantID, delta); err != nil || !product.TracksStock() {
+			return err
+		}
+
+		var res sql.Result
+		if variantID != "" {
+			res, err = r.db.exec(ctx, tx,
+				`UPDATE product_variants SET stock = stock + ? WHERE product_id = ? AND id = ? AND stock + ? >= 0`,
+				delta, id, variantID, delta)
+		} else {
+			res, err = r.db.exec(ctx, tx, `UPDATE products SET stock = stock + ? WHERE id = ? AND stock + ? >= 0`,
+				delta, id, delta)
+		}
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return models.ErrOutOfStock
+		}
+		if variantID != "" {
+			if _, err := r.db.exec(ctx, tx, `UPDATE products SET stock = stock + ? WHERE id = ?`, delta, id); err != nil {
+				return err
+			}
+		}
+
+		// Se vuelve a leer con las filas ya bloqueadas para decidir el estado
+		// sobre las existencias definitivas
+		if product, err = r.get(ctx, tx, id); err != nil {
+			return err
+		}
+		product.SyncStock()
+		product.UpdatedAt = now()
+		_, err = r.db.exec(ctx, tx, `UPDATE products SET status = ?, updated_at = ? WHERE id = ?`,
+			product.Status, sqlTime{&product.UpdatedAt}, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// Delete elimina un Producto con sus variantes
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM product_variants WHERE product_id = ?`, id); err != nil {
+			return err
+		}
+		res, err := r.db.exec(ctx, tx, `DELETE FROM products WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		return requireAffected(res)
+	})
+}
+
+// List lista las Productos según los filtros. Con búsqueda se ordenan por
+// relevancia, salvo al paginar por cursor, que conserva el orden por fecha.
+func (r *ProductRepository) List(ctx context.Context, filter repository.ProductFilter) ([]*models.Product, error) {
+	conditions, args := r.filterConditions(filter)
+	offset := filter.Offset
+	if filter.After != nil {
+		cond, condArgs := afterCondition("products", filter.After)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+		offset = 0
+	}
+
+	from := ` FROM products`
+	order := ` ORDER BY products.created_at DESC, products.id DESC`
+	if search, ok := r.db.searchClause("products", filter.Search); ok {
+		from += search.join
+		conditions = append(conditions, search.cond)
+		args = append(args, search.args...)
+		if filter.After == nil {
+			order = ` ORDER BY ` + search.order + `, products.created_at DESC, products.id DESC`
+			args = append(args, search.orderArgs...)
+		}
+	}
+
+	query := `SELECT ` + productColumns + from + whereClause(conditions) + order
+	query, args = limitOffset(query, args, filter.Limit, offset)
+
+	return r.queryProducts(ctx, r.db.db, query, args...)
+}
+
+// Count cuenta las Productos que cumplen los filtros, ignorando Limit y Offset
+func (r *ProductRepository) Count(ctx context.Context, filter repository.ProductFilter) (int64, error) {
+	conditions, args := r.filterConditions(filter)
+	from := ` FROM products`
+	if search, ok := r.db.searchClause("products", filter.Search); ok {
+		from += search.join
+		conditions = append(conditions, search.cond)
+		args = append(args, search.args...)
+	}
+
+	var total int64
+	err := scanRow(r.db.queryRow(ctx, r.db.db, `SELECT COUNT(*)`+from+whereClause(conditions), args...), &total)
+	return total, err
+}
+
+// filterConditions construye las condiciones WHERE de un ProductFilter
+func (r *ProductRepository) filterConditions(filter repository.ProductFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.CauseID != "" {
+		conditions = append(conditions, "products.cause_id = ?")
+		args = append(args, filter.CauseID)
+	}
+	if filter.GuiverID != "" {
+		conditions = append(conditions, "products.guiver_id = ?")
+		args = append(args, filter.GuiverID)
+	}
+	if len(filter.PriceRanges) > 0 {
+		ranges := make([]string, 0, len(filter.PriceRanges))
+		for _, priceRange := range filter.PriceRanges {
+			cond := "products.price_currency = ?"
+			args = append(args, priceRange.Currency)
+			if priceRange.Min > 0 {
+				cond += " AND products.price_amount >= ?"
+				args = append(args, priceRange.Min)
+			}
+			if priceRange.Max > 0 {
+				cond += " AND products.price_amount <= ?"
+				args = append(args, priceRange.Max)
+			}
+			ranges = append(ranges, "("+cond+")")
+		}
+		conditions = append(conditions, "("+strings.Join(ranges, " OR ")+")")
+	}
+	return conditions, args
+}
+
+func (r *ProductRepository) queryProducts(ctx context.Context, q querier, query string, args ...interface{}) ([]*models.Product, error) {
+	rows, err := r.db.query(ctx, q, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []*models.Product{}
+	for rows.Next() {
+		var product models.Product
+		err := rows.Scan(&product.ID, &product.GuiverID, &product.CauseID, &product.Title,
+			&product.Description, stringList{&product.ImageURLs}, &product.Price.Amount, &product.Price.Currency,
+			&product.DonationPercentage, &product.Status, nullInt{&product.Stock}, &product.ContactInfo.WhatsApp,
+			&product.ContactInfo.Instagram, &product.ContactInfo.Email,
+			sqlTime{&product.CreatedAt}, sqlTime{&product.UpdatedAt})
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, &product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := r.loadVariants(ctx, q, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// loadVariants completa las variantes de los Productos indicados
+func (r *ProductRepository) loadVariants(ctx context.Context, q querier, products []*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.Product, len(products))
+	ids := make([]interface{}, 0, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+		ids = append(ids, product.ID)
+	}
+
+	rows, err := r.db.query(ctx, q,
+		`SELECT product_id, id, size, color, stock FROM product_variants
+		WHERE product_id IN (`+placeholders(len(ids))+`) ORDER BY product_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID string
+		var variant models.ProductVariant
+		if err := rows.Scan(&productID, &variant.ID, &variant.Size, &variant.Color, &variant.Stock); err != nil {
+			return err
+		}
+		product := byID[productID]
+		product.Variants = append(product.Variants, variant)
+	}
+	return rows.Err()
+}
+
+// insertVariants guarda las variantes del Producto en orden
+func (r *ProductRepository) insertVariants(ctx context.Context, tx *sql.Tx, product *models.Product) error {
+	for i, variant := range product.Variants {
+		_, err := r.db.exec(ctx, tx,
+			`INSERT INTO product_variants (product_id, id, position, size, color, stock) VALUES (?, ?, ?, ?, ?, ?)`,
+			product.ID, variant.ID, i, variant.Size, variant.Color, variant.Stock)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package sqlstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guiver/internal/domain/repository"
+	"github.com/guiver/internal/domain/repository/repositorytest"
+)
+
+// openTestDB abre una base SQLite en memoria, vacía y migrada, que se cierra
+// al terminar la prueba
+func openTestDB(t *testing.T) *DB {
+	db, err := Open(context.Background(), DialectSQLite, ":memory:")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestGuiverRepository(t *testing.T) {
+	repositorytest.TestGuiverRepository(t, func(t *testing.T) repository.GuiverRepository {
+		return NewGuiverRepository(openTestDB(t))
+	})
+}
+
+func TestCauseRepository(t *testing.T) {
+	repositorytest.TestCauseRepository(t, func(t *testing.T) repository.CauseRepository {
+		return NewCauseRepository(openTestDB(t))
+	})
+}
+
+func TestProductRepository(t *testing.T) {
+	repositorytest.TestProductRepository(t, func(t *testing.T) repository.ProductRepository {
+		return NewProductRepository(openTestDB(t))
+	})
+}
+
+func TestPledgeRepository(t *testing.T) {
+	repositorytest.TestPledgeRepository(t, func(t *testing.T) (repository.CauseRepository, repository.PledgeRepository) {
+		db := openTestDB(t)
+		return NewCauseRepository(db), NewPledgeRepository(db)
+	})
+}
+
+func TestOrderRepository(t *testing.T) {
+	repositorytest.TestOrderRepository(t, func(t *testing.T) repository.OrderRepository {
+		return NewOrderRepository(openTestDB(t))
+	})
+}
+
+func TestLedgerRepository(t *testing.T) {
+	repositorytest.TestLedgerRepository(t, func(t *testing.T) repository.LedgerRepository {
+		return NewLedgerRepository(openTestDB(t))
+	})
+}
+
+func TestRoleRepository(t *testing.T) {
+	repositorytest.TestRoleRepository(t, func(t *testing.T) repository.RoleRepository {
+		return NewRoleRepository(openTestDB(t))
+	})
+}
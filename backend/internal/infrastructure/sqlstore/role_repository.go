@@ -0,0 +1,79 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/guiver/internal/domain/models"
+)
+
+// RoleRepository implementa el repositorio de roles sobre SQL
+type RoleRepository struct {
+	db *DB
+}
+
+// NewRoleRepository crea una nueva instancia de RoleRepository
+func NewRoleRepository(db *DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// GetRoles devuelve los roles asignados a un Guiver
+func (r *RoleRepository) GetRoles(ctx context.Context, guiverID string) ([]models.Role, error) {
+	rows, err := r.db.query(ctx, r.db.db,
+		`SELECT role FROM guiver_roles WHERE guiver_id = ? ORDER BY role`, guiverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// SetRoles reemplaza los roles asignados a un Guiver
+func (r *RoleRepository) SetRoles(ctx context.Context, guiverID string, roles []models.Role) error {
+	roles, err := models.NormalizeRoles(roles)
+	if err != nil {
+		return err
+	}
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := r.db.exec(ctx, tx, `DELETE FROM guiver_roles WHERE guiver_id = ?`, guiverID); err != nil {
+			return err
+		}
+		for _, role := range roles {
+			if _, err := r.db.exec(ctx, tx,
+				`INSERT INTO guiver_roles (guiver_id, role) VALUES (?, ?)`, guiverID, role); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListByRole lista los Guivers que tienen el rol
+func (r *RoleRepository) ListByRole(ctx context.Context, role models.Role) ([]string, error) {
+	rows, err := r.db.query(ctx, r.db.db,
+		`SELECT guiver_id FROM guiver_roles WHERE role = ? ORDER BY guiver_id`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,94 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/guiver/internal/search"
+)
+
+// searchClause es lo que una búsqueda por palabras clave agrega a una consulta
+// sobre causes o products
+type searchClause struct {
+	join      string // se agrega después de FROM
+	cond      string // condición WHERE
+	args      []interface{}
+	order     string // expresión de ORDER BY, la más relevante primero
+	orderArgs []interface{}
+}
+
+// searchText devuelve el texto normalizado que se guarda en las columnas
+// search_title y search_body, sobre las que se construye el índice
+func searchText(text string) string {
+	return strings.Join(search.Tokenize(text), " ")
+}
+
+// searchClause devuelve la búsqueda sobre la tabla indicada (causes o
+// products). Todos los términos deben aparecer en el título o la descripción;
+// los que aparecen en el título pesan más en la relevancia.
+func (d *DB) searchClause(table, text string) (searchClause, bool) {
+	q := search.ParseQuery(text)
+	if q.Empty() {
+		return searchClause{}, false
+	}
+
+	if d.dialect == DialectPostgres {
+		terms := strings.Join(q.Terms, " ")
+		return searchClause{
+			cond:      table + ".search_vector @@ plainto_tsquery('simple', ?)",
+			args:      []interface{}{terms},
+			order:     "ts_rank(" + table + ".search_vector, plainto_tsquery('simple', ?)) DESC",
+			orderArgs: []interface{}{terms},
+		}, true
+	}
+
+	// En FTS5 cada término se cita para neutralizar la sintaxis de consulta;
+	// varios términos seguidos equivalen a AND
+	quoted := make([]string, len(q.Terms))
+	for i, term := range q.Terms {
+		quoted[i] = `"` + term + `"`
+	}
+	index := table + "_search"
+	return searchClause{
+		join:  " JOIN " + index + " ON " + index + ".rowid = " + table + ".rowid",
+		cond:  index + " MATCH ?",
+		args:  []interface{}{strings.Join(quoted, " ")},
+		order: "bm25(" + index + ", 3.0, 1.0)",
+	}, true
+}
+
+// backfillSearch calcula search_title y search_body de las filas existentes
+// de la tabla
+func backfillSearch(table string) func(ctx context.Context, d *DB, tx *sql.Tx) error {
+	return func(ctx context.Context, d *DB, tx *sql.Tx) error {
+		type row struct{ id, title, description string }
+
+		rows, err := d.query(ctx, tx, `SELECT id, title, description FROM `+table)
+		if err != nil {
+			return err
+		}
+		var pending []row
+		for rows.Next() {
+			var r row
+			if err := rows.Scan(&r.id, &r.title, &r.description); err != nil {
+				rows.Close()
+				return err
+			}
+			pending = append(pending, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, r := range pending {
+			_, err := d.exec(ctx, tx, `UPDATE `+table+` SET search_title = ?, search_body = ? WHERE id = ?`,
+				searchText(r.title), searchText(r.description), r.id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,294 @@
+// Package sqlstore implementa los repositorios del dominio sobre database/sql.
+// Soporta SQLite y PostgreSQL; las diferencias entre ambos motores se
+// concentran en Dialect.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guiver/internal/domain/repository"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// Dialect identifica el motor de base de datos
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+// DB encapsula la conexión a la base de datos y su dialecto
+type DB struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// Open abre la conexión y aplica las migraciones pendientes
+func Open(ctx context.Context, dialect Dialect, dsn string) (*DB, error) {
+	var driverName string
+	switch dialect {
+	case DialectSQLite:
+		driverName = "sqlite"
+	case DialectPostgres:
+		driverName = "postgres"
+	default:
+		return nil, fmt.Errorf("unsupported SQL dialect %q", dialect)
+	}
+
+	conn, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %v", err)
+	}
+	if dialect == DialectSQLite {
+		// SQLite no admite escrituras concurrentes y cada conexión a
+		// ":memory:" es una base de datos distinta
+		conn.SetMaxOpenConns(1)
+	}
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+
+	db := &DB{db: conn, dialect: dialect}
+	if err := db.migrate(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close cierra la conexión con la base de datos
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// rebind adapta los placeholders "?" al dialecto
+func (d *DB) rebind(query string) string {
+	if d.dialect != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// querier es la parte común de *sql.DB y *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (d *DB) exec(ctx context.Context, q querier, query string, args ...interface{}) (sql.Result, error) {
+	res, err := q.ExecContext(ctx, d.rebind(query), args...)
+	return res, translateError(err)
+}
+
+func (d *DB) query(ctx context.Context, q querier, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := q.QueryContext(ctx, d.rebind(query), args...)
+	return rows, translateError(err)
+}
+
+func (d *DB) queryRow(ctx context.Context, q querier, query string, args ...interface{}) *sql.Row {
+	return q.QueryRowContext(ctx, d.rebind(query), args...)
+}
+
+// withTx ejecuta fn dentro de una transacción
+func (d *DB) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return translateError(tx.Commit())
+}
+
+// lock serializa hasta el final de tx las transacciones que bloquean la misma
+// clave, para las verificaciones que leen varias filas antes de escribir. En
+// SQLite no hace falta: hay una sola conexión y las transacciones no se
+// solapan.
+func (d *DB) lock(ctx context.Context, tx *sql.Tx, key string) error {
+	if d.dialect != DialectPostgres {
+		return nil
+	}
+	_, err := d.exec(ctx, tx, `SELECT pg_advisory_xact_lock(hashtext(?))`, key)
+	return err
+}
+
+// scanRow escanea una fila y traduce sql.ErrNoRows a repository.ErrNotFound
+func scanRow(row *sql.Row, dest ...interface{}) error {
+	err := row.Scan(dest...)
+	if err == sql.ErrNoRows {
+		return repository.ErrNotFound
+	}
+	return translateError(err)
+}
+
+// requireAffected devuelve repository.ErrNotFound si la sentencia no modificó ninguna fila
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// now devuelve la hora actual con la precisión que conservan ambos motores
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// timeLayout es un formato de ancho fijo para que SQLite ordene correctamente
+const timeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// sqlTime almacena un time.Time en UTC con precisión de microsegundos
+type sqlTime struct {
+	t *time.Time
+}
+
+func (s sqlTime) Value() (driver.Value, error) {
+	return s.t.UTC().Format(timeLayout), nil
+}
+
+func (s sqlTime) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*s.t = v.UTC()
+	case string:
+		return s.parse(v)
+	case []byte:
+		return s.parse(string(v))
+	case nil:
+		*s.t = time.Time{}
+	default:
+		return fmt.Errorf("cannot scan %T into time", src)
+	}
+	return nil
+}
+
+func (s sqlTime) parse(v string) error {
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return err
+	}
+	*s.t = t.UTC()
+	return nil
+}
+
+// nullTime almacena un *time.Time opcional como sqlTime o NULL
+type nullTime struct {
+	t **time.Time
+}
+
+func (n nullTime) Value() (driver.Value, error) {
+	if *n.t == nil {
+		return nil, nil
+	}
+	return sqlTime{*n.t}.Value()
+}
+
+func (n nullTime) Scan(src interface{}) error {
+	if src == nil {
+		*n.t = nil
+		return nil
+	}
+	var t time.Time
+	if err := (sqlTime{&t}).Scan(src); err != nil {
+		return err
+	}
+	*n.t = &t
+	return nil
+}
+
+// nullInt almacena un *int opcional como entero o NULL
+type nullInt struct {
+	n **int
+}
+
+func (n nullInt) Value() (driver.Value, error) {
+	if *n.n == nil {
+		return nil, nil
+	}
+	return int64(**n.n), nil
+}
+
+func (n nullInt) Scan(src interface{}) error {
+	var v sql.NullInt64
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	if !v.Valid {
+		*n.n = nil
+		return nil
+	}
+	i := int(v.Int64)
+	*n.n = &i
+	return nil
+}
+
+// stringList almacena un []string como JSON
+type stringList struct {
+	s *[]string
+}
+
+func (l stringList) Value() (driver.Value, error) {
+	if *l.s == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(*l.s)
+	return string(b), err
+}
+
+func (l stringList) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case nil:
+		*l.s = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into string list", src)
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		list = nil
+	}
+	*l.s = list
+	return nil
+}
+
+// placeholders devuelve "?, ?, ..." con n elementos
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -1,15 +1,28 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/guiver/pkg/firebase"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/identity"
 )
 
-// AuthMiddleware verifies the Firebase ID token in the Authorization header
-func AuthMiddleware() gin.HandlerFunc {
+// Keys of the values AuthMiddleware adds to the gin context
+const (
+	ContextUserID        = "userId"        // string, UID of the account
+	ContextEmail         = "email"         // string, may be empty
+	ContextEmailVerified = "emailVerified" // bool
+	ContextClaims        = "claims"        // map[string]interface{}, every claim of the token
+)
+
+// AuthMiddleware verifies the token in the Authorization header with verifier
+func AuthMiddleware(verifier identity.TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -17,15 +30,75 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		idToken := strings.Replace(authHeader, "Bearer ", "", 1)
-		token, err := firebase.VerifyIDToken(c.Request.Context(), idToken)
+		idToken := strings.TrimPrefix(authHeader, "Bearer ")
+		token, err := verifier.VerifyToken(c.Request.Context(), idToken)
 		if err != nil {
+			if !errors.Is(err, identity.ErrInvalidToken) {
+				// The verifier could not check the token (e.g. fetching public keys failed)
+				log.Printf("Error verifying token: %v", err)
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Could not verify token"})
+				return
+			}
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
-		// Add the user ID to the context
-		c.Set("userId", token.UID)
+		// Add the user and the token claims to the context
+		c.Set(ContextUserID, token.UID)
+		c.Set(ContextEmail, token.Email())
+		c.Set(ContextEmailVerified, token.EmailVerified())
+		c.Set(ContextClaims, token.Claims)
 		c.Next()
 	}
 }
+
+// RequireVerifiedEmail rejects requests whose token does not declare a verified
+// email. It must run after AuthMiddleware.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool(ContextEmailVerified) {
+			c.AbortWithStatusJSON(http.StatusForbidden, responses.ErrorResponse{
+				Status:  "error",
+				Message: "Email has not been verified, check your inbox",
+				Code:    responses.CodeEmailNotVerified,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests from accounts that have none of roles, either
+// in the "roles" claim of their token or assigned in the policy's repository.
+// It must run after AuthMiddleware.
+func RequireRole(policy *authz.Policy, roles ...models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := authz.Subject{ID: c.GetString(ContextUserID), Claims: Claims(c)}
+		ok, err := policy.HasRole(c.Request.Context(), subject, roles...)
+		if err != nil {
+			log.Printf("Error getting roles: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, responses.ErrorResponse{
+				Status:  "error",
+				Message: "Could not check permissions, please retry",
+				Code:    responses.CodeUnavailable,
+			})
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, responses.ErrorResponse{
+				Status:  "error",
+				Message: "Not authorized to access this resource",
+				Code:    responses.CodeForbidden,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Claims returns the claims of the token verified by AuthMiddleware
+func Claims(c *gin.Context) map[string]interface{} {
+	claims, _ := c.Get(ContextClaims)
+	m, _ := claims.(map[string]interface{})
+	return m
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiver/internal/authz"
+	"github.com/guiver/internal/delivery/http/responses"
+	"github.com/guiver/internal/domain/models"
+	"github.com/guiver/internal/identity"
+	"github.com/guiver/internal/identity/static"
+	"github.com/guiver/internal/infrastructure/memory"
+)
+
+// unavailableVerifier no puede verificar ningún token
+type unavailableVerifier struct{}
+
+func (unavailableVerifier) VerifyToken(ctx context.Context, token string) (*identity.Token, error) {
+	return nil, errors.New("fetching public keys: connection refused")
+}
+
+// failingRoles no puede leer los roles asignados
+type failingRoles struct{ *memory.RoleRepository }
+
+func (failingRoles) GetRoles(ctx context.Context, guiverID string) ([]models.Role, error) {
+	return nil, errors.New("storage unavailable")
+}
+
+func testVerifier() *static.Verifier {
+	v := static.New()
+	v.Add("verified", identity.Token{UID: "alice", Claims: map[string]interface{}{
+		"email": "alice@example.com", "email_verified": true,
+	}})
+	v.Add("unverified", identity.Token{UID: "bob", Claims: map[string]interface{}{
+		"email": "bob@example.com",
+	}})
+	v.Add("moderator", identity.Token{UID: "carol", Claims: map[string]interface{}{
+		"email_verified": true, authz.RolesClaim: []interface{}{"moderator"},
+	}})
+	v.Add("admin", identity.Token{UID: "dave", Claims: map[string]interface{}{"email_verified": true}})
+	return v
+}
+
+// serve atiende GET /test con los middlewares y un handler que devuelve el
+// usuario del contexto
+func serve(token string, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers = append(handlers, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"userId":        c.GetString(ContextUserID),
+			"email":         c.GetString(ContextEmail),
+			"emailVerified": c.GetBool(ContextEmailVerified),
+		})
+	})
+	router.GET("/test", handlers...)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier identity.TokenVerifier
+		token    string
+		status   int
+		userID   string
+		email    string
+		verified bool
+	}{
+		{"Verified", testVerifier(), "verified", http.StatusOK, "alice", "alice@example.com", true},
+		{"Unverified", testVerifier(), "unverified", http.StatusOK, "bob", "bob@example.com", false},
+		{"NoHeader", testVerifier(), "", http.StatusUnauthorized, "", "", false},
+		{"InvalidToken", testVerifier(), "forged", http.StatusUnauthorized, "", "", false},
+		{"VerifierUnavailable", unavailableVerifier{}, "verified", http.StatusServiceUnavailable, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.token, AuthMiddleware(tt.verifier))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var got struct {
+				UserID        string `json:"userId"`
+				Email         string `json:"email"`
+				EmailVerified bool   `json:"emailVerified"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.UserID != tt.userID || got.Email != tt.email || got.EmailVerified != tt.verified {
+				t.Errorf("context = %+v, want %s %s %v", got, tt.userID, tt.email, tt.verified)
+			}
+		})
+	}
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		code   string
+	}{
+		{"Verified", "verified", http.StatusOK, ""},
+		{"Unverified", "unverified", http.StatusForbidden, responses.CodeEmailNotVerified},
+		{"NoToken", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.token, AuthMiddleware(testVerifier()), RequireVerifiedEmail())
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.code == "" {
+				return
+			}
+			var got responses.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.Code != tt.code {
+				t.Errorf("code = %q, want %q", got.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	roles := memory.NewRoleRepository()
+	if err := roles.SetRoles(context.Background(), "dave", []models.Role{models.RoleAdmin}); err != nil {
+		t.Fatalf("SetRoles: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		policy *authz.Policy
+		token  string
+		roles  []models.Role
+		status int
+	}{
+		{name: "AssignedRole", policy: authz.NewPolicy(roles), token: "admin", roles: []models.Role{models.RoleAdmin}, status: http.StatusOK},
+		{name: "ClaimedRole", policy: authz.NewPolicy(roles), token: "moderator", roles: []models.Role{models.RoleModerator, models.RoleAdmin}, status: http.StatusOK},
+		{name: "MissingRole", policy: authz.NewPolicy(roles), token: "moderator", roles: []models.Role{models.RoleAdmin}, status: http.StatusForbidden},
+		{name: "NoRoles", policy: authz.NewPolicy(roles), token: "verified", roles: []models.Role{models.RoleOrganizer}, status: http.StatusForbidden},
+		{name: "RolesUnavailable", policy: authz.NewPolicy(failingRoles{}), token: "admin", roles: []models.Role{models.RoleAdmin}, status: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.token, AuthMiddleware(testVerifier()), RequireRole(tt.policy, tt.roles...))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+		})
+	}
+}
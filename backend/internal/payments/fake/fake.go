@@ -0,0 +1,155 @@
+// Package fake implementa una pasarela de pagos en memoria para desarrollo y
+// pruebas, sin servicios externos.
+//
+// Es determinista: los pagos se numeran en orden de creación (fake_pay_000001),
+// el pagador los aprueba al instante salvo los importes terminados en 99, que
+// se rechazan, y las notificaciones se firman con HMAC-SHA256 en la cabecera
+// SignatureHeader.
+package fake
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/guiver/internal/payments"
+)
+
+// SignatureHeader es la cabecera con la firma de las notificaciones
+const SignatureHeader = "X-Fake-Signature"
+
+// Provider es la pasarela de pagos falsa
+type Provider struct {
+	mu          sync.Mutex
+	secret      []byte
+	checkoutURL string
+	payments    map[string]*payments.Payment
+	next        int
+}
+
+// New crea una pasarela falsa que firma las notificaciones con secret. Las
+// URLs de checkout se forman a partir de checkoutURL.
+func New(secret []byte, checkoutURL string) *Provider {
+	return &Provider{
+		secret:      secret,
+		checkoutURL: strings.TrimSuffix(checkoutURL, "/"),
+		payments:    make(map[string]*payments.Payment),
+	}
+}
+
+// CreateCheckout registra el pago, ya aprobado o rechazado por el pagador
+func (p *Provider) CreateCheckout(ctx context.Context, req payments.CheckoutRequest) (*payments.Checkout, error) {
+	if req.Amount <= 0 {
+		return nil, payments.ErrInvalidAmount
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.next++
+	payment := &payments.Payment{
+		ID:        fmt.Sprintf("fake_pay_%06d", p.next),
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Status:    payments.StatusAuthorized,
+	}
+	if req.Amount%100 == 99 {
+		payment.Status = payments.StatusFailed
+	}
+	p.payments[payment.ID] = payment
+	return &payments.Checkout{PaymentID: payment.ID, URL: p.checkoutURL + "/" + payment.ID}, nil
+}
+
+// Capture cobra un pago aprobado
+func (p *Provider) Capture(ctx context.Context, paymentID string) (*payments.Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[paymentID]
+	if !ok {
+		return nil, payments.ErrNotFound
+	}
+	switch payment.Status {
+	case payments.StatusAuthorized:
+		payment.Status = payments.StatusCaptured
+	case payments.StatusCaptured:
+	default:
+		return nil, payments.ErrNotAuthorized
+	}
+	clone := *payment
+	return &clone, nil
+}
+
+// Refund devuelve parte o todo un pago capturado
+func (p *Provider) Refund(ctx context.Context, paymentID string, amount int64) (*payments.Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[paymentID]
+	if !ok {
+		return nil, payments.ErrNotFound
+	}
+	if payment.Status != payments.StatusCaptured {
+		return nil, payments.ErrNotCaptured
+	}
+	if amount <= 0 || amount > payment.Amount-payment.RefundedAmount {
+		return nil, payments.ErrInvalidAmount
+	}
+
+	payment.RefundedAmount += amount
+	if payment.RefundedAmount == payment.Amount {
+		payment.Status = payments.StatusRefunded
+	}
+	clone := *payment
+	return &clone, nil
+}
+
+// VerifyWebhook verifica la firma de una notificación y la decodifica
+func (p *Provider) VerifyWebhook(payload []byte, header http.Header) (*payments.Event, error) {
+	signature, err := hex.DecodeString(header.Get(SignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(payload)) {
+		return nil, payments.ErrInvalidSignature
+	}
+
+	var event payments.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %v", err)
+	}
+	return &event, nil
+}
+
+// Notification genera la notificación firmada que enviaría la pasarela sobre
+// el estado actual del pago, para simularla en desarrollo y pruebas
+func (p *Provider) Notification(paymentID string, eventType payments.EventType) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	payment, ok := p.payments[paymentID]
+	var event payments.Event
+	if ok {
+		event = payments.Event{Type: eventType, Payment: *payment}
+	}
+	p.mu.Unlock()
+	if !ok {
+		return nil, nil, payments.ErrNotFound
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set(SignatureHeader, hex.EncodeToString(p.sign(payload)))
+	return payload, header, nil
+}
+
+func (p *Provider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
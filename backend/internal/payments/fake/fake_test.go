@@ -0,0 +1,165 @@
+package fake
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/guiver/internal/payments"
+)
+
+func checkout(t *testing.T, p *Provider, amount int64) *payments.Checkout {
+	t.Helper()
+	c, err := p.CreateCheckout(context.Background(), payments.CheckoutRequest{
+		Reference: "order:o1", Amount: amount, Currency: "ARS",
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckout: %v", err)
+	}
+	return c
+}
+
+func TestCreateCheckout(t *testing.T) {
+	p := New([]byte("secret"), "http://localhost/checkout/")
+	first := checkout(t, p, 1000)
+	second := checkout(t, p, 1099)
+	if first.PaymentID != "fake_pay_000001" || first.URL != "http://localhost/checkout/fake_pay_000001" ||
+		second.PaymentID != "fake_pay_000002" {
+		t.Errorf("CreateCheckout = %+v, %+v", first, second)
+	}
+
+	for _, amount := range []int64{0, -100} {
+		_, err := p.CreateCheckout(context.Background(), payments.CheckoutRequest{Amount: amount, Currency: "ARS"})
+		if !errors.Is(err, payments.ErrInvalidAmount) {
+			t.Errorf("CreateCheckout(%d) error = %v, want ErrInvalidAmount", amount, err)
+		}
+	}
+}
+
+func TestCapture(t *testing.T) {
+	ctx := context.Background()
+	p := New([]byte("secret"), "http://localhost/checkout")
+	tests := []struct {
+		name    string
+		amount  int64
+		want    payments.Status
+		wantErr error
+	}{
+		{"Approved", 1000, payments.StatusCaptured, nil},
+		{"RejectedEndingIn99", 1099, "", payments.ErrNotAuthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := checkout(t, p, tt.amount).PaymentID
+			// Capturar dos veces da el mismo resultado
+			for i := 0; i < 2; i++ {
+				payment, err := p.Capture(ctx, id)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Capture error = %v, want %v", err, tt.wantErr)
+				}
+				if err == nil && payment.Status != tt.want {
+					t.Errorf("Capture status = %q, want %q", payment.Status, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := p.Capture(ctx, "missing"); !errors.Is(err, payments.ErrNotFound) {
+		t.Errorf("Capture(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRefund(t *testing.T) {
+	ctx := context.Background()
+	p := New([]byte("secret"), "http://localhost/checkout")
+	captured := checkout(t, p, 1000).PaymentID
+	if _, err := p.Capture(ctx, captured); err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	authorized := checkout(t, p, 500).PaymentID
+
+	tests := []struct {
+		name         string
+		paymentID    string
+		amount       int64
+		wantErr      error
+		wantStatus   payments.Status
+		wantRefunded int64
+	}{
+		{"NotCaptured", authorized, 500, payments.ErrNotCaptured, "", 0},
+		{"Missing", "missing", 100, payments.ErrNotFound, "", 0},
+		{"Zero", captured, 0, payments.ErrInvalidAmount, "", 0},
+		{"Partial", captured, 400, nil, payments.StatusCaptured, 400},
+		{"MoreThanLeft", captured, 601, payments.ErrInvalidAmount, "", 0},
+		{"Rest", captured, 600, nil, payments.StatusRefunded, 1000},
+		{"AlreadyRefunded", captured, 1, payments.ErrNotCaptured, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment, err := p.Refund(ctx, tt.paymentID, tt.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refund error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (payment.Status != tt.wantStatus || payment.RefundedAmount != tt.wantRefunded) {
+				t.Errorf("Refund = %+v, want status %q and %d refunded", payment, tt.wantStatus, tt.wantRefunded)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	p := New([]byte("secret"), "http://localhost/checkout")
+	id := checkout(t, p, 1000).PaymentID
+	payload, header, err := p.Notification(id, payments.EventAuthorized)
+	if err != nil {
+		t.Fatalf("Notification: %v", err)
+	}
+
+	event, err := p.VerifyWebhook(payload, header)
+	if err != nil {
+		t.Fatalf("VerifyWebhook: %v", err)
+	}
+	if event.Type != payments.EventAuthorized || event.Payment.ID != id || event.Payment.Reference != "order:o1" ||
+		event.Payment.Amount != 1000 || event.Payment.Status != payments.StatusAuthorized {
+		t.Errorf("VerifyWebhook = %+v", event)
+	}
+
+	signed := func(secret string, body []byte) http.Header {
+		header := http.Header{}
+		header.Set(SignatureHeader, hex.EncodeToString(New([]byte(secret), "").sign(body)))
+		return header
+	}
+	tampered := append([]byte(nil), payload...)
+	tampered[len(tampered)-2] = ' '
+	tests := []struct {
+		name    string
+		payload []byte
+		header  http.Header
+	}{
+		{"MissingSignature", payload, http.Header{}},
+		{"NotHex", payload, http.Header{SignatureHeader: []string{"zz"}}},
+		{"OtherSecret", payload, signed("other", payload)},
+		{"TamperedPayload", tampered, header},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.VerifyWebhook(tt.payload, tt.header); !errors.Is(err, payments.ErrInvalidSignature) {
+				t.Errorf("VerifyWebhook error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	t.Run("SignedButNotJSON", func(t *testing.T) {
+		body := []byte("not json")
+		_, err := p.VerifyWebhook(body, signed("secret", body))
+		if err == nil || errors.Is(err, payments.ErrInvalidSignature) {
+			t.Errorf("VerifyWebhook error = %v, want a payload error", err)
+		}
+	})
+
+	if _, _, err := p.Notification("missing", payments.EventCaptured); !errors.Is(err, payments.ErrNotFound) {
+		t.Errorf("Notification(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package payments define la interfaz de las pasarelas de pago. Los handlers
+// solo dependen de Provider, de modo que agregar una pasarela real
+// (MercadoPago, Stripe) no requiere modificarlos.
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Status representa el estado de un pago en la pasarela
+type Status string
+
+const (
+	StatusPending    Status = "pending"    // el pagador aún no completó el checkout
+	StatusAuthorized Status = "authorized" // el pagador aprobó el pago; falta capturarlo
+	StatusCaptured   Status = "captured"
+	StatusFailed     Status = "failed"
+	StatusRefunded   Status = "refunded" // reembolsado por completo
+)
+
+// EventType identifica el tipo de una notificación de la pasarela
+type EventType string
+
+const (
+	EventAuthorized EventType = "payment.authorized"
+	EventCaptured   EventType = "payment.captured"
+	EventFailed     EventType = "payment.failed"
+	EventRefunded   EventType = "payment.refunded"
+)
+
+// Errores comunes a todas las pasarelas
+var (
+	ErrNotFound         = errors.New("payment not found")
+	ErrNotAuthorized    = errors.New("payment not authorized by the payer")
+	ErrNotCaptured      = errors.New("payment not captured")
+	ErrInvalidAmount    = errors.New("invalid payment amount")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// CheckoutRequest describe un cobro. Los importes están en unidades menores de
+// Currency (centavos).
+type CheckoutRequest struct {
+	// Reference identifica la operación propia que se cobra ("pledge:causa:aporte")
+	// y vuelve en los pagos y las notificaciones
+	Reference   string
+	Description string
+	Amount      int64
+	Currency    string
+}
+
+// Checkout es un cobro iniciado: el pagador debe completarlo en URL
+type Checkout struct {
+	PaymentID string
+	URL       string
+}
+
+// Payment es el estado de un pago en la pasarela
+type Payment struct {
+	ID             string `json:"id"`
+	Reference      string `json:"reference"`
+	Amount         int64  `json:"amount"`
+	Currency       string `json:"currency"`
+	Status         Status `json:"status"`
+	RefundedAmount int64  `json:"refundedAmount"`
+}
+
+// Event es una notificación verificada de la pasarela
+type Event struct {
+	Type    EventType `json:"type"`
+	Payment Payment   `json:"payment"`
+}
+
+// Provider es una pasarela de pagos
+type Provider interface {
+	// CreateCheckout inicia un cobro y devuelve a dónde redirigir al pagador
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (*Checkout, error)
+	// Capture cobra un pago que el pagador aprobó. Capturar un pago ya
+	// capturado no cambia nada; si el pagador no lo aprobó falla con
+	// ErrNotAuthorized.
+	Capture(ctx context.Context, paymentID string) (*Payment, error)
+	// Refund devuelve amount de un pago capturado (ErrNotCaptured si no lo
+	// está, ErrInvalidAmount si supera lo que queda por devolver)
+	Refund(ctx context.Context, paymentID string, amount int64) (*Payment, error)
+	// VerifyWebhook verifica la firma de una notificación recibida y la
+	// decodifica. Falla con ErrInvalidSignature si la firma no es válida.
+	VerifyWebhook(payload []byte, header http.Header) (*Event, error)
+}
@@ -0,0 +1,74 @@
+package search
+
+// Index es un índice invertido en memoria que se mantiene al crear, actualizar
+// y eliminar documentos. No es seguro para uso concurrente: el llamador debe
+// protegerlo con el mismo lock que los datos indexados.
+type Index struct {
+	docs     map[string]fields
+	postings map[string]map[string]struct{}
+}
+
+// NewIndex crea una nueva instancia de Index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]fields),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// Put indexa el documento, reemplazando la versión anterior si existía
+func (i *Index) Put(id string, doc Document) {
+	i.Remove(id)
+
+	f := newFields(doc)
+	i.docs[id] = f
+	for _, m := range []map[string]int{f.title, f.body} {
+		for term := range m {
+			if i.postings[term] == nil {
+				i.postings[term] = make(map[string]struct{})
+			}
+			i.postings[term][id] = struct{}{}
+		}
+	}
+}
+
+// Remove quita el documento del índice
+func (i *Index) Remove(id string) {
+	f, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	delete(i.docs, id)
+	for _, m := range []map[string]int{f.title, f.body} {
+		for term := range m {
+			delete(i.postings[term], id)
+			if len(i.postings[term]) == 0 {
+				delete(i.postings, term)
+			}
+		}
+	}
+}
+
+// Search devuelve la relevancia de cada documento que contiene todos los
+// términos de la consulta, por ID
+func (i *Index) Search(q Query) map[string]float64 {
+	scores := make(map[string]float64)
+	if q.Empty() {
+		return scores
+	}
+
+	// Se recorren solo los documentos del término menos frecuente
+	candidates := i.postings[q.Terms[0]]
+	for _, term := range q.Terms[1:] {
+		if len(i.postings[term]) < len(candidates) {
+			candidates = i.postings[term]
+		}
+	}
+
+	for id := range candidates {
+		if s := score(q, i.docs[id]); s > 0 {
+			scores[id] = s
+		}
+	}
+	return scores
+}
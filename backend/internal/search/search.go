@@ -0,0 +1,176 @@
+// Package search implementa la búsqueda por palabras clave de causas y
+// productos. El texto se normaliza igual al indexar y al consultar: minúsculas,
+// sin tildes ni diéresis, sin palabras vacías del español y con una
+// reducción simple de plurales, de modo que "Niños" encuentra "niño".
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxQueryTerms limita la cantidad de términos que se usan de una consulta
+const MaxQueryTerms = 10
+
+// Document es el texto indexable de una entidad
+type Document struct {
+	Title string
+	Body  string
+}
+
+// Query es una consulta ya normalizada
+type Query struct {
+	Terms []string
+}
+
+// ParseQuery normaliza el texto de búsqueda del usuario
+func ParseQuery(text string) Query {
+	terms := unique(Tokenize(text))
+	if len(terms) > MaxQueryTerms {
+		terms = terms[:MaxQueryTerms]
+	}
+	return Query{Terms: terms}
+}
+
+// Empty indica si la consulta no tiene términos útiles
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0
+}
+
+// Tokenize divide el texto en términos normalizados, en orden y con repeticiones
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(Normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 2 || stopwords[word] {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+// Terms devuelve los términos distintos del documento, para guardarlos en el índice
+func Terms(doc Document) []string {
+	return unique(append(Tokenize(doc.Title), Tokenize(doc.Body)...))
+}
+
+// Normalize pasa el texto a minúsculas y elimina tildes y diéresis
+func Normalize(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Pesos de cada campo al calcular la relevancia
+const (
+	titleWeight = 3
+	bodyWeight  = 1
+)
+
+// Score calcula la relevancia del documento para la consulta. Devuelve 0 si
+// el documento no contiene todos los términos.
+func Score(q Query, doc Document) float64 {
+	return score(q, newFields(doc))
+}
+
+// fields cuenta las apariciones de cada término en el título y en el cuerpo
+type fields struct {
+	title map[string]int
+	body  map[string]int
+}
+
+func newFields(doc Document) fields {
+	return fields{title: counts(Tokenize(doc.Title)), body: counts(Tokenize(doc.Body))}
+}
+
+func score(q Query, f fields) float64 {
+	if q.Empty() {
+		return 0
+	}
+
+	var total float64
+	for _, term := range q.Terms {
+		inTitle, inBody := f.title[term], f.body[term]
+		if inTitle == 0 && inBody == 0 {
+			return 0
+		}
+		total += titleWeight*saturate(inTitle) + bodyWeight*saturate(inBody)
+	}
+	return total
+}
+
+// Rank filtra los elementos que coinciden con la consulta y los ordena por
+// relevancia descendente. A igual relevancia se conserva el orden recibido.
+func Rank[T any](items []T, q Query, doc func(T) Document) []T {
+	type hit struct {
+		item  T
+		score float64
+	}
+
+	hits := make([]hit, 0, len(items))
+	for _, item := range items {
+		if score := Score(q, doc(item)); score > 0 {
+			hits = append(hits, hit{item: item, score: score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+
+	ranked := make([]T, len(hits))
+	for i, h := range hits {
+		ranked[i] = h.item
+	}
+	return ranked
+}
+
+// Filter devuelve los elementos que coinciden con la consulta, en el orden recibido
+func Filter[T any](items []T, q Query, doc func(T) Document) []T {
+	matches := make([]T, 0, len(items))
+	for _, item := range items {
+		if Score(q, doc(item)) > 0 {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// saturate evita que repetir una palabra muchas veces domine la relevancia
+func saturate(n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	return 2 * float64(n) / float64(n+1)
+}
+
+func counts(terms []string) map[string]int {
+	m := make(map[string]int, len(terms))
+	for _, term := range terms {
+		m[term]++
+	}
+	return m
+}
+
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	result := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			result = append(result, term)
+		}
+	}
+	return result
+}
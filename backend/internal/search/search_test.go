@@ -0,0 +1,128 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Niños", []string{"nino"}},
+		{"Rescate de PERROS en la calle", []string{"rescat", "perro", "call"}},
+		{"Pingüino, ¡árbol! y café", []string{"pinguino", "arbol", "caf"}},
+		{"Los países y el país", []string{"pai", "pai"}},
+		{"a 1 x 42 ONG2024", []string{"42", "ong2024"}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"perro perros PERRO", []string{"perro"}},
+		{"de la y", []string{}},
+		{"uno dos tres cuatro cinco seis siete ocho nueve diez once doce",
+			[]string{"dos", "tre", "cuatro", "cinco", "sei", "siet", "ocho", "nuev", "diez", "onc"}},
+	}
+	for _, tt := range tests {
+		q := ParseQuery(tt.text)
+		if !reflect.DeepEqual(q.Terms, tt.want) {
+			t.Errorf("ParseQuery(%q) = %q, want %q", tt.text, q.Terms, tt.want)
+		}
+		if q.Empty() != (len(tt.want) == 0) {
+			t.Errorf("ParseQuery(%q).Empty() = %v", tt.text, q.Empty())
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	doc := Document{Title: "Rescate de perros", Body: "Ayudamos a los perros y a más perros de la calle"}
+	tests := []struct {
+		name  string
+		query string
+		want  float64
+	}{
+		{"Empty", "", 0},
+		{"Missing", "gatos", 0},
+		{"AllTermsRequired", "perros gatos", 0},
+		// perro: 1 en el título y 2 en el cuerpo, saturados a 1 y 4/3
+		{"TitleAndBody", "perro", titleWeight*1 + bodyWeight*4.0/3},
+		{"BodyOnly", "calles", bodyWeight * 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(ParseQuery(tt.query), doc); got != tt.want {
+				t.Errorf("Score(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankAndFilter(t *testing.T) {
+	docs := []Document{
+		{Title: "Huerta comunitaria", Body: "Perros bienvenidos"},
+		{Title: "Gatos", Body: "Sin relación"},
+		{Title: "Rescate de perros", Body: "Perros de la calle"},
+		{Title: "Colecta", Body: "Para un perro"},
+	}
+	text := func(doc Document) Document { return doc }
+	q := ParseQuery("perro")
+
+	ranked := Rank(docs, q, text)
+	want := []Document{docs[2], docs[0], docs[3]}
+	if !reflect.DeepEqual(ranked, want) {
+		t.Errorf("Rank = %v, want %v", ranked, want)
+	}
+	filtered := Filter(docs, q, text)
+	want = []Document{docs[0], docs[2], docs[3]}
+	if !reflect.DeepEqual(filtered, want) {
+		t.Errorf("Filter = %v, want %v", filtered, want)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	index := NewIndex()
+	index.Put("a", Document{Title: "Rescate de perros", Body: "En la calle"})
+	index.Put("b", Document{Title: "Huerta", Body: "Un perro guardián"})
+	index.Put("c", Document{Title: "Gatos"})
+
+	assertHits := func(query string, want ...string) {
+		t.Helper()
+		scores := index.Search(ParseQuery(query))
+		got := make([]string, 0, len(scores))
+		for _, id := range want {
+			if scores[id] > 0 {
+				got = append(got, id)
+			}
+		}
+		if len(scores) != len(want) || len(got) != len(want) {
+			t.Errorf("Search(%q) = %v, want %v", query, scores, want)
+		}
+	}
+
+	assertHits("perros", "a", "b")
+	assertHits("perro calle", "a")
+	assertHits("")
+	assertHits("tortuga")
+
+	// Put reemplaza la versión anterior y Remove la quita
+	index.Put("a", Document{Title: "Rescate de gatos"})
+	assertHits("perros", "b")
+	assertHits("gatos", "a", "c")
+	index.Remove("c")
+	index.Remove("missing")
+	assertHits("gatos", "a")
+	if scores := index.Search(ParseQuery("perro")); scores["b"] != Score(ParseQuery("perro"), Document{Title: "Huerta", Body: "Un perro guardián"}) {
+		t.Errorf("Search score = %v, want the same as Score", scores["b"])
+	}
+}
@@ -0,0 +1,51 @@
+package search
+
+import "strings"
+
+// stopwords son palabras vacías del español, ya normalizadas (sin tildes)
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		al algo algunas algunos ante antes como con contra cual cuando de del desde
+		donde durante el ella ellas ellos en entre era es esa esas ese eso esos esta
+		estas este esto estos fue ha hay la las le les lo los mas me mi mis muy nada
+		ni no nos nosotras nosotros nuestra nuestro os otra otras otro otros para pero
+		poco por porque que quien quienes se ser si sin sobre son su sus tambien tanto
+		te ti todo todos tu tus un una unas uno unos ya yo
+	`) {
+		stopwords[word] = true
+	}
+}
+
+// stem reduce los plurales más comunes del español a una raíz común con el
+// singular. No es un lematizador completo: solo busca que singular y plural
+// coincidan, aunque la raíz no sea una palabra ("clase" y "clases" -> "cla").
+func stem(word string) string {
+	runes := []rune(word)
+	n := len(runes)
+
+	switch {
+	case n > 4 && strings.HasSuffix(word, "ces") && isVowel(runes[n-4]):
+		// luces -> luz, lápices -> lapiz
+		return string(runes[:n-3]) + "z"
+	case n > 4 && strings.HasSuffix(word, "es") && !isVowel(runes[n-3]):
+		// animales -> animal, flores -> flor, países -> pais
+		n -= 2
+	}
+
+	// Las "e" y las "s" tras vocal finales se quitan hasta que no quede
+	// ninguna, para que coincidan los plurales en -s (perros, calles) con su
+	// singular y los singulares en -s (país, interés) con los plurales en -es
+	for n > 3 {
+		if runes[n-1] != 'e' && (runes[n-1] != 's' || !isVowel(runes[n-2])) {
+			break
+		}
+		n--
+	}
+	return string(runes[:n])
+}
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiou", r)
+}
@@ -0,0 +1,41 @@
+package search
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		singular, plural, want string
+	}{
+		{"perro", "perros", "perro"},
+		{"casa", "casas", "casa"},
+		{"calle", "calles", "call"},
+		{"clase", "clases", "cla"},
+		{"animal", "animales", "animal"},
+		{"flor", "flores", "flor"},
+		{"luz", "luces", "luz"},
+		{"lapiz", "lapices", "lapiz"},
+		{"pais", "paises", "pai"},
+		{"interes", "intereses", "inter"},
+		{"autobus", "autobuses", "autobu"},
+		{"mes", "meses", "mes"},
+		{"dulce", "dulces", "dulc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.singular, func(t *testing.T) {
+			if got := stem(tt.singular); got != tt.want {
+				t.Errorf("stem(%q) = %q, want %q", tt.singular, got, tt.want)
+			}
+			if got := stem(tt.plural); got != tt.want {
+				t.Errorf("stem(%q) = %q, want %q", tt.plural, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStemKeepsShortWords(t *testing.T) {
+	for _, word := range []string{"mes", "tos", "gas", "pie"} {
+		if got := stem(word); got != word {
+			t.Errorf("stem(%q) = %q, want it unchanged", word, got)
+		}
+	}
+}
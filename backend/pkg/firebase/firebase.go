@@ -12,17 +12,17 @@ import (
 )
 
 var (
-	app     *firebase.App
-	auth    *auth.Client
-	storage *storage.Client
-	once    sync.Once
+	app           *firebase.App
+	authClient    *auth.Client
+	storageClient *storage.Client
+	once          sync.Once
 )
 
-// InitFirebase initializes Firebase with the service account
-func InitFirebase() error {
+// InitFirebase initializes Firebase with the given service account file
+func InitFirebase(credentialsFile string) error {
 	var err error
 	once.Do(func() {
-		opt := option.WithCredentialsFile("config/serviceAccountKey.json")
+		opt := option.WithCredentialsFile(credentialsFile)
 		app, err = firebase.NewApp(context.Background(), nil, opt)
 		if err != nil {
 			err = fmt.Errorf("error initializing app: %v", err)
@@ -30,14 +30,14 @@ func InitFirebase() error {
 		}
 
 		// Initialize Auth
-		auth, err = app.Auth(context.Background())
+		authClient, err = app.Auth(context.Background())
 		if err != nil {
 			err = fmt.Errorf("error getting Auth client: %v", err)
 			return
 		}
 
 		// Initialize Storage
-		storage, err = app.Storage(context.Background())
+		storageClient, err = app.Storage(context.Background())
 		if err != nil {
 			err = fmt.Errorf("error getting Storage client: %v", err)
 			return
@@ -46,21 +46,17 @@ func InitFirebase() error {
 	return err
 }
 
+// GetApp returns the Firebase App
+func GetApp() *firebase.App {
+	return app
+}
+
 // GetAuthClient returns the Firebase Auth client
 func GetAuthClient() *auth.Client {
-	return auth
+	return authClient
 }
 
 // GetStorageClient returns the Firebase Storage client
 func GetStorageClient() *storage.Client {
-	return storage
-}
-
-// VerifyIDToken verifies the Firebase ID token
-func VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error) {
-	token, err := auth.VerifyIDToken(ctx, idToken)
-	if err != nil {
-		return nil, fmt.Errorf("error verifying ID token: %v", err)
-	}
-	return token, nil
+	return storageClient
 }